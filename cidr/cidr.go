@@ -0,0 +1,105 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package cidr
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/utils"
+)
+
+// Family identifies the IP version of a CIDR range
+type Family int
+
+// This set of constants defines the supported address families
+const (
+	IPv4 Family = 4
+	IPv6 Family = 6
+)
+
+// This set of constants defines how IPv4 addresses are embedded in IPv6 addresses (RFC 4291, IPv4-mapped addresses)
+const (
+	ipv4MappedPrefix     string = "::ffff:"
+	ipv4MappedPrefixBits int    = 96
+	ipv6Bits             int    = 128
+)
+
+// AnyCIDR models a CIDR range of any address family, tagged with its family
+// @field Family Family: The address family of the CIDR range
+// @field IPv4 *ipv4cidr.IPv4CIDR: The parsed CIDR range, if the family is IPv4
+type AnyCIDR struct {
+	Family Family
+	IPv4   *ipv4cidr.IPv4CIDR
+}
+
+// ParseAny detects the address family of a CIDR range and parses it with the matching constructor
+// IPv4-mapped IPv6 CIDR ranges (::ffff:a.b.c.d/N with N >= 96) are parsed as the IPv4 CIDR range a.b.c.d/(N-96)
+// @param IP string: A string representation of CIDR range, e.g. a.b.c.d/e or a.b.c.d for IPv4
+// @param standardize bool: If the IP part of the CIDR range is not the first IP in range, then setting this value to "true" will automatically convert it to the first IP in range. If set to "false", a non-standard CIDR will give an error
+// @returns *AnyCIDR: If the input is valid, returns a pointer to the parsed CIDR range tagged with its family
+// @returns error: If the input is invalid, or of a family that is not supported yet, the appropriate error is returned
+func ParseAny(IP string, standardize bool) (*AnyCIDR, error) {
+
+	// IPv6 addresses always contain a colon, IPv4 addresses never do
+	// IPv4-mapped IPv6 addresses, as reported by dual-stack sockets, are converted back to IPv4
+	if strings.Contains(IP, ":") {
+		mapped, err := unmapIPv4(IP)
+		if err != nil {
+			return nil, err
+		}
+		IP = mapped
+	}
+
+	ipv4, err := ipv4cidr.NewIPv4CIDR(IP, standardize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AnyCIDR{
+		Family: IPv4,
+		IPv4:   ipv4,
+	}, nil
+
+}
+
+// unmapIPv4 converts an IPv4-mapped IPv6 CIDR range into the equivalent IPv4 CIDR range
+// @param IP string: A string representation of an IPv6 CIDR range
+// @returns string: The IPv4 CIDR range in format a.b.c.d/e
+// @returns error: If the CIDR range is not an IPv4-mapped CIDR range with a prefix length of at least 96, the appropriate error is returned
+func unmapIPv4(IP string) (string, error) {
+
+	if len(IP) < len(ipv4MappedPrefix) || !strings.EqualFold(IP[:len(ipv4MappedPrefix)], ipv4MappedPrefix) {
+		return "", utils.NewError(consts.UnsupportedFamilyCode, consts.UnsupportedFamilyError)
+	}
+
+	address := IP[len(ipv4MappedPrefix):]
+	sections := strings.Split(address, "/")
+	if len(sections) == 1 {
+		return address, nil
+	}
+
+	bits, err := strconv.Atoi(sections[1])
+	if err != nil || len(sections) > 2 || bits > ipv6Bits || bits < 0 {
+		return "", utils.NewError(consts.InvalidInputCode, consts.InvalidIPv4CIDRError)
+	}
+
+	// Prefixes shorter than 96 bits extend beyond the IPv4-mapped range, so they are genuine IPv6 CIDR ranges
+	if bits < ipv4MappedPrefixBits {
+		return "", utils.NewError(consts.UnsupportedFamilyCode, consts.UnsupportedFamilyError)
+	}
+
+	return sections[0] + "/" + strconv.Itoa(bits-ipv4MappedPrefixBits), nil
+
+}
+
+// ToString converts the CIDR range into its string representation
+// @returns string: String corresponding to the CIDR range, e.g. a.b.c.d/e for IPv4
+func (a *AnyCIDR) ToString() string {
+
+	return a.IPv4.ToString()
+
+}
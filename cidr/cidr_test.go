@@ -0,0 +1,102 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package cidr
+
+import (
+	"testing"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParseAnyIPv4 parses IPv4 CIDR ranges without specifying the family
+// Success Metric: The CIDR range is parsed and tagged as IPv4
+func TestParseAnyIPv4(t *testing.T) {
+
+	CIDR, err := ParseAny("10.10.0.1/26", true)
+	assert.Nil(t, err, "10.10.0.1/26 is a valid IPv4 CIDR block, object should be created.")
+
+	assert.Equal(t, IPv4, CIDR.Family)
+	assert.Equal(t, "10.10.0.0/26", CIDR.IPv4.ToString())
+	assert.Equal(t, "10.10.0.0/26", CIDR.ToString())
+
+}
+
+// TestParseAnyIPv6 parses an IPv6 CIDR range
+// Success Metric: Throw an error saying the family is not supported yet
+func TestParseAnyIPv6(t *testing.T) {
+
+	_, err := ParseAny("2001:db8::/32", false)
+	if assert.Error(t, err, "IPv6 is not supported yet. An error should be thrown.") {
+
+		assert.Equal(t, consts.UnsupportedFamilyError, err.Error(), "Error thrown should be: \"%s\"", consts.UnsupportedFamilyError)
+
+	}
+
+}
+
+// TestParseAnyInvalidInput parses an invalid CIDR range
+// Success Metric: Throw an error saying the CIDR range is invalid
+func TestParseAnyInvalidInput(t *testing.T) {
+
+	_, err := ParseAny("10.10.0.0/33", false)
+	if assert.Error(t, err, "10.10.0.0/33 is an invalid CIDR block. An error should be thrown.") {
+
+		assert.Equal(t, consts.InvalidIPv4CIDRError, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidIPv4CIDRError)
+
+	}
+
+}
+
+// TestParseAnyIPv4Mapped parses IPv4-mapped IPv6 CIDR ranges
+// Success Metric: The CIDR range is converted to IPv4, with the prefix length reduced by 96
+func TestParseAnyIPv4Mapped(t *testing.T) {
+
+	inputs := map[string]string{
+		"::ffff:10.10.0.1/122": "10.10.0.0/26",
+		"::FFFF:192.168.1.7":   "192.168.1.7/32",
+		"::ffff:0.0.0.0/96":    "0.0.0.0/0",
+	}
+	for input, expected := range inputs {
+
+		CIDR, err := ParseAny(input, true)
+		assert.Nil(t, err, "%s is a valid IPv4-mapped CIDR block, object should be created.", input)
+		assert.Equal(t, IPv4, CIDR.Family)
+		assert.Equal(t, expected, CIDR.ToString())
+
+	}
+
+}
+
+// TestParseAnyIPv4MappedInvalidInput parses IPv4-mapped IPv6 CIDR ranges that cannot be converted to IPv4
+// Success Metric: Prefixes shorter than 96 are not supported yet, and invalid ranges are rejected
+func TestParseAnyIPv4MappedInvalidInput(t *testing.T) {
+
+	_, err := ParseAny("::ffff:10.10.0.0/95", true)
+	if assert.Error(t, err, "A /95 extends beyond the IPv4-mapped range. An error should be thrown.") {
+
+		assert.Equal(t, consts.UnsupportedFamilyError, err.Error(), "Error thrown should be: \"%s\"", consts.UnsupportedFamilyError)
+
+	}
+
+	for _, input := range []string{"::ffff:10.10.0.0/129", "::ffff:10.10.0.0/x", "::ffff:10.10.0/120"} {
+
+		_, err = ParseAny(input, true)
+		if assert.Error(t, err, "%s is an invalid CIDR block. An error should be thrown.", input) {
+
+			assert.Equal(t, consts.InvalidIPv4CIDRError, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidIPv4CIDRError)
+
+		}
+
+	}
+
+	_, err = ParseAny("::ffff:10.10.0.1/122", false)
+	if assert.Error(t, err, "10.10.0.1/26 is not standardized. An error should be thrown.") {
+
+		assert.Equal(t, consts.NonStandardizedIPError, err.Error(), "Error thrown should be: \"%s\"", consts.NonStandardizedIPError)
+
+	}
+
+}
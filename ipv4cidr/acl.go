@@ -0,0 +1,328 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"sort"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/utils"
+)
+
+// ACLRule models a single rule of an ordered access control list, where the first rule matching an IP address decides its action
+// @field Action string: The action of the rule, either "allow" or "deny"
+// @field CIDR *IPv4CIDR: The CIDR range matched by the rule
+type ACLRule struct {
+	Action string
+	CIDR   *IPv4CIDR
+}
+
+// EliminatedRule reports a rule of the input list that is not in the optimized list
+// @field Rule ACLRule: The eliminated rule
+// @field Reason string: Why the rule was eliminated, either "shadowed" (no IP address reaches it) or "merged" (it was combined with adjacent rules)
+// @field MergedInto *IPv4CIDR: For merged rules, the CIDR range of the rule replacing it. Nil for shadowed rules
+type EliminatedRule struct {
+	Rule       ACLRule
+	Reason     string
+	MergedInto *IPv4CIDR
+}
+
+// ACLOptimization is the result of optimizing an ACL
+// @field Rules []ACLRule: The optimized list of rules, matching every IP address with the same action as the input list
+// @field Eliminated []EliminatedRule: The rules of the input list that were eliminated, in input order
+type ACLOptimization struct {
+	Rules      []ACLRule
+	Eliminated []EliminatedRule
+}
+
+// aclEntry tracks a rule of the list being optimized, along with the input rules it replaces
+// @field rule ACLRule: The current rule
+// @field origins []int: Indices of the input rules merged into this rule
+type aclEntry struct {
+	rule    ACLRule
+	origins []int
+}
+
+// OptimizeACL produces a shorter ACL that is equivalent to an ordered list of rules: every IP address is matched with the same action, and unmatched addresses stay unmatched
+// Rules that no IP address can reach because earlier rules cover them are removed, and pairs of sibling rules with the same action are merged into their parent CIDR range when no rule in between decides differently for them
+// @input rules []ACLRule: The ordered list of rules. It is not modified
+// @returns *ACLOptimization: The optimized list of rules and the report of eliminated rules
+// @returns error: If a rule has an invalid action or no CIDR range, an error is returned
+func OptimizeACL(rules []ACLRule) (*ACLOptimization, error) {
+
+	if err := ValidateACL(rules); err != nil {
+		return nil, err
+	}
+
+	entries := make([]aclEntry, 0, len(rules))
+	for n, rule := range rules {
+		entries = append(entries, aclEntry{rule: rule, origins: []int{n}})
+	}
+
+	result := &ACLOptimization{
+		Eliminated: make([]EliminatedRule, 0),
+	}
+	reasons := make(map[int]EliminatedRule)
+
+	// Removing a rule can enable a merge and vice versa, so repeat until the list is stable
+	for changed := true; changed; {
+		changed = false
+
+		if remaining, shadowed := removeShadowedRules(entries); len(shadowed) > 0 {
+			for _, entry := range shadowed {
+				for _, origin := range entry.origins {
+					reasons[origin] = EliminatedRule{Rule: rules[origin], Reason: consts.ACLShadowed}
+				}
+			}
+			entries = remaining
+			changed = true
+		}
+
+		if merged, ok := mergeSiblingRules(entries); ok {
+			entries = merged
+			changed = true
+		}
+	}
+
+	result.Rules = make([]ACLRule, 0, len(entries))
+	for _, entry := range entries {
+		result.Rules = append(result.Rules, entry.rule)
+		if len(entry.origins) > 1 {
+			for _, origin := range entry.origins {
+				reasons[origin] = EliminatedRule{Rule: rules[origin], Reason: consts.ACLMerged, MergedInto: entry.rule.CIDR}
+			}
+		}
+	}
+
+	for n := range rules {
+		if reason, ok := reasons[n]; ok {
+			result.Eliminated = append(result.Eliminated, reason)
+		}
+	}
+
+	return result, nil
+
+}
+
+// ValidateACL checks that every rule of an ACL has a valid action and a CIDR range, e.g. before rendering it for another system
+// @input rules []ACLRule: The ordered list of rules
+// @returns error: If a rule has an invalid action or no CIDR range, the appropriate error is returned. Else, return value is nil
+func ValidateACL(rules []ACLRule) error {
+
+	for _, rule := range rules {
+		if rule.Action != consts.ACLAllow && rule.Action != consts.ACLDeny {
+			return utils.NewError(consts.InvalidACLCode, consts.InvalidACLActionError)
+		}
+		if rule.CIDR == nil {
+			return utils.NewError(consts.InvalidACLCode, consts.MissingACLCIDRError)
+		}
+	}
+
+	return nil
+
+}
+
+// removeShadowedRules removes the rules whose CIDR range is entirely covered by earlier rules
+// @input entries []aclEntry: The ordered list of rules
+// @returns []aclEntry: The remaining rules
+// @returns []aclEntry: The removed rules
+func removeShadowedRules(entries []aclEntry) ([]aclEntry, []aclEntry) {
+
+	remaining := make([]aclEntry, 0, len(entries))
+	shadowed := make([]aclEntry, 0)
+	earlier := make([]*IPv4CIDR, 0, len(entries))
+
+	for _, entry := range entries {
+		if coveredAddresses(entry.rule.CIDR, earlier) == entry.rule.CIDR.size() {
+			shadowed = append(shadowed, entry)
+			continue
+		}
+		remaining = append(remaining, entry)
+		earlier = append(earlier, entry.rule.CIDR)
+	}
+
+	return remaining, shadowed
+
+}
+
+// mergeSiblingRules merges every pair of rules that can be replaced by a single rule for their parent CIDR range, in one sweep from the longest mask up
+// Two rules can be merged if they have the same action and are the two halves of the same parent, and no rule between them with a different action overlaps the later one, since the merged rule takes the position of the earlier one
+// @input entries []aclEntry: The ordered list of rules. It is not modified
+// @returns []aclEntry: The list with the pairs merged
+// @returns bool: True if a pair was merged
+func mergeSiblingRules(entries []aclEntry) ([]aclEntry, bool) {
+
+	entries = append(make([]aclEntry, 0, len(entries)), entries...)
+
+	// Rules are bucketed by mask, so that sorting a bucket by action and IP makes siblings adjacent.
+	// Merged rules are added to the bucket of their parent mask, which is processed next, so that they can be merged again in the same sweep
+	var byMask [consts.MaxBits + 1][]int
+	for n, entry := range entries {
+		byMask[entry.rule.CIDR.mask] = append(byMask[entry.rule.CIDR.mask], n)
+	}
+
+	removed := make([]bool, len(entries))
+	merged := false
+
+	for mask := consts.MaxBits; mask > 0; mask-- {
+
+		bucket := byMask[mask]
+		sort.Slice(bucket, func(x, y int) bool {
+			a, b := entries[bucket[x]].rule, entries[bucket[y]].rule
+			if a.Action != b.Action {
+				return a.Action < b.Action
+			}
+			if a.CIDR.ip != b.CIDR.ip {
+				return a.CIDR.ip < b.CIDR.ip
+			}
+			return bucket[x] < bucket[y]
+		})
+
+		for n := 0; n+1 < len(bucket); n++ {
+
+			// The lower half comes first in the bucket, so the two rules are siblings if their IPs only differ by the size of the range
+			lower, upper := entries[bucket[n]].rule, entries[bucket[n+1]].rule
+			if lower.Action != upper.Action || lower.CIDR.ip^upper.CIDR.ip != lower.CIDR.rangeLength {
+				continue
+			}
+
+			earlier, later := bucket[n], bucket[n+1]
+			if later < earlier {
+				earlier, later = later, earlier
+			}
+
+			blocked := false
+			for k := earlier + 1; k < later; k++ {
+				if !removed[k] && entries[k].rule.Action != upper.Action && intersect(entries[k].rule.CIDR, entries[later].rule.CIDR) != nil {
+					blocked = true
+					break
+				}
+			}
+			if blocked {
+				continue
+			}
+
+			entries[earlier] = aclEntry{
+				rule: ACLRule{
+					Action: lower.Action,
+					CIDR:   fromIPAndMask(lower.CIDR.ip, mask-1),
+				},
+				origins: append(append([]int{}, entries[earlier].origins...), entries[later].origins...),
+			}
+			removed[later] = true
+			byMask[mask-1] = append(byMask[mask-1], earlier)
+			merged = true
+
+			// Both rules of the pair are used
+			n++
+
+		}
+
+	}
+
+	result := make([]aclEntry, 0, len(entries))
+	for n, entry := range entries {
+		if !removed[n] {
+			result = append(result, entry)
+		}
+	}
+
+	return result, merged
+
+}
+
+// ACLFinding reports a rule of an ACL that does not affect the outcome of the list
+// @field Index int: The position of the rule in the list
+// @field Rule ACLRule: The rule
+// @field Kind string: Either "shadowed" (earlier rules cover the whole CIDR range of the rule, so no IP address reaches it) or "redundant" (later rules with the same action would match every IP address reaching it)
+// @field Conflicting bool: For shadowed rules, true if one of the earlier rules covering it has a different action, which usually indicates a mistake
+// @field By []int: The positions of the rules causing the finding: the overlapping earlier rules for shadowed rules, or the later rules taking over for redundant rules
+type ACLFinding struct {
+	Index       int
+	Rule        ACLRule
+	Kind        string
+	Conflicting bool
+	By          []int
+}
+
+// AnalyzeACL audits an ordered list of rules and reports the rules that are shadowed by earlier rules or redundant with later ones
+// Each rule is checked against the list as given, so removing several reported rules at once may change the outcome of the list; use OptimizeACL to shorten the list safely
+// @input rules []ACLRule: The ordered list of rules
+// @returns []ACLFinding: The findings, in order of rule position
+// @returns error: If a rule has an invalid action or no CIDR range, an error is returned
+func AnalyzeACL(rules []ACLRule) ([]ACLFinding, error) {
+
+	if err := ValidateACL(rules); err != nil {
+		return nil, err
+	}
+
+	findings := make([]ACLFinding, 0)
+	for n, rule := range rules {
+
+		// The addresses reaching the rule are those of its CIDR range not matched by an earlier rule
+		reached := toRanges([]*IPv4CIDR{rule.CIDR})
+		overlapping := make([]int, 0)
+		conflicting := false
+		for m := 0; m < n; m++ {
+			if intersect(rules[m].CIDR, rule.CIDR) == nil {
+				continue
+			}
+			reached = subtractRanges(reached, toRanges([]*IPv4CIDR{rules[m].CIDR}))
+			overlapping = append(overlapping, m)
+			conflicting = conflicting || rules[m].Action != rule.Action
+		}
+
+		if len(reached) == 0 {
+			findings = append(findings, ACLFinding{
+				Index:       n,
+				Rule:        rule,
+				Kind:        consts.ACLShadowed,
+				Conflicting: conflicting,
+				By:          overlapping,
+			})
+			continue
+		}
+
+		// Without the rule, the addresses reaching it would fall through to the following rules, which must all decide the same way
+		takenOver := make([]int, 0)
+		for m := n + 1; m < len(rules) && len(reached) > 0; m++ {
+			remaining := subtractRanges(reached, toRanges([]*IPv4CIDR{rules[m].CIDR}))
+			if rangesSize(remaining) == rangesSize(reached) {
+				continue
+			}
+			if rules[m].Action != rule.Action {
+				break
+			}
+			reached = remaining
+			takenOver = append(takenOver, m)
+		}
+
+		if len(reached) == 0 {
+			findings = append(findings, ACLFinding{
+				Index: n,
+				Rule:  rule,
+				Kind:  consts.ACLRedundant,
+				By:    takenOver,
+			})
+		}
+
+	}
+
+	return findings, nil
+
+}
+
+// rangesSize counts the number of IP addresses in a list of disjoint IP ranges
+// @input ranges []ipRange: The IP ranges
+// @returns uint64: Number of IP addresses in the ranges
+func rangesSize(ranges []ipRange) uint64 {
+
+	size := uint64(0)
+	for _, r := range ranges {
+		size += r.end - r.start + 1
+	}
+
+	return size
+
+}
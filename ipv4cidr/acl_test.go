@@ -0,0 +1,214 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// parseRules builds a list of ACL rules from "action cidr" strings
+func parseRules(rules ...string) []ACLRule {
+
+	result := make([]ACLRule, 0, len(rules))
+	for _, rule := range rules {
+		fields := strings.Fields(rule)
+		result = append(result, ACLRule{Action: fields[0], CIDR: mustParse(fields[1])})
+	}
+
+	return result
+
+}
+
+// ruleStrings converts a list of ACL rules to "action cidr" strings for comparison in tests
+func ruleStrings(rules []ACLRule) []string {
+
+	result := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		result = append(result, rule.Action+" "+rule.CIDR.ToString())
+	}
+
+	return result
+
+}
+
+// TestOptimizeACLShadowed removes rules covered by earlier rules
+// Success Metric: Shadowed rules are removed and reported, whatever their action
+func TestOptimizeACLShadowed(t *testing.T) {
+
+	rules := parseRules(
+		"deny 10.0.0.0/8",
+		"allow 10.1.0.0/16",
+		"allow 192.168.0.0/25",
+		"deny 192.168.0.128/25",
+		"deny 192.168.0.64/26",
+		"allow 172.16.0.0/12",
+	)
+
+	optimization, err := OptimizeACL(rules)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"deny 10.0.0.0/8", "allow 192.168.0.0/25", "deny 192.168.0.128/25", "allow 172.16.0.0/12"}, ruleStrings(optimization.Rules))
+
+	if assert.Len(t, optimization.Eliminated, 2) {
+		assert.Equal(t, "allow 10.1.0.0/16", ruleStrings([]ACLRule{optimization.Eliminated[0].Rule})[0])
+		assert.Equal(t, consts.ACLShadowed, optimization.Eliminated[0].Reason)
+		assert.Nil(t, optimization.Eliminated[0].MergedInto)
+		assert.Equal(t, "deny 192.168.0.64/26", ruleStrings([]ACLRule{optimization.Eliminated[1].Rule})[0])
+	}
+
+}
+
+// TestOptimizeACLMerged merges sibling rules with the same action, repeatedly
+// Success Metric: Four consecutive /26 rules collapse into a /24, and a blocking rule in between prevents a merge
+func TestOptimizeACLMerged(t *testing.T) {
+
+	rules := parseRules(
+		"allow 10.0.0.0/26",
+		"allow 10.0.0.64/26",
+		"allow 10.0.0.192/26",
+		"allow 10.0.0.128/26",
+		"allow 10.0.1.0/25",
+		"deny 10.0.1.128/26",
+		"allow 10.0.1.128/25",
+	)
+
+	optimization, err := OptimizeACL(rules)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"allow 10.0.0.0/24", "allow 10.0.1.0/25", "deny 10.0.1.128/26", "allow 10.0.1.128/25"}, ruleStrings(optimization.Rules))
+
+	if assert.Len(t, optimization.Eliminated, 4) {
+		for _, eliminated := range optimization.Eliminated {
+			assert.Equal(t, consts.ACLMerged, eliminated.Reason)
+			assert.Equal(t, "10.0.0.0/24", eliminated.MergedInto.ToString())
+		}
+	}
+
+}
+
+// TestOptimizeACLMergedLargeList merges thousands of host rules listed in reverse order
+// Success Metric: The host rules collapse into a single /20, and the rule after them is kept
+func TestOptimizeACLMergedLargeList(t *testing.T) {
+
+	parent := mustParse("10.0.0.0/20")
+	hosts, _ := parent.SplitToMask(32)
+
+	rules := make([]ACLRule, 0, len(hosts)+1)
+	for n := len(hosts) - 1; n >= 0; n-- {
+		rules = append(rules, ACLRule{Action: consts.ACLAllow, CIDR: hosts[n]})
+	}
+	rules = append(rules, ACLRule{Action: consts.ACLDeny, CIDR: mustParse("0.0.0.0/0")})
+
+	optimization, err := OptimizeACL(rules)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"allow 10.0.0.0/20", "deny 0.0.0.0/0"}, ruleStrings(optimization.Rules))
+	assert.Len(t, optimization.Eliminated, len(hosts))
+
+}
+
+// TestOptimizeACLInvalidAction checks that rules with invalid actions are rejected
+// Success Metric: An error is returned with the appropriate message
+func TestOptimizeACLInvalidAction(t *testing.T) {
+
+	_, err := OptimizeACL([]ACLRule{{Action: "permit", CIDR: mustParse("10.0.0.0/8")}})
+	if assert.Error(t, err, "permit is an invalid action. An error should be thrown.") {
+
+		assert.Equal(t, consts.InvalidACLActionError, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidACLActionError)
+
+	}
+
+}
+
+// TestACLMissingCIDR checks that rules without a CIDR range are rejected instead of panicking
+// Success Metric: An error is returned with the appropriate message and code, by both OptimizeACL and AnalyzeACL
+func TestACLMissingCIDR(t *testing.T) {
+
+	rules := []ACLRule{{Action: consts.ACLAllow, CIDR: mustParse("10.0.0.0/8")}, {Action: consts.ACLDeny}}
+
+	_, err := OptimizeACL(rules)
+	if assert.Error(t, err, "A rule has no CIDR range. An error should be thrown.") {
+
+		assert.Equal(t, consts.MissingACLCIDRError, err.Error(), "Error thrown should be: \"%s\"", consts.MissingACLCIDRError)
+		assert.Equal(t, consts.InvalidACLCode, GetErrorCode(err))
+
+	}
+
+	_, err = AnalyzeACL(rules)
+	if assert.Error(t, err, "A rule has no CIDR range. An error should be thrown.") {
+
+		assert.Equal(t, consts.MissingACLCIDRError, err.Error(), "Error thrown should be: \"%s\"", consts.MissingACLCIDRError)
+
+	}
+
+}
+
+// TestAnalyzeACL reports shadowed and redundant rules
+// Success Metric: Each finding has the right kind, conflict flag and causing rules
+func TestAnalyzeACL(t *testing.T) {
+
+	rules := parseRules(
+		"deny 10.0.0.0/8",      // 0
+		"allow 10.1.0.0/16",    // 1: shadowed by 0, conflicting
+		"deny 10.2.0.0/16",     // 2: shadowed by 0
+		"allow 192.168.1.0/24", // 3: redundant with 5
+		"deny 192.168.2.0/24",  // 4
+		"allow 192.168.0.0/16", // 5
+		"deny 172.16.0.0/24",   // 6: not redundant, 7 decides differently
+		"allow 172.16.0.0/12",  // 7
+		"allow 100.64.0.0/10",  // 8: not redundant, nothing matches after it
+	)
+
+	findings, err := AnalyzeACL(rules)
+	assert.Nil(t, err)
+
+	if assert.Len(t, findings, 3) {
+
+		assert.Equal(t, 1, findings[0].Index)
+		assert.Equal(t, consts.ACLShadowed, findings[0].Kind)
+		assert.True(t, findings[0].Conflicting)
+		assert.Equal(t, []int{0}, findings[0].By)
+
+		assert.Equal(t, 2, findings[1].Index)
+		assert.Equal(t, consts.ACLShadowed, findings[1].Kind)
+		assert.False(t, findings[1].Conflicting)
+
+		assert.Equal(t, 3, findings[2].Index)
+		assert.Equal(t, consts.ACLRedundant, findings[2].Kind)
+		assert.Equal(t, []int{5}, findings[2].By)
+
+	}
+
+	_, err = AnalyzeACL([]ACLRule{{Action: "", CIDR: mustParse("10.0.0.0/8")}})
+	if assert.Error(t, err, "An empty action is invalid. An error should be thrown.") {
+
+		assert.Equal(t, consts.InvalidACLActionError, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidACLActionError)
+
+	}
+
+}
+
+// BenchmarkOptimizeACL optimizes an ACL of a thousand alternating allow and deny rules
+func BenchmarkOptimizeACL(b *testing.B) {
+
+	parent := mustParse("10.0.0.0/14")
+	subnets, _ := parent.SplitToMask(24)
+
+	rules := make([]ACLRule, 0, len(subnets))
+	for n, subnet := range subnets {
+		action := consts.ACLAllow
+		if n%3 == 0 {
+			action = consts.ACLDeny
+		}
+		rules = append(rules, ACLRule{Action: action, CIDR: subnet})
+	}
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		OptimizeACL(rules)
+	}
+
+}
@@ -0,0 +1,49 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/utils"
+)
+
+// IsAlignedTo checks if the CIDR range starts on a block boundary of a given size
+// @input mask uint8: The mask of the block size to check against, e.g. 24 for a /24 boundary
+// @returns bool: True if the first IP of the CIDR range is the first IP of a block of that mask. False if the mask is larger than 32
+func (i *IPv4CIDR) IsAlignedTo(mask uint8) bool {
+
+	if mask > consts.MaxBits {
+		return false
+	}
+
+	return utils.Standardize(i.ip, utils.GetNetmask(mask)) == i.ip
+
+}
+
+// NextAlignedBlock finds the first block of a given size that starts at or after an IP address, e.g. to find where the next /24 can be allocated
+// @input after string: The IP address in format a.b.c.d
+// @input mask uint8: The mask of the block (0-32)
+// @returns *IPv4CIDR: The block, starting at the IP address if it is aligned, else at the next boundary
+// @returns error: If the IP address or the mask is invalid, or no block of that size starts at or after the IP address, the appropriate error is returned
+func NextAlignedBlock(after string, mask uint8) (*IPv4CIDR, error) {
+
+	ip, err := utils.ParseIPUint32(after)
+	if err != nil {
+		return nil, err
+	}
+
+	if mask > consts.MaxBits {
+		return nil, utils.NewError(consts.InvalidMaskCode, consts.InvalidMaskError)
+	}
+
+	// Round the IP up to the next multiple of the block size, computed in 64 bits to detect running past the end of the IPv4 space
+	size := utils.GetCIDRRangeLength64(mask)
+	start := (uint64(ip) + size - 1) / size * size
+	if start > uint64(consts.MaxUInt32) {
+		return nil, utils.NewError(consts.OutOfRangeCode, consts.NoAlignedBlockError)
+	}
+
+	return fromIPAndMask(uint32(start), mask), nil
+
+}
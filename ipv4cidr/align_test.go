@@ -0,0 +1,82 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"testing"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestIsAlignedTo checks CIDR ranges against block boundaries of various sizes
+// Success Metric: Only CIDR ranges starting on a boundary of the given size are aligned
+func TestIsAlignedTo(t *testing.T) {
+
+	CIDR, _ := NewIPv4CIDR("10.0.4.0/24", false)
+
+	assert.True(t, CIDR.IsAlignedTo(24), "10.0.4.0 is on a /24 boundary")
+	assert.True(t, CIDR.IsAlignedTo(22), "10.0.4.0 is on a /22 boundary")
+	assert.False(t, CIDR.IsAlignedTo(21), "10.0.4.0 is not on a /21 boundary")
+	assert.True(t, CIDR.IsAlignedTo(32), "Every IP is on a /32 boundary")
+	assert.False(t, CIDR.IsAlignedTo(33), "33 is an invalid mask")
+
+	CIDR, _ = NewIPv4CIDR("0.0.0.0/0", false)
+	assert.True(t, CIDR.IsAlignedTo(0), "0.0.0.0 is on a /0 boundary")
+
+}
+
+// TestNextAlignedBlock finds the next block of a given size at or after IPs
+// Success Metric: Aligned IPs start the block themselves, others are rounded up to the next boundary
+func TestNextAlignedBlock(t *testing.T) {
+
+	block, err := NextAlignedBlock("10.0.4.0", 24)
+	assert.Nil(t, err)
+	assert.Equal(t, "10.0.4.0/24", block.ToString())
+
+	block, err = NextAlignedBlock("10.0.4.1", 24)
+	assert.Nil(t, err)
+	assert.Equal(t, "10.0.5.0/24", block.ToString())
+
+	block, err = NextAlignedBlock("10.0.5.1", 22)
+	assert.Nil(t, err)
+	assert.Equal(t, "10.0.8.0/22", block.ToString())
+
+	block, err = NextAlignedBlock("255.255.255.255", 32)
+	assert.Nil(t, err)
+	assert.Equal(t, "255.255.255.255/32", block.ToString())
+
+	block, err = NextAlignedBlock("0.0.0.0", 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "0.0.0.0/0", block.ToString())
+
+}
+
+// TestNextAlignedBlockErrors checks invalid inputs and blocks past the end of the IPv4 space
+// Success Metric: Errors are returned with the appropriate messages
+func TestNextAlignedBlockErrors(t *testing.T) {
+
+	_, err := NextAlignedBlock("255.255.255.1", 24)
+	if assert.Error(t, err, "No /24 starts after 255.255.255.1. An error should be thrown.") {
+
+		assert.Equal(t, consts.NoAlignedBlockError, err.Error(), "Error thrown should be: \"%s\"", consts.NoAlignedBlockError)
+
+	}
+
+	_, err = NextAlignedBlock("10.0.0.0", 33)
+	if assert.Error(t, err, "33 is an invalid mask. An error should be thrown.") {
+
+		assert.Equal(t, consts.InvalidMaskError, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidMaskError)
+
+	}
+
+	_, err = NextAlignedBlock("10.0.0.0/24", 24)
+	if assert.Error(t, err, "10.0.0.0/24 is not an IP address. An error should be thrown.") {
+
+		assert.Equal(t, consts.InvalidIPv4Error, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidIPv4Error)
+
+	}
+
+}
@@ -0,0 +1,62 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"fmt"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+)
+
+// AzureDelegationMaxMask maps Azure subnet delegations and dedicated subnet names to the longest mask (i.e. the smallest subnet) they support
+// Callers can add entries for services not listed here before creating rules
+var AzureDelegationMaxMask = map[string]uint8{
+	consts.AzureAppServiceDelegation:         28,
+	consts.AzureSQLManagedInstanceDelegation: 27,
+	consts.AzureNetAppFilesDelegation:        28,
+	consts.AzureDNSResolverDelegation:        28,
+	consts.AzureGatewaySubnet:                29,
+	consts.AzureFirewallSubnet:               26,
+	consts.AzureFirewallManagementSubnet:     26,
+	consts.AzureBastionSubnet:                26,
+	consts.AzureRouteServerSubnet:            27,
+}
+
+// azureDelegation is the rule returned by AzureDelegation
+type azureDelegation struct {
+	service string
+}
+
+// AzureDelegation creates a rule requiring the CIDR range to be large enough for the given Azure subnet delegation or dedicated subnet
+// @param service string: The delegation (e.g. Microsoft.Sql/managedInstances) or dedicated subnet name (e.g. AzureFirewallSubnet)
+// @returns Rule: The new rule
+func AzureDelegation(service string) Rule {
+
+	return &azureDelegation{service: service}
+
+}
+
+// Check evaluates the rule against a CIDR range
+// @input cidr *IPv4CIDR: The CIDR range to check
+// @returns *Violation: If the CIDR range is too small for the service, or the service is unknown, the violation is returned. Else, return value is nil
+func (r *azureDelegation) Check(cidr *IPv4CIDR) *Violation {
+
+	maxMask, ok := AzureDelegationMaxMask[r.service]
+	if !ok {
+		return &Violation{
+			Rule:    consts.AzureDelegationRule,
+			Message: fmt.Sprintf(consts.AzureDelegationUnknownViolation, r.service),
+		}
+	}
+
+	if cidr.mask <= maxMask {
+		return nil
+	}
+
+	return &Violation{
+		Rule:    consts.AzureDelegationRule,
+		Message: fmt.Sprintf(consts.AzureDelegationTooSmallViolation, cidr.ToString(), cidr.mask, r.service, maxMask),
+	}
+
+}
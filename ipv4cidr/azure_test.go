@@ -0,0 +1,40 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"testing"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAzureDelegation checks subnets against the size requirements of Azure delegations
+// Success Metric: Subnets that are large enough pass, smaller subnets and unknown services are reported
+func TestAzureDelegation(t *testing.T) {
+
+	validator := NewValidator(AzureDelegation(consts.AzureSQLManagedInstanceDelegation))
+
+	CIDR, _ := NewIPv4CIDR("10.0.0.0/27", false)
+	assert.Empty(t, validator.Validate(CIDR), "A /27 is large enough for SQL Managed Instance.")
+
+	CIDR, _ = NewIPv4CIDR("10.0.0.0/28", false)
+	violations := validator.Validate(CIDR)
+	if assert.Len(t, violations, 1) {
+
+		assert.Equal(t, consts.AzureDelegationRule, violations[0].Rule)
+		assert.Equal(t, "10.0.0.0/28 is a /28, but Microsoft.Sql/managedInstances requires a subnet of at least /27", violations[0].Message)
+
+	}
+
+	validator = NewValidator(AzureDelegation("Microsoft.Contoso/widgets"))
+	violations = validator.Validate(CIDR)
+	if assert.Len(t, violations, 1) {
+
+		assert.Equal(t, "No Azure subnet size requirement is known for \"Microsoft.Contoso/widgets\"", violations[0].Message)
+
+	}
+
+}
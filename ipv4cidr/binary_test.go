@@ -0,0 +1,188 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestBinaryRoundTrip writes a list of CIDR ranges in the binary format and reads it back
+// Success Metric: The same list is read back, in the same order
+func TestBinaryRoundTrip(t *testing.T) {
+
+	cidrs := parseAll(t, "10.0.0.0/24", "192.168.0.0/16", "10.0.1.0/24", "0.0.0.0/0", "255.255.255.255")
+
+	var buffer bytes.Buffer
+	err := WriteBinary(&buffer, cidrs)
+	assert.Nil(t, err, "The list should be written.")
+
+	decoded, err := ReadBinary(&buffer)
+	assert.Nil(t, err, "The list should be read back.")
+	assert.Equal(t, toStrings(cidrs), toStrings(decoded))
+
+}
+
+// TestBinaryCompactness writes a large sorted list of CIDR ranges in the binary format
+// Success Metric: After the first range, the encoding takes 3 bytes per range, far smaller than the string representation
+func TestBinaryCompactness(t *testing.T) {
+
+	cidrs := make([]*IPv4CIDR, 0, 4096)
+	for n := 0; n < 4096; n++ {
+		cidr, _ := NewIPv4CIDR(fmt.Sprintf("10.%d.%d.0/24", n/256, n%256), false)
+		cidrs = append(cidrs, cidr)
+	}
+
+	var buffer bytes.Buffer
+	WriteBinary(&buffer, cidrs)
+
+	// The first range is a delta from 0.0.0.0, which takes 5 bytes plus the mask
+	assert.Equal(t, len(consts.BinaryMagic)+1+6+3*4095, buffer.Len())
+
+}
+
+// TestBinaryEmptyList writes and reads an empty list, and reads an empty stream
+// Success Metric: Only the header is written and an empty list is read back, while an empty stream is rejected as truncated
+func TestBinaryEmptyList(t *testing.T) {
+
+	var buffer bytes.Buffer
+	err := WriteBinary(&buffer, nil)
+	assert.Nil(t, err, "The empty list should be written.")
+	assert.Equal(t, consts.BinaryMagic+string([]byte{consts.BinaryVersion}), buffer.String(), "Only the header should be written.")
+
+	decoded, err := ReadBinary(&buffer)
+	assert.Nil(t, err, "A header alone is a valid empty list.")
+	assert.Empty(t, decoded)
+
+	_, err = ReadBinary(bytes.NewReader(nil))
+	if assert.Error(t, err, "An empty stream has no header. An error should be thrown.") {
+
+		assert.Equal(t, consts.InvalidBinaryFormatError, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidBinaryFormatError)
+
+	}
+
+}
+
+// TestBinaryEncodeNil writes a nil CIDR range
+// Success Metric: Throw an error saying the CIDR range should not be nil instead of panicking
+func TestBinaryEncodeNil(t *testing.T) {
+
+	var buffer bytes.Buffer
+	err := NewEncoder(&buffer).Encode(nil)
+	if assert.Error(t, err, "The CIDR range is nil. An error should be thrown.") {
+
+		assert.Equal(t, consts.NilCIDRError, err.Error(), "Error thrown should be: \"%s\"", consts.NilCIDRError)
+		assert.Equal(t, consts.InvalidInputCode, GetErrorCode(err))
+
+	}
+
+	err = WriteBinary(&buffer, append(parseAll(t, "10.0.0.0/8"), nil))
+	assert.Error(t, err, "A CIDR range of the list is nil. An error should be thrown.")
+
+}
+
+// TestBinaryNotStandardized reads a CIDR range whose IP has host bits set
+// Success Metric: Throw an error saying the CIDR range is not standardized instead of silently standardizing it
+func TestBinaryNotStandardized(t *testing.T) {
+
+	// Delta 2 (zigzag 4) with mask /24, i.e. 0.0.0.2/24
+	_, err := ReadBinary(bytes.NewReader([]byte("CIDR\x01\x04\x18")))
+	if assert.Error(t, err, "0.0.0.2/24 is not standardized. An error should be thrown.") {
+
+		assert.Equal(t, consts.NonStandardizedIPError, err.Error(), "Error thrown should be: \"%s\"", consts.NonStandardizedIPError)
+		assert.Equal(t, consts.NotStandardizedCode, GetErrorCode(err))
+
+	}
+
+}
+
+// TestBinaryInvalidData reads data that is not in the binary format
+// Success Metric: Throw an error saying the binary data is invalid
+func TestBinaryInvalidData(t *testing.T) {
+
+	testInputs := [][]byte{
+		[]byte("JSON"),
+		[]byte("CIDR\x02"),
+		[]byte("CIDR\x01\x14"),
+		[]byte("CIDR\x01\x14\x21"),
+		[]byte("CIDR\x01\x01\x20"),
+	}
+
+	for _, input := range testInputs {
+
+		_, err := ReadBinary(bytes.NewReader(input))
+		if assert.Error(t, err, "%q is not valid binary data. An error should be thrown.", input) {
+
+			assert.Equal(t, consts.InvalidBinaryFormatError, err.Error(), "For input %q, Error thrown should be: \"%s\"", input, consts.InvalidBinaryFormatError)
+
+		}
+
+	}
+
+}
+
+// failingReader is a stream whose reads always fail, e.g. after a connection reset
+type failingReader struct{}
+
+// Read fails
+func (failingReader) Read(p []byte) (int, error) {
+
+	return 0, errors.New("connection reset")
+
+}
+
+// TestBinaryInvalidDataCode reads an over-long varint and a failing stream
+// Success Metric: Every decoding error carries the binary format error code
+func TestBinaryInvalidDataCode(t *testing.T) {
+
+	overflow := append([]byte("CIDR\x01"), bytes.Repeat([]byte{0xff}, 10)...)
+	overflow = append(overflow, 0x01, 0x18)
+
+	_, err := ReadBinary(bytes.NewReader(overflow))
+	if assert.Error(t, err, "An over-long varint is not valid binary data. An error should be thrown.") {
+
+		assert.Equal(t, consts.InvalidBinaryCode, GetErrorCode(err))
+		assert.Equal(t, consts.InvalidBinaryFormatError, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidBinaryFormatError)
+
+	}
+
+	_, err = ReadBinary(io.MultiReader(bytes.NewReader([]byte("CIDR\x01\x14")), failingReader{}))
+	if assert.Error(t, err, "A failing stream cannot be decoded. An error should be thrown.") {
+
+		assert.Equal(t, consts.InvalidBinaryCode, GetErrorCode(err))
+
+	}
+
+}
+
+// TestSetBinary encodes a Set in the binary format and decodes it back
+// Success Metric: The same set is decoded, and invalid data leaves the set unchanged
+func TestSetBinary(t *testing.T) {
+
+	set := Set(parseAll(t, "10.0.0.0/24", "192.168.0.0/16", "10.0.1.0/24"))
+
+	data, err := set.MarshalBinary()
+	assert.Nil(t, err, "The set should be encoded.")
+
+	var decoded Set
+	err = decoded.UnmarshalBinary(data)
+	assert.Nil(t, err, "The set should be decoded.")
+	assert.Equal(t, toStrings(set), toStrings(decoded))
+
+	err = decoded.UnmarshalBinary([]byte("JSON"))
+	if assert.Error(t, err, "\"JSON\" is not valid binary data. An error should be thrown.") {
+
+		assert.Equal(t, consts.InvalidBinaryFormatError, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidBinaryFormatError)
+
+	}
+	assert.Equal(t, toStrings(set), toStrings(decoded), "A failed decoding should not modify the set.")
+
+}
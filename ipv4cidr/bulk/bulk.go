@@ -0,0 +1,251 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package bulk
+
+import (
+	"bufio"
+	"io"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr"
+)
+
+// Default values used for unset fields of Options
+const (
+	DefaultBatchSize        int = 1024
+	DefaultCompactThreshold int = 1 << 16
+)
+
+// Progress reports how much of the input stream has been processed so far
+// @field Lines uint64: Number of lines read
+// @field Parsed uint64: Number of lines successfully parsed into CIDR ranges
+// @field Invalid uint64: Number of lines that could not be parsed
+type Progress struct {
+	Lines   uint64
+	Parsed  uint64
+	Invalid uint64
+}
+
+// Options configures the processing of a stream
+// @field Workers int: Number of goroutines parsing lines concurrently. Defaults to the number of CPUs
+// @field BatchSize int: Number of lines handed to a worker at a time. Defaults to DefaultBatchSize
+// @field Standardize bool: If set, non-standard CIDR ranges are standardized instead of being rejected as invalid
+// @field CompactThreshold int: Number of pending CIDR ranges after which they are aggregated to bound memory usage. Defaults to DefaultCompactThreshold
+// @field OnProgress func(Progress): Optional callback, called after every processed batch
+// @field OnError func(line uint64, input string, err error): Optional callback, called for every line that could not be parsed
+type Options struct {
+	Workers          int
+	BatchSize        int
+	Standardize      bool
+	CompactThreshold int
+	OnProgress       func(Progress)
+	OnError          func(line uint64, input string, err error)
+}
+
+// Result holds the outcome of processing a stream
+// @field Prefixes []*ipv4cidr.IPv4CIDR: The minimal list of CIDR ranges covering every parsed line, in order of IP
+// @field Progress Progress: The final line counts
+type Result struct {
+	Prefixes []*ipv4cidr.IPv4CIDR
+	Progress Progress
+}
+
+// batch holds a group of lines read from the stream
+// @field numbers []uint64: Line number of each line in the stream (1-based)
+// @field lines []string: The lines
+type batch struct {
+	numbers []uint64
+	lines   []string
+}
+
+// batchResult holds the outcome of parsing a batch
+// @field batch batch: The parsed batch
+// @field cidrs []*ipv4cidr.IPv4CIDR: The CIDR ranges parsed from the batch
+// @field errs []error: The parse error of each line, nil if the line was parsed successfully
+type batchResult struct {
+	batch batch
+	cidrs []*ipv4cidr.IPv4CIDR
+	errs  []error
+}
+
+// Process reads CIDR ranges from a stream, one per line, and aggregates them into the minimal list of CIDR ranges covering all of them
+// Empty lines and lines starting with # are skipped. Lines are parsed by a pool of workers, and only a bounded number of batches is in flight at any time.
+// @input r io.Reader: The stream to read, with one CIDR range (a.b.c.d/e) or IP address (a.b.c.d) per line
+// @input opts Options: The processing options
+// @returns *Result: The aggregated CIDR ranges and line counts
+// @returns error: If reading the stream fails, the error is returned
+func Process(r io.Reader, opts Options) (*Result, error) {
+
+	opts = withDefaults(opts)
+
+	batches := make(chan batch, opts.Workers)
+	results := make(chan batchResult, opts.Workers)
+
+	// Read the stream into batches
+	var readErr error
+	go func() {
+		readErr = readBatches(r, opts.BatchSize, batches)
+		close(batches)
+	}()
+
+	// Parse the batches concurrently
+	var workers sync.WaitGroup
+	for n := 0; n < opts.Workers; n++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for b := range batches {
+				results <- parseBatch(b, opts.Standardize)
+			}
+		}()
+	}
+	go func() {
+		workers.Wait()
+		close(results)
+	}()
+
+	// Collect the results, compacting the pending CIDR ranges whenever they exceed the threshold
+	progress := Progress{}
+	pending := make([]*ipv4cidr.IPv4CIDR, 0)
+	threshold := opts.CompactThreshold
+
+	for result := range results {
+
+		progress.Lines += uint64(len(result.batch.lines))
+		for n, err := range result.errs {
+			if err == nil {
+				progress.Parsed++
+				continue
+			}
+			progress.Invalid++
+			if opts.OnError != nil {
+				opts.OnError(result.batch.numbers[n], result.batch.lines[n], err)
+			}
+		}
+
+		pending = append(pending, result.cidrs...)
+		if len(pending) > threshold {
+			pending = ipv4cidr.Aggregate(pending)
+
+			// If compaction did not help much, raise the threshold so that it is not repeated for every batch
+			if len(pending) > threshold/2 {
+				threshold *= 2
+			}
+		}
+
+		if opts.OnProgress != nil {
+			opts.OnProgress(progress)
+		}
+
+	}
+
+	// The results channel is only closed after the reader has finished, so readErr is safe to access
+	if readErr != nil {
+		return nil, readErr
+	}
+
+	return &Result{
+		Prefixes: ipv4cidr.Aggregate(pending),
+		Progress: progress,
+	}, nil
+
+}
+
+// withDefaults replaces the unset fields of the options with their default values
+// @input opts Options: The options as provided by the caller
+// @returns Options: The options with defaults applied
+func withDefaults(opts Options) Options {
+
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.NumCPU()
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+	if opts.CompactThreshold <= 0 {
+		opts.CompactThreshold = DefaultCompactThreshold
+	}
+
+	return opts
+
+}
+
+// readBatches reads the non-empty, non-comment lines of a stream and sends them in batches
+// @input r io.Reader: The stream to read
+// @input batchSize int: Number of lines per batch
+// @input batches chan<- batch: The channel to send the batches to
+// @returns error: If reading the stream fails, the error is returned
+func readBatches(r io.Reader, batchSize int, batches chan<- batch) error {
+
+	scanner := bufio.NewScanner(r)
+	current := newBatch(batchSize)
+	lineNumber := uint64(0)
+
+	for scanner.Scan() {
+
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		current.numbers = append(current.numbers, lineNumber)
+		current.lines = append(current.lines, line)
+
+		if len(current.lines) == batchSize {
+			batches <- current
+			current = newBatch(batchSize)
+		}
+
+	}
+
+	if len(current.lines) > 0 {
+		batches <- current
+	}
+
+	return scanner.Err()
+
+}
+
+// newBatch creates an empty batch with room for the given number of lines
+// @input batchSize int: Number of lines per batch
+// @returns batch: The empty batch
+func newBatch(batchSize int) batch {
+
+	return batch{
+		numbers: make([]uint64, 0, batchSize),
+		lines:   make([]string, 0, batchSize),
+	}
+
+}
+
+// parseBatch parses every line of a batch into a CIDR range
+// @input b batch: The batch to parse
+// @input standardize bool: Flag for whether to standardize non-standard CIDR ranges or reject them
+// @returns batchResult: The parsed CIDR ranges and the error of each line
+func parseBatch(b batch, standardize bool) batchResult {
+
+	result := batchResult{
+		batch: b,
+		cidrs: make([]*ipv4cidr.IPv4CIDR, 0, len(b.lines)),
+		errs:  make([]error, len(b.lines)),
+	}
+
+	parser := ipv4cidr.NewParser(standardize)
+	for n, line := range b.lines {
+
+		cidr := &ipv4cidr.IPv4CIDR{}
+		if err := parser.ParseInto(cidr, line); err != nil {
+			result.errs[n] = err
+			continue
+		}
+		result.cidrs = append(result.cidrs, cidr)
+
+	}
+
+	return result
+
+}
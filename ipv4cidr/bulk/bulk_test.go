@@ -0,0 +1,144 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package bulk
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// failingReader is a reader that always fails
+type failingReader struct{}
+
+// Read returns an error
+func (r failingReader) Read(p []byte) (int, error) {
+
+	return 0, errors.New("read failed")
+
+}
+
+// TestProcess processes a small stream with valid, invalid, comment and empty lines
+// Success Metric: The valid lines are aggregated, invalid lines are reported with their line number
+func TestProcess(t *testing.T) {
+
+	input := strings.Join([]string{
+		"# prefixes",
+		"10.0.0.0/24",
+		"10.0.1.0/24",
+		"",
+		"10.0.0.128/25",
+		"10.0.2.0/33",
+		"192.168.0.1/24",
+		"  172.16.0.0/12  ",
+	}, "\n")
+
+	invalidLines := make([]uint64, 0)
+	opts := Options{
+		Workers:   2,
+		BatchSize: 2,
+		OnError: func(line uint64, input string, err error) {
+			invalidLines = append(invalidLines, line)
+		},
+	}
+
+	result, err := Process(strings.NewReader(input), opts)
+	assert.Nil(t, err, "The stream can be read, it should be processed.")
+
+	prefixes := make([]string, 0)
+	for _, prefix := range result.Prefixes {
+		prefixes = append(prefixes, prefix.ToString())
+	}
+	assert.Equal(t, []string{"10.0.0.0/23", "172.16.0.0/12"}, prefixes)
+	assert.Equal(t, Progress{Lines: 6, Parsed: 4, Invalid: 2}, result.Progress)
+	assert.ElementsMatch(t, []uint64{6, 7}, invalidLines)
+
+}
+
+// TestProcessStandardize processes a stream with non-standard CIDR ranges and the standardize option set
+// Success Metric: The CIDR ranges are standardized instead of being rejected
+func TestProcessStandardize(t *testing.T) {
+
+	result, err := Process(strings.NewReader("192.168.0.1/24\n192.168.1.1/24"), Options{Standardize: true})
+	assert.Nil(t, err, "The stream can be read, it should be processed.")
+
+	if assert.Len(t, result.Prefixes, 1) {
+
+		assert.Equal(t, "192.168.0.0/23", result.Prefixes[0].ToString())
+
+	}
+
+}
+
+// TestProcessLargeStream processes a stream large enough to trigger compaction, reporting progress
+// Success Metric: All lines are aggregated and progress is reported after every batch
+func TestProcessLargeStream(t *testing.T) {
+
+	var builder strings.Builder
+	for n := 0; n < 4096; n++ {
+		fmt.Fprintf(&builder, "10.%d.%d.0/24\n", n/256, n%256)
+	}
+
+	progressCalls := 0
+	lastProgress := Progress{}
+	opts := Options{
+		BatchSize:        64,
+		CompactThreshold: 128,
+		OnProgress: func(p Progress) {
+			progressCalls++
+			lastProgress = p
+		},
+	}
+
+	result, err := Process(strings.NewReader(builder.String()), opts)
+	assert.Nil(t, err, "The stream can be read, it should be processed.")
+
+	if assert.Len(t, result.Prefixes, 1) {
+
+		assert.Equal(t, "10.0.0.0/12", result.Prefixes[0].ToString())
+
+	}
+	assert.Equal(t, 64, progressCalls)
+	assert.Equal(t, uint64(4096), lastProgress.Parsed)
+
+}
+
+// TestProcessReadError processes a stream that cannot be read
+// Success Metric: The read error is returned
+func TestProcessReadError(t *testing.T) {
+
+	_, err := Process(failingReader{}, Options{})
+	if assert.Error(t, err, "The stream cannot be read. An error should be thrown.") {
+
+		assert.Equal(t, "read failed", err.Error())
+
+	}
+
+}
+
+// TestProcessInvalidLineError checks the error reported for an invalid line
+// Success Metric: The error is the package's invalid input error
+func TestProcessInvalidLineError(t *testing.T) {
+
+	var reported error
+	opts := Options{
+		OnError: func(line uint64, input string, err error) {
+			reported = err
+		},
+	}
+
+	_, err := Process(strings.NewReader("10.0.0.0/33"), opts)
+	assert.Nil(t, err, "The stream can be read, it should be processed.")
+	if assert.Error(t, reported, "10.0.0.0/33 is invalid. An error should be reported.") {
+
+		assert.Equal(t, consts.InvalidIPv4CIDRError, reported.Error())
+
+	}
+
+}
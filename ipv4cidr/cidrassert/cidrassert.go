@@ -0,0 +1,221 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package cidrassert
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/utils"
+)
+
+// TestingT is the subset of *testing.T used to report failures, so the assertions can be used with any test framework
+type TestingT interface {
+	Errorf(format string, args ...interface{})
+}
+
+// ContainsIP asserts that a CIDR range contains an IP address
+// @input t TestingT: The test to report the failure to
+// @input cidr string: The CIDR range in format a.b.c.d/e or a.b.c.d
+// @input ip string: The IP address in format a.b.c.d
+// @input msgAndArgs ...interface{}: Optional message (and format arguments) added to the failure
+// @returns bool: True if the assertion passed
+func ContainsIP(t TestingT, cidr string, ip string, msgAndArgs ...interface{}) bool {
+
+	helper(t)
+
+	parsed, ok := parseCIDR(t, cidr, msgAndArgs)
+	if !ok {
+		return false
+	}
+
+	value, err := utils.ParseIPUint32(ip)
+	if err != nil {
+		return fail(t, fmt.Sprintf("Invalid IP address %q: %s", ip, err.Error()), msgAndArgs)
+	}
+
+	if !parsed.ContainsRange(value, value) {
+		first, last := parsed.Range()
+		return fail(t, fmt.Sprintf("Expected %s to contain %s, but its range is %s - %s", cidr, ip, utils.ConvertIPToString(first), utils.ConvertIPToString(last)), msgAndArgs)
+	}
+
+	return true
+
+}
+
+// Within asserts that a CIDR range is entirely within a parent CIDR range
+// @input t TestingT: The test to report the failure to
+// @input cidr string: The CIDR range in format a.b.c.d/e or a.b.c.d
+// @input parent string: The parent CIDR range in format a.b.c.d/e or a.b.c.d
+// @input msgAndArgs ...interface{}: Optional message (and format arguments) added to the failure
+// @returns bool: True if the assertion passed
+func Within(t TestingT, cidr string, parent string, msgAndArgs ...interface{}) bool {
+
+	helper(t)
+
+	child, ok := parseCIDR(t, cidr, msgAndArgs)
+	if !ok {
+		return false
+	}
+	supernet, ok := parseCIDR(t, parent, msgAndArgs)
+	if !ok {
+		return false
+	}
+
+	if !supernet.ContainsRange(child.Range()) {
+		outside, _ := ipv4cidr.DiffLists([]*ipv4cidr.IPv4CIDR{supernet}, []*ipv4cidr.IPv4CIDR{child})
+		return fail(t, fmt.Sprintf("Expected %s to be within %s, but %s is outside of it", cidr, parent, joinCIDRs(outside)), msgAndArgs)
+	}
+
+	return true
+
+}
+
+// NoOverlap asserts that no two CIDR ranges of a list share IP addresses
+// @input t TestingT: The test to report the failure to
+// @input cidrs []string: The CIDR ranges in format a.b.c.d/e or a.b.c.d
+// @input msgAndArgs ...interface{}: Optional message (and format arguments) added to the failure
+// @returns bool: True if the assertion passed
+func NoOverlap(t TestingT, cidrs []string, msgAndArgs ...interface{}) bool {
+
+	helper(t)
+
+	parsed, ok := parseCIDRs(t, cidrs, msgAndArgs)
+	if !ok {
+		return false
+	}
+
+	overlaps := ipv4cidr.OverlapReport(parsed)
+	if len(overlaps) > 0 {
+		lines := make([]string, 0, len(overlaps))
+		for _, overlap := range overlaps {
+			lines = append(lines, fmt.Sprintf("\t%s and %s share %s (%d addresses)", overlap.First.ToString(), overlap.Second.ToString(), overlap.Region.ToString(), overlap.Size))
+		}
+		return fail(t, fmt.Sprintf("Expected no overlapping CIDR ranges, but found %d overlap(s):\n%s", len(overlaps), strings.Join(lines, "\n")), msgAndArgs)
+	}
+
+	return true
+
+}
+
+// EqualSets asserts that two lists of CIDR ranges cover exactly the same IP addresses, however they are split into CIDR ranges and in whatever order
+// @input t TestingT: The test to report the failure to
+// @input expected []string: The expected CIDR ranges in format a.b.c.d/e or a.b.c.d
+// @input actual []string: The actual CIDR ranges in format a.b.c.d/e or a.b.c.d
+// @input msgAndArgs ...interface{}: Optional message (and format arguments) added to the failure
+// @returns bool: True if the assertion passed
+func EqualSets(t TestingT, expected []string, actual []string, msgAndArgs ...interface{}) bool {
+
+	helper(t)
+
+	expectedCIDRs, ok := parseCIDRs(t, expected, msgAndArgs)
+	if !ok {
+		return false
+	}
+	actualCIDRs, ok := parseCIDRs(t, actual, msgAndArgs)
+	if !ok {
+		return false
+	}
+
+	extra, missing := ipv4cidr.DiffLists(expectedCIDRs, actualCIDRs)
+	if len(extra) > 0 || len(missing) > 0 {
+		return fail(t, fmt.Sprintf("Expected the same set of addresses\n\tmissing: %s\n\textra:   %s", joinCIDRs(missing), joinCIDRs(extra)), msgAndArgs)
+	}
+
+	return true
+
+}
+
+// helper marks the calling function as a test helper, if the test supports it
+// @input t TestingT: The test
+func helper(t TestingT) {
+
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+
+}
+
+// fail reports a failure with an optional user message
+// @input t TestingT: The test to report the failure to
+// @input message string: The description of the failure
+// @input msgAndArgs []interface{}: Optional message (and format arguments) added to the failure
+// @returns bool: Always false
+func fail(t TestingT, message string, msgAndArgs []interface{}) bool {
+
+	helper(t)
+
+	if len(msgAndArgs) > 0 {
+		if format, ok := msgAndArgs[0].(string); ok {
+			message += "\n\tmessage: " + fmt.Sprintf(format, msgAndArgs[1:]...)
+		} else {
+			message += "\n\tmessage: " + fmt.Sprint(msgAndArgs...)
+		}
+	}
+	t.Errorf("%s", message)
+
+	return false
+
+}
+
+// parseCIDR parses a CIDR range, reporting a failure if it is invalid
+// @input t TestingT: The test to report the failure to
+// @input cidr string: The CIDR range in format a.b.c.d/e or a.b.c.d
+// @input msgAndArgs []interface{}: Optional message (and format arguments) added to the failure
+// @returns *ipv4cidr.IPv4CIDR: The parsed CIDR range, standardized
+// @returns bool: True if the CIDR range is valid
+func parseCIDR(t TestingT, cidr string, msgAndArgs []interface{}) (*ipv4cidr.IPv4CIDR, bool) {
+
+	helper(t)
+
+	parsed, err := ipv4cidr.NewIPv4CIDR(cidr, true)
+	if err != nil {
+		return nil, fail(t, fmt.Sprintf("Invalid CIDR range %q: %s", cidr, err.Error()), msgAndArgs)
+	}
+
+	return parsed, true
+
+}
+
+// parseCIDRs parses a list of CIDR ranges, reporting a failure if any is invalid
+// @input t TestingT: The test to report the failure to
+// @input cidrs []string: The CIDR ranges in format a.b.c.d/e or a.b.c.d
+// @input msgAndArgs []interface{}: Optional message (and format arguments) added to the failure
+// @returns []*ipv4cidr.IPv4CIDR: The parsed CIDR ranges, standardized
+// @returns bool: True if all CIDR ranges are valid
+func parseCIDRs(t TestingT, cidrs []string, msgAndArgs []interface{}) ([]*ipv4cidr.IPv4CIDR, bool) {
+
+	helper(t)
+
+	parsed := make([]*ipv4cidr.IPv4CIDR, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		p, ok := parseCIDR(t, cidr, msgAndArgs)
+		if !ok {
+			return nil, false
+		}
+		parsed = append(parsed, p)
+	}
+
+	return parsed, true
+
+}
+
+// joinCIDRs formats a list of CIDR ranges for a failure message
+// @input cidrs []*ipv4cidr.IPv4CIDR: The CIDR ranges
+// @returns string: The comma-separated CIDR ranges, or "none"
+func joinCIDRs(cidrs []*ipv4cidr.IPv4CIDR) string {
+
+	if len(cidrs) == 0 {
+		return "none"
+	}
+
+	parts := make([]string, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		parts = append(parts, cidr.ToString())
+	}
+
+	return strings.Join(parts, ", ")
+
+}
@@ -0,0 +1,89 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package cidrassert
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder records the failures reported by an assertion
+type recorder struct {
+	failures []string
+}
+
+// Errorf records a failure
+func (r *recorder) Errorf(format string, args ...interface{}) {
+
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+
+}
+
+// TestContainsIP checks IPs inside and outside a CIDR range
+// Success Metric: Only IPs outside the range fail, with the range in the message
+func TestContainsIP(t *testing.T) {
+
+	r := &recorder{}
+	assert.True(t, ContainsIP(r, "10.0.0.0/24", "10.0.0.255"))
+	assert.Empty(t, r.failures)
+
+	assert.False(t, ContainsIP(r, "10.0.0.0/24", "10.0.1.0", "checking host %d", 7))
+	if assert.Len(t, r.failures, 1) {
+		assert.Equal(t, "Expected 10.0.0.0/24 to contain 10.0.1.0, but its range is 10.0.0.0 - 10.0.0.255\n\tmessage: checking host 7", r.failures[0])
+	}
+
+	assert.False(t, ContainsIP(r, "10.0.0.0/24", "10.0.1"))
+	assert.Len(t, r.failures, 2)
+
+}
+
+// TestWithin checks CIDR ranges inside and partially outside a parent
+// Success Metric: The failure message shows the part of the range outside the parent
+func TestWithin(t *testing.T) {
+
+	r := &recorder{}
+	assert.True(t, Within(r, "10.0.1.0/24", "10.0.0.0/16"))
+	assert.Empty(t, r.failures)
+
+	assert.False(t, Within(r, "10.0.0.0/15", "10.0.0.0/16"))
+	if assert.Len(t, r.failures, 1) {
+		assert.Equal(t, "Expected 10.0.0.0/15 to be within 10.0.0.0/16, but 10.1.0.0/16 is outside of it", r.failures[0])
+	}
+
+	assert.False(t, Within(r, "10.0.0.0/33", "10.0.0.0/16"))
+	assert.Len(t, r.failures, 2)
+
+}
+
+// TestNoOverlap checks disjoint and overlapping lists
+// Success Metric: The failure message lists every overlapping pair
+func TestNoOverlap(t *testing.T) {
+
+	r := &recorder{}
+	assert.True(t, NoOverlap(r, []string{"10.0.0.0/24", "10.0.1.0/24"}))
+	assert.Empty(t, r.failures)
+
+	assert.False(t, NoOverlap(r, []string{"10.0.0.0/16", "10.0.1.0/24"}))
+	if assert.Len(t, r.failures, 1) {
+		assert.Equal(t, "Expected no overlapping CIDR ranges, but found 1 overlap(s):\n\t10.0.0.0/16 and 10.0.1.0/24 share 10.0.1.0/24 (256 addresses)", r.failures[0])
+	}
+
+}
+
+// TestEqualSets compares lists covering the same and different addresses
+// Success Metric: Differently split lists are equal, and the failure message lists missing and extra addresses
+func TestEqualSets(t *testing.T) {
+
+	r := &recorder{}
+	assert.True(t, EqualSets(r, []string{"10.0.0.0/23"}, []string{"10.0.1.0/24", "10.0.0.0/24"}))
+	assert.Empty(t, r.failures)
+
+	assert.False(t, EqualSets(r, []string{"10.0.0.0/23"}, []string{"10.0.0.0/24", "192.168.0.0/24"}))
+	if assert.Len(t, r.failures, 1) {
+		assert.Equal(t, "Expected the same set of addresses\n\tmissing: 10.0.1.0/24\n\textra:   192.168.0.0/24", r.failures[0])
+	}
+
+}
@@ -0,0 +1,473 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package cidrexpr
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/utils"
+)
+
+// Expression is a compiled filter over CIDR ranges, e.g. within("10.0.0.0/8") && !overlaps(reserved)
+//
+// The language is made of:
+//   - within(x), overlaps(x), contains(x): true if the CIDR range is within, overlaps, or contains the addresses of x,
+//     where x is a quoted CIDR range or the name of a set
+//   - private(), shared(), host(): true if the CIDR range is private (RFC 1918), shared (RFC 6598), or a single IP
+//   - mask == n, mask != n, mask < n, mask <= n, mask > n, mask >= n: comparisons of the mask of the CIDR range
+//   - true, false, !, &&, || and parentheses, with the usual precedence
+//
+// @field source string: The source of the expression
+// @field match predicate: The compiled expression
+type Expression struct {
+	source string
+	match  predicate
+}
+
+// predicate is a compiled (sub-)expression
+type predicate func(cidr *ipv4cidr.IPv4CIDR) bool
+
+// Compile parses an expression and resolves the sets it references
+// @input source string: The expression
+// @input sets map[string][]*ipv4cidr.IPv4CIDR: The named sets the expression can reference, may be nil
+// @returns *Expression: The compiled expression
+// @returns error: If the expression is invalid, or references an undefined set, an error is returned
+func Compile(source string, sets map[string][]*ipv4cidr.IPv4CIDR) (*Expression, error) {
+
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens, sets: sets}
+
+	match, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.position != len(p.tokens) {
+		return nil, invalidExpression()
+	}
+
+	return &Expression{source: source, match: match}, nil
+
+}
+
+// Match evaluates the expression against a CIDR range
+// @input cidr *ipv4cidr.IPv4CIDR: The CIDR range
+// @returns bool: True if the CIDR range matches the expression
+func (e *Expression) Match(cidr *ipv4cidr.IPv4CIDR) bool {
+
+	return e.match(cidr)
+
+}
+
+// Filter returns the CIDR ranges matching the expression
+// @input cidrs []*ipv4cidr.IPv4CIDR: The CIDR ranges
+// @returns []*ipv4cidr.IPv4CIDR: The matching CIDR ranges, in the order of the input
+func (e *Expression) Filter(cidrs []*ipv4cidr.IPv4CIDR) []*ipv4cidr.IPv4CIDR {
+
+	matches := make([]*ipv4cidr.IPv4CIDR, 0)
+	for _, cidr := range cidrs {
+		if e.match(cidr) {
+			matches = append(matches, cidr)
+		}
+	}
+
+	return matches
+
+}
+
+// String returns the source of the expression
+// @returns string: The source of the expression
+func (e *Expression) String() string {
+
+	return e.source
+
+}
+
+// This set of constants defines the kinds of tokens of the expression language
+const (
+	identifierToken = iota
+	stringToken
+	numberToken
+	operatorToken
+)
+
+// token is a lexical token of an expression
+// @field kind int: The kind of token
+// @field text string: The text of the token, without quotes for strings
+type token struct {
+	kind int
+	text string
+}
+
+// operators are the operators and punctuation of the language, two-character operators first so that they are matched greedily
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "(", ")"}
+
+// tokenize splits an expression into tokens
+// @input source string: The expression
+// @returns []token: The tokens
+// @returns error: If the expression contains an unexpected character or an unterminated string, an error is returned
+func tokenize(source string) ([]token, error) {
+
+	tokens := make([]token, 0)
+	for position := 0; position < len(source); {
+
+		c := source[position]
+		switch {
+
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			position++
+
+		case c == '"':
+			end := strings.IndexByte(source[position+1:], '"')
+			if end < 0 {
+				return nil, invalidExpression()
+			}
+			tokens = append(tokens, token{kind: stringToken, text: source[position+1 : position+1+end]})
+			position += end + 2
+
+		case c >= '0' && c <= '9':
+			end := position
+			for end < len(source) && source[end] >= '0' && source[end] <= '9' {
+				end++
+			}
+			tokens = append(tokens, token{kind: numberToken, text: source[position:end]})
+			position = end
+
+		case c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+			end := position
+			for end < len(source) && (source[end] == '_' || source[end] == '-' || (source[end] >= 'a' && source[end] <= 'z') || (source[end] >= 'A' && source[end] <= 'Z') || (source[end] >= '0' && source[end] <= '9')) {
+				end++
+			}
+			tokens = append(tokens, token{kind: identifierToken, text: source[position:end]})
+			position = end
+
+		default:
+			matched := false
+			for _, operator := range operators {
+				if strings.HasPrefix(source[position:], operator) {
+					tokens = append(tokens, token{kind: operatorToken, text: operator})
+					position += len(operator)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, invalidExpression()
+			}
+
+		}
+
+	}
+
+	return tokens, nil
+
+}
+
+// parser is a recursive descent parser compiling tokens into predicates
+// @field tokens []token: The tokens of the expression
+// @field position int: The index of the next token
+// @field sets map[string][]*ipv4cidr.IPv4CIDR: The named sets the expression can reference
+type parser struct {
+	tokens   []token
+	position int
+	sets     map[string][]*ipv4cidr.IPv4CIDR
+}
+
+// accept consumes the next token if it is the given operator
+// @input operator string: The operator
+// @returns bool: True if the token was consumed
+func (p *parser) accept(operator string) bool {
+
+	if p.position < len(p.tokens) && p.tokens[p.position].kind == operatorToken && p.tokens[p.position].text == operator {
+		p.position++
+		return true
+	}
+
+	return false
+
+}
+
+// next consumes the next token
+// @returns token: The token
+// @returns bool: False if there are no more tokens
+func (p *parser) next() (token, bool) {
+
+	if p.position >= len(p.tokens) {
+		return token{}, false
+	}
+
+	p.position++
+
+	return p.tokens[p.position-1], true
+
+}
+
+// parseOr parses a disjunction: and ('||' and)*
+// @returns predicate: The compiled expression
+// @returns error: If the tokens are not a valid expression, an error is returned
+func (p *parser) parseOr() (predicate, error) {
+
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept("||") {
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		l := left
+		left = func(cidr *ipv4cidr.IPv4CIDR) bool { return l(cidr) || right(cidr) }
+
+	}
+
+	return left, nil
+
+}
+
+// parseAnd parses a conjunction: unary ('&&' unary)*
+// @returns predicate: The compiled expression
+// @returns error: If the tokens are not a valid expression, an error is returned
+func (p *parser) parseAnd() (predicate, error) {
+
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept("&&") {
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		l := left
+		left = func(cidr *ipv4cidr.IPv4CIDR) bool { return l(cidr) && right(cidr) }
+
+	}
+
+	return left, nil
+
+}
+
+// parseUnary parses a negation or a primary expression: '!' unary | primary
+// @returns predicate: The compiled expression
+// @returns error: If the tokens are not a valid expression, an error is returned
+func (p *parser) parseUnary() (predicate, error) {
+
+	if p.accept("!") {
+
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return func(cidr *ipv4cidr.IPv4CIDR) bool { return !operand(cidr) }, nil
+
+	}
+
+	return p.parsePrimary()
+
+}
+
+// parsePrimary parses a parenthesized expression, a boolean, a mask comparison or a function call
+// @returns predicate: The compiled expression
+// @returns error: If the tokens are not a valid expression, an error is returned
+func (p *parser) parsePrimary() (predicate, error) {
+
+	if p.accept("(") {
+
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if !p.accept(")") {
+			return nil, invalidExpression()
+		}
+
+		return inner, nil
+
+	}
+
+	name, ok := p.next()
+	if !ok || name.kind != identifierToken {
+		return nil, invalidExpression()
+	}
+
+	switch name.text {
+	case "true":
+		return func(*ipv4cidr.IPv4CIDR) bool { return true }, nil
+	case "false":
+		return func(*ipv4cidr.IPv4CIDR) bool { return false }, nil
+	case "mask":
+		return p.parseMaskComparison()
+	}
+
+	if !p.accept("(") {
+		return nil, invalidExpression()
+	}
+
+	switch name.text {
+
+	case "private", "shared", "host":
+		if !p.accept(")") {
+			return nil, invalidExpression()
+		}
+		return propertyPredicate(name.text), nil
+
+	case "within", "overlaps", "contains":
+		set, err := p.parseSet()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, invalidExpression()
+		}
+		return setPredicate(name.text, set), nil
+
+	}
+
+	return nil, invalidExpression()
+
+}
+
+// parseMaskComparison parses the rest of a mask comparison, after the mask keyword: operator number
+// @returns predicate: The compiled expression
+// @returns error: If the tokens are not a valid expression, an error is returned
+func (p *parser) parseMaskComparison() (predicate, error) {
+
+	operator, ok := p.next()
+	if !ok || operator.kind != operatorToken {
+		return nil, invalidExpression()
+	}
+
+	number, ok := p.next()
+	if !ok || number.kind != numberToken {
+		return nil, invalidExpression()
+	}
+
+	value, err := strconv.Atoi(number.text)
+	if err != nil || value > int(consts.MaxBits) {
+		return nil, utils.NewError(consts.InvalidExpressionCode, consts.InvalidMaskError)
+	}
+	mask := uint8(value)
+
+	switch operator.text {
+	case "==":
+		return func(cidr *ipv4cidr.IPv4CIDR) bool { return cidr.GetMask() == mask }, nil
+	case "!=":
+		return func(cidr *ipv4cidr.IPv4CIDR) bool { return cidr.GetMask() != mask }, nil
+	case "<":
+		return func(cidr *ipv4cidr.IPv4CIDR) bool { return cidr.GetMask() < mask }, nil
+	case "<=":
+		return func(cidr *ipv4cidr.IPv4CIDR) bool { return cidr.GetMask() <= mask }, nil
+	case ">":
+		return func(cidr *ipv4cidr.IPv4CIDR) bool { return cidr.GetMask() > mask }, nil
+	case ">=":
+		return func(cidr *ipv4cidr.IPv4CIDR) bool { return cidr.GetMask() >= mask }, nil
+	}
+
+	return nil, invalidExpression()
+
+}
+
+// parseSet parses the argument of a set function: a quoted CIDR range or the name of a set
+// @returns []*ipv4cidr.IPv4CIDR: The CIDR ranges of the argument
+// @returns error: If the argument is not a valid CIDR range or a defined set, an error is returned
+func (p *parser) parseSet() ([]*ipv4cidr.IPv4CIDR, error) {
+
+	argument, ok := p.next()
+	if !ok {
+		return nil, invalidExpression()
+	}
+
+	switch argument.kind {
+
+	case stringToken:
+		cidr, err := ipv4cidr.NewIPv4CIDR(argument.text, false)
+		if err != nil {
+			return nil, err
+		}
+		return []*ipv4cidr.IPv4CIDR{cidr}, nil
+
+	case identifierToken:
+		set, ok := p.sets[argument.text]
+		if !ok {
+			return nil, utils.NewError(consts.InvalidExpressionCode, consts.UndefinedSetError)
+		}
+		return set, nil
+
+	}
+
+	return nil, invalidExpression()
+
+}
+
+// propertyPredicate compiles a property function
+// @input name string: The name of the function, one of private, shared or host
+// @returns predicate: The compiled function
+func propertyPredicate(name string) predicate {
+
+	switch name {
+	case "private":
+		return func(cidr *ipv4cidr.IPv4CIDR) bool { return cidr.IsPrivate() }
+	case "shared":
+		return func(cidr *ipv4cidr.IPv4CIDR) bool { return cidr.IsSharedAddressSpace() }
+	}
+
+	return func(cidr *ipv4cidr.IPv4CIDR) bool { return cidr.IsSingleIP() }
+
+}
+
+// setPredicate compiles a set function. The set is aggregated once, at compile time
+// @input name string: The name of the function, one of within, overlaps or contains
+// @input set []*ipv4cidr.IPv4CIDR: The CIDR ranges of the argument
+// @returns predicate: The compiled function
+func setPredicate(name string, set []*ipv4cidr.IPv4CIDR) predicate {
+
+	set = ipv4cidr.Aggregate(set)
+
+	switch name {
+
+	case "within":
+		// Every address of the CIDR range is in the set
+		return func(cidr *ipv4cidr.IPv4CIDR) bool {
+			return len(ipv4cidr.Difference([]*ipv4cidr.IPv4CIDR{cidr}, set)) == 0
+		}
+
+	case "overlaps":
+		// Some address of the CIDR range is in the set
+		return func(cidr *ipv4cidr.IPv4CIDR) bool {
+			outside := ipv4cidr.Difference([]*ipv4cidr.IPv4CIDR{cidr}, set)
+			return ipv4cidr.TotalAddresses(outside) < ipv4cidr.TotalAddresses([]*ipv4cidr.IPv4CIDR{cidr})
+		}
+
+	}
+
+	// Every address of the set is in the CIDR range
+	return func(cidr *ipv4cidr.IPv4CIDR) bool {
+		return len(ipv4cidr.Difference(set, []*ipv4cidr.IPv4CIDR{cidr})) == 0
+	}
+
+}
+
+// invalidExpression creates the error returned for syntax errors
+// @returns error: The error
+func invalidExpression() error {
+
+	return utils.NewError(consts.InvalidExpressionCode, consts.InvalidExpressionError)
+
+}
@@ -0,0 +1,127 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package cidrexpr
+
+import (
+	"testing"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// parseAll parses a list of CIDR ranges in tests
+func parseAll(t *testing.T, cidrs ...string) []*ipv4cidr.IPv4CIDR {
+
+	parsed := make([]*ipv4cidr.IPv4CIDR, 0, len(cidrs))
+	for _, cidr := range cidrs {
+
+		p, err := ipv4cidr.NewIPv4CIDR(cidr, false)
+		assert.Nil(t, err, "%s is a valid CIDR block", cidr)
+		parsed = append(parsed, p)
+
+	}
+
+	return parsed
+
+}
+
+// toStrings converts a list of CIDR ranges to strings for comparison in tests
+func toStrings(cidrs []*ipv4cidr.IPv4CIDR) []string {
+
+	strs := make([]string, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		strs = append(strs, cidr.ToString())
+	}
+
+	return strs
+
+}
+
+// TestExpressionFilter filters CIDR ranges with expressions
+// Success Metric: Each expression keeps exactly the CIDR ranges it describes
+func TestExpressionFilter(t *testing.T) {
+
+	sets := map[string][]*ipv4cidr.IPv4CIDR{
+		"reserved": parseAll(t, "10.1.0.0/24", "10.1.1.0/24"),
+	}
+	cidrs := parseAll(t, "10.0.0.0/24", "10.1.0.0/23", "10.1.1.128/25", "10.2.0.0/16", "100.64.0.0/24", "192.0.2.1/32", "0.0.0.0/0")
+
+	for source, expected := range map[string][]string{
+		`within("10.0.0.0/8") && !overlaps(reserved)`: {"10.0.0.0/24", "10.2.0.0/16"},
+		`within(reserved)`:                      {"10.1.0.0/23", "10.1.1.128/25"},
+		`overlaps(reserved)`:                    {"10.1.0.0/23", "10.1.1.128/25", "0.0.0.0/0"},
+		`contains(reserved)`:                    {"10.1.0.0/23", "0.0.0.0/0"},
+		`private() && mask >= 24`:               {"10.0.0.0/24", "10.1.1.128/25"},
+		`shared() || host()`:                    {"100.64.0.0/24", "192.0.2.1/32"},
+		`!(mask > 0 && mask != 32)`:             {"192.0.2.1/32", "0.0.0.0/0"},
+		`mask == 16 || mask < 1`:                {"10.2.0.0/16", "0.0.0.0/0"},
+		`mask <= 23 && false || true && host()`: {"192.0.2.1/32"},
+	} {
+
+		expression, err := Compile(source, sets)
+		if assert.Nil(t, err, "%s is a valid expression", source) {
+
+			assert.Equal(t, expected, toStrings(expression.Filter(cidrs)), "Matches of %s", source)
+			assert.Equal(t, source, expression.String())
+
+		}
+
+	}
+
+}
+
+// TestCompileInvalidInput compiles invalid expressions
+// Success Metric: Errors are returned for syntax errors, undefined sets, invalid CIDR ranges and invalid masks
+func TestCompileInvalidInput(t *testing.T) {
+
+	for _, source := range []string{
+		``,
+		`within("10.0.0.0/8"`,
+		`within("10.0.0.0/8) && host()`,
+		`within("10.0.0.0/8") &&`,
+		`within("10.0.0.0/8") host()`,
+		`private`,
+		`private(reserved)`,
+		`unknown()`,
+		`mask`,
+		`mask ! 24`,
+		`mask >= private`,
+		`within(24)`,
+		`host() & private()`,
+		`(host()`,
+	} {
+
+		_, err := Compile(source, nil)
+		if assert.Error(t, err, "%s is an invalid expression. An error should be thrown.", source) {
+
+			assert.Equal(t, consts.InvalidExpressionError, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidExpressionError)
+
+		}
+
+	}
+
+	_, err := Compile(`overlaps(reserved)`, nil)
+	if assert.Error(t, err, "reserved is not defined. An error should be thrown.") {
+
+		assert.Equal(t, consts.UndefinedSetError, err.Error(), "Error thrown should be: \"%s\"", consts.UndefinedSetError)
+
+	}
+
+	_, err = Compile(`within("10.0.0.1/8")`, nil)
+	if assert.Error(t, err, "10.0.0.1/8 is not standardized. An error should be thrown.") {
+
+		assert.Equal(t, consts.NonStandardizedIPError, err.Error(), "Error thrown should be: \"%s\"", consts.NonStandardizedIPError)
+
+	}
+
+	_, err = Compile(`mask > 33`, nil)
+	if assert.Error(t, err, "33 is not a valid mask. An error should be thrown.") {
+
+		assert.Equal(t, consts.InvalidMaskError, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidMaskError)
+
+	}
+
+}
@@ -0,0 +1,17 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package consts
+
+// This set of constants defines the actions of an ACL rule
+const (
+	ACLAllow string = "allow"
+	ACLDeny  string = "deny"
+)
+
+// This set of constants defines the reasons for which an ACL rule can be eliminated by the optimizer or reported by the analyzer
+const (
+	ACLShadowed  string = "shadowed"
+	ACLMerged    string = "merged"
+	ACLRedundant string = "redundant"
+)
@@ -0,0 +1,33 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package consts
+
+// This set of constants defines the Azure subnet delegations and dedicated subnet names that have a minimum subnet size
+const (
+	AzureAppServiceDelegation         string = "Microsoft.Web/serverFarms"
+	AzureSQLManagedInstanceDelegation string = "Microsoft.Sql/managedInstances"
+	AzureNetAppFilesDelegation        string = "Microsoft.Netapp/volumes"
+	AzureDNSResolverDelegation        string = "Microsoft.Network/dnsResolvers"
+	AzureGatewaySubnet                string = "GatewaySubnet"
+	AzureFirewallSubnet               string = "AzureFirewallSubnet"
+	AzureFirewallManagementSubnet     string = "AzureFirewallManagementSubnet"
+	AzureBastionSubnet                string = "AzureBastionSubnet"
+	AzureRouteServerSubnet            string = "RouteServerSubnet"
+)
+
+// This set of constants defines the next hop types of Azure route table (UDR) routes
+const (
+	AzureNextHopVirtualNetworkGateway string = "VirtualNetworkGateway"
+	AzureNextHopVnetLocal             string = "VnetLocal"
+	AzureNextHopInternet              string = "Internet"
+	AzureNextHopVirtualAppliance      string = "VirtualAppliance"
+	AzureNextHopNone                  string = "None"
+)
+
+// This set of constants defines the Azure resource types and limits used by the exporters
+const (
+	AzureIPGroupType         string = "Microsoft.Network/ipGroups"
+	AzureIPGroupAPIVersion   string = "2023-04-01"
+	AzureIPGroupMaxAddresses int    = 5000
+)
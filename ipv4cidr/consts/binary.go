@@ -0,0 +1,10 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package consts
+
+// This set of constants defines the header of the binary encoding of CIDR lists
+const (
+	BinaryMagic   string = "CIDR"
+	BinaryVersion byte   = 1
+)
@@ -0,0 +1,28 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package consts
+
+// This set of constants defines the stable, machine-readable codes attached to the errors returned by this package
+const (
+	InvalidInputCode      string = "CIDR_INVALID_INPUT"
+	NotStandardizedCode   string = "CIDR_NOT_STANDARDIZED"
+	SplitNotPossibleCode  string = "CIDR_SPLIT_NOT_POSSIBLE"
+	OutOfRangeCode        string = "CIDR_OUT_OF_RANGE"
+	InvalidMaskCode       string = "CIDR_INVALID_MASK"
+	EmptyInputCode        string = "CIDR_EMPTY_INPUT"
+	InvalidPatchCode      string = "PATCH_INVALID_OPERATION"
+	PatchConflictCode     string = "PATCH_CONFLICT"
+	InvalidBinaryCode     string = "BINARY_INVALID_FORMAT"
+	UnsupportedFamilyCode string = "CIDR_UNSUPPORTED_FAMILY"
+	InvalidDHCPOptionCode string = "DHCP_INVALID_OPTION"
+	NotWithinParentCode   string = "CIDR_NOT_WITHIN_PARENT"
+	InvalidACLCode        string = "ACL_INVALID_RULE"
+	InvalidNextHopCode    string = "EXPORT_INVALID_NEXT_HOP"
+	InvalidFeedRecordCode string = "FEED_INVALID_RECORD"
+	LookupFailedCode      string = "RDAP_LOOKUP_FAILED"
+	InvalidExpressionCode string = "EXPR_INVALID"
+	MergeNotPossibleCode  string = "CIDR_MERGE_NOT_POSSIBLE"
+	PlanConflictCode      string = "PLAN_CONFLICT"
+	SizeMismatchCode      string = "CIDR_SIZE_MISMATCH"
+)
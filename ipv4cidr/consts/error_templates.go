@@ -0,0 +1,16 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package consts
+
+// ErrorTemplates is the default catalog of detailed error messages, keyed by error code. Each template embeds the offending input, so that the detail of an error
+// says what was wrong with it, e.g. "mask 36 out of range [0,32] in \"10.0.0.0/36\"". Only the codes below have a template, and only the errors created with their input
+// use it (parsing, mask, index and containment errors). Every other error uses its generic message as its detail.
+// The map is read once at init and must not be modified. Use ipv4cidr.SetErrorTemplate to replace a template, e.g. to translate it
+var ErrorTemplates = map[string]string{
+	InvalidInputCode:    "%s in %q",                                // reason, input
+	NotStandardizedCode: "%q is not standardized, it should be %s", // input, standardized CIDR range
+	InvalidMaskCode:     "mask %d out of range [%d,%d]",            // mask, smallest allowed mask, largest allowed mask
+	OutOfRangeCode:      "%d out of range [%d,%d]",                 // value, smallest allowed value, largest allowed value
+	NotWithinParentCode: "%s is not within %s",                     // CIDR range, parent CIDR range
+}
@@ -6,7 +6,43 @@ package consts
 // This set of constants defines strings corresponding to the new errors introduced in this package
 const (
 	InvalidIPv4CIDRError             string = "IP address is invalid, it should be of the format a.b.c.d or a.b.c.d/e, where 0 <= a, b, c, d < 256 and 0 <= e <= 32"
+	InvalidIPv4Error                 string = "IP address is invalid, it should be of the format a.b.c.d, where 0 <= a, b, c, d < 256"
 	NonStandardizedIPError           string = "IP address is not standardized, the IP part of IP/CIDR should be the first IP in the range"
 	NoMoreSplittingPossibleError     string = "There is only one IP address in this CIDR range, further splitting is not possible"
 	RequestedIPExceedsCIDRRangeError string = "Requested IP exceeds the CIDR range"
+	InvalidChildMaskError            string = "Requested mask should be between the mask of the CIDR range and 32"
+	EmptyInputError                  string = "At least one IP address or CIDR range is required"
+	InvalidPatchOperationError       string = "Patch operation should be either \"add\" or \"remove\""
+	PatchAddConflictError            string = "CIDR range to add is already in the list"
+	InvalidBinaryFormatError         string = "Binary data is not a valid encoded list of CIDR ranges"
+	UnsupportedFamilyError           string = "IPv6 CIDR ranges are not supported yet"
+	InvalidDHCPOptionError           string = "DHCP option data is not a valid list of classless static routes"
+	NotWithinParentError             string = "CIDR range is not within the parent CIDR range"
+	ParentSizeMismatchError          string = "Parent CIDR ranges should be of the same size"
+	InvalidACLActionError            string = "ACL rule action should be either \"allow\" or \"deny\""
+	InvalidMaskError                 string = "Mask should be between 0 and 32"
+	NoAlignedBlockError              string = "No block of the requested size starts at or after the IP address"
+	IPNotInCIDRRangeError            string = "IP address is not in the CIDR range"
+	EndOfAddressSpaceError           string = "There is no IP address beyond the bounds of the IPv4 address space"
+	UnsupportedScanVerbError         string = "CIDR ranges can only be scanned with the 'v' and 's' verbs"
+	SubnetIndexOutOfRangeError       string = "Requested subnet index exceeds the number of subnets of that size in the CIDR range"
+	InvalidNextHopError              string = "Next hop type should be VirtualNetworkGateway, VnetLocal, Internet, VirtualAppliance or None, and a next hop IP address is required for VirtualAppliance only"
+	InvalidDelegationRecordError     string = "RIR delegation record is invalid, it should be of the format registry|cc|type|start|value|date|status"
+	InvalidMRTRecordError            string = "MRT record is truncated or malformed"
+	RDAPLookupFailedError            string = "RDAP server did not return an IPv4 network registration for the CIDR range"
+	InvalidExpressionError           string = "Expression is invalid, it should combine within, overlaps, contains, private, shared, host and mask comparisons with &&, || and !"
+	UndefinedSetError                string = "Expression references a set that is not defined"
+	NotSiblingsError                 string = "CIDR ranges cannot be merged, they should be the two halves of the same CIDR range"
+	InvalidSubnetCountError          string = "Number of subnets should be a power of two, and no more than the number of IP addresses in the CIDR range"
+	InvalidGrowthRateError           string = "Growth rate should be a number greater than -1"
+	SizeExceedsAddressSpaceError     string = "Projected number of addresses exceeds the IPv4 address space"
+	InsufficientSpaceError           string = "CIDR range is too small to hold subnets for all the requested host counts"
+	NoSupernetError                  string = "The entire IPv4 address space (/0) has no supernet"
+	BreakdownTooLargeError           string = "Child mask should be at most 16 bits longer than the parent mask"
+	MissingACLCIDRError              string = "ACL rule should have a CIDR range"
+	MigrationTooLargeError           string = "Prefix length should be at most 16 bits longer than the mask of each subnet to migrate"
+	NilCIDRError                     string = "CIDR range should not be nil"
+	SubnetNotInPlanError             string = "Subnet to remove is not in the plan"
+	InvalidIPRangeError              string = "Last IP address of the range should not be before the first IP address"
+	PatchRemoveConflictError         string = "CIDR range to remove is not in the list"
 )
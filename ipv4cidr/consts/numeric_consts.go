@@ -11,3 +11,9 @@ const (
 	GroupSize     uint8  = 8
 	HighestBitSet uint32 = uint32(1) << (MaxBits - 1)
 )
+
+// MaxBreakdownBits is the largest difference between the child mask and the parent mask of a coverage breakdown, i.e. at most 65536 child subnets are reported
+const MaxBreakdownBits uint8 = 16
+
+// MaxMigrationBits is the largest number of bits a subnet can be split by when migrating an address plan, i.e. a subnet is split into at most 65536 subnets
+const MaxMigrationBits uint8 = 16
@@ -0,0 +1,10 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package consts
+
+// This set of constants defines the operations supported in a patch
+const (
+	PatchAdd    string = "add"
+	PatchRemove string = "remove"
+)
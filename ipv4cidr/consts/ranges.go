@@ -0,0 +1,12 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package consts
+
+// This set of constants defines well-known special-purpose CIDR ranges
+const (
+	PrivateRange10     string = "10.0.0.0/8"
+	PrivateRange172    string = "172.16.0.0/12"
+	PrivateRange192    string = "192.168.0.0/16"
+	SharedAddressRange string = "100.64.0.0/10"
+)
@@ -0,0 +1,15 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package consts
+
+import "time"
+
+// RDAPBootstrapURL is the RDAP service redirecting each query to the authoritative registry
+const RDAPBootstrapURL string = "https://rdap.org"
+
+// RDAPMaxResponseSize is the number of bytes of an RDAP response read at most (1 MiB), far more than an IP network object takes
+const RDAPMaxResponseSize int64 = 1 << 20
+
+// RDAPTimeout is the time limit of an RDAP request made by the default RDAP client, so a stalled server does not block lookups forever
+const RDAPTimeout time.Duration = 30 * time.Second
@@ -0,0 +1,12 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package consts
+
+// This set of constants defines the statuses of records in RIR delegated-extended statistics files
+const (
+	RIRAllocated string = "allocated"
+	RIRAssigned  string = "assigned"
+	RIRAvailable string = "available"
+	RIRReserved  string = "reserved"
+)
@@ -0,0 +1,12 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package consts
+
+// This set of constants defines the number of addresses reserved in every subnet by common platforms, for subnet sizing
+const (
+	NetworkAndBroadcastReservedAddresses uint64 = 2
+	AzureReservedAddresses               uint64 = 5
+	AWSReservedAddresses                 uint64 = 5
+	GCPReservedAddresses                 uint64 = 4
+)
@@ -0,0 +1,27 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package consts
+
+// This set of constants defines the names of the validation rules
+const (
+	MinPrefixLengthRule string = "min-prefix-length"
+	MaxPrefixLengthRule string = "max-prefix-length"
+	WithinRule          string = "within"
+	NotOverlappingRule  string = "not-overlapping"
+	PrivateRule         string = "private"
+	PrivateOrSharedRule string = "private-or-shared"
+	AzureDelegationRule string = "azure-delegation"
+)
+
+// This set of constants defines the format strings of the messages describing validation rule violations
+const (
+	MinPrefixLengthViolation         string = "Mask /%d is shorter than the minimum allowed mask /%d"
+	MaxPrefixLengthViolation         string = "Mask /%d is longer than the maximum allowed mask /%d"
+	WithinViolation                  string = "%s is not within any of the allowed ranges %s"
+	NotOverlappingViolation          string = "%s overlaps the reserved range %s"
+	PrivateViolation                 string = "%s is not within the private (RFC 1918) address space"
+	PrivateOrSharedViolation         string = "%s is not within the private (RFC 1918) or shared (RFC 6598) address space"
+	AzureDelegationTooSmallViolation string = "%s is a /%d, but %s requires a subnet of at least /%d"
+	AzureDelegationUnknownViolation  string = "No Azure subnet size requirement is known for %q"
+)
@@ -0,0 +1,52 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+//go:build cidrdebug
+// +build cidrdebug
+
+package ipv4cidr
+
+import (
+	"fmt"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/utils"
+)
+
+// This file is only built with the cidrdebug build tag (go test -tags cidrdebug ./...), which makes the internal arithmetic of the package
+// check its invariants and panic with diagnostics as soon as one is broken. Without the tag, the checks in debug_disabled.go are no-ops
+
+// checkInvariants panics if a CIDR range is not consistent: mask between 0 and 32, netmask and range length matching the mask, and standardized IP
+// @input i *IPv4CIDR: The CIDR range to check
+// @input operation string: The name of the operation that produced the CIDR range, reported in the panic
+func checkInvariants(i *IPv4CIDR, operation string) {
+
+	if i.mask > consts.MaxBits {
+		panic(fmt.Sprintf("ipv4cidr: %s produced an invalid mask /%d", operation, i.mask))
+	}
+
+	if netmask := utils.GetNetmask(i.mask); i.netmask != netmask {
+		panic(fmt.Sprintf("ipv4cidr: %s produced netmask %s for /%d, expected %s", operation, utils.ConvertIPToString(i.netmask), i.mask, utils.ConvertIPToString(netmask)))
+	}
+
+	if rangeLength := utils.GetCIDRRangeLength(i.mask); i.rangeLength != rangeLength {
+		panic(fmt.Sprintf("ipv4cidr: %s produced range length %d for /%d, expected %d", operation, i.rangeLength, i.mask, rangeLength))
+	}
+
+	if utils.Standardize(i.ip, i.netmask) != i.ip {
+		panic(fmt.Sprintf("ipv4cidr: %s produced non-standardized CIDR range %s/%d", operation, utils.ConvertIPToString(i.ip), i.mask))
+	}
+
+}
+
+// checkOffset panics if adding an offset to the first IP address of a CIDR range would step outside of it, e.g. by overflowing 32 bits
+// @input i *IPv4CIDR: The CIDR range the offset is relative to
+// @input offset uint64: The offset from the first IP address of the CIDR range
+// @input operation string: The name of the operation computing the offset, reported in the panic
+func checkOffset(i *IPv4CIDR, offset uint64, operation string) {
+
+	if offset >= utils.GetCIDRRangeLength64(i.mask) {
+		panic(fmt.Sprintf("ipv4cidr: %s computed offset %d outside of %s, which has %d IP addresses", operation, offset, i.ToString(), utils.GetCIDRRangeLength64(i.mask)))
+	}
+
+}
@@ -0,0 +1,13 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+//go:build !cidrdebug
+// +build !cidrdebug
+
+package ipv4cidr
+
+// checkInvariants does nothing unless the package is built with the cidrdebug build tag, see debug.go
+func checkInvariants(i *IPv4CIDR, operation string) {}
+
+// checkOffset does nothing unless the package is built with the cidrdebug build tag, see debug.go
+func checkOffset(i *IPv4CIDR, offset uint64, operation string) {}
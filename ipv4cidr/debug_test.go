@@ -0,0 +1,53 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+//go:build cidrdebug
+// +build cidrdebug
+
+package ipv4cidr
+
+import (
+	"testing"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCheckInvariants checks consistent and corrupted CIDR ranges with the cidrdebug build tag
+// Success Metric: Consistent CIDR ranges pass, and each broken invariant panics
+func TestCheckInvariants(t *testing.T) {
+
+	assert.NotPanics(t, func() { checkInvariants(mustParse("10.0.0.0/24"), "test") })
+	assert.NotPanics(t, func() { checkInvariants(mustParse("0.0.0.0/0"), "test") })
+
+	valid := mustParse("10.0.0.0/24")
+
+	for name, corrupt := range map[string]func(i *IPv4CIDR){
+		"mask":         func(i *IPv4CIDR) { i.mask = 33 },
+		"netmask":      func(i *IPv4CIDR) { i.netmask = utils.GetNetmask(23) },
+		"range length": func(i *IPv4CIDR) { i.rangeLength = 255 },
+		"standardized": func(i *IPv4CIDR) { i.ip++ },
+	} {
+
+		broken := *valid
+		corrupt(&broken)
+		assert.Panics(t, func() { checkInvariants(&broken, "test") }, "A CIDR range with a broken %s should panic", name)
+
+	}
+
+}
+
+// TestCheckOffset checks offsets within and outside of CIDR ranges with the cidrdebug build tag
+// Success Metric: Offsets within the CIDR range pass, and offsets outside of it, including underflows, panic
+func TestCheckOffset(t *testing.T) {
+
+	CIDR := mustParse("10.0.0.0/24")
+
+	assert.NotPanics(t, func() { checkOffset(CIDR, 0, "test") })
+	assert.NotPanics(t, func() { checkOffset(CIDR, 255, "test") })
+	assert.Panics(t, func() { checkOffset(CIDR, 256, "test") })
+
+	assert.Panics(t, func() { CIDR.GetIPInRange(0, false) }, "The 0th IP address is before the CIDR range")
+
+}
@@ -0,0 +1,115 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/utils"
+)
+
+// StaticRoute models a classless static route, as carried in DHCP option 121 (RFC 3442)
+// @field Destination *IPv4CIDR: The destination CIDR range
+// @field Router string: The IP address of the router, in format a.b.c.d
+type StaticRoute struct {
+	Destination *IPv4CIDR
+	Router      string
+}
+
+// EncodeClasslessStaticRoutes encodes a list of static routes into the data of DHCP option 121 (RFC 3442)
+// Each route is encoded as the mask (1 byte), the significant octets of the destination, and the router (4 bytes).
+// Data longer than 255 bytes has to be split across multiple options by the caller (RFC 3396).
+// @input routes []StaticRoute: The routes to encode
+// @returns []byte: The option data, without the option code and length
+// @returns error: If a router is not a valid IP address, an error is returned
+func EncodeClasslessStaticRoutes(routes []StaticRoute) ([]byte, error) {
+
+	data := make([]byte, 0, len(routes)*9)
+	for _, route := range routes {
+
+		router, err := utils.ParseIPUint32(route.Router)
+		if err != nil {
+			return nil, err
+		}
+
+		data = append(data, route.Destination.mask)
+		data = appendOctets(data, route.Destination.ip, significantOctets(route.Destination.mask))
+		data = appendOctets(data, router, 4)
+
+	}
+
+	return data, nil
+
+}
+
+// DecodeClasslessStaticRoutes decodes the data of DHCP option 121 (RFC 3442) into a list of static routes
+// @input data []byte: The option data, without the option code and length
+// @returns []StaticRoute: The decoded routes, in order
+// @returns error: If the data is not a valid list of classless static routes, an error is returned
+func DecodeClasslessStaticRoutes(data []byte) ([]StaticRoute, error) {
+
+	routes := make([]StaticRoute, 0)
+	for position := 0; position < len(data); {
+
+		mask := data[position]
+		if mask > consts.MaxBits {
+			return nil, utils.NewError(consts.InvalidDHCPOptionCode, consts.InvalidDHCPOptionError)
+		}
+
+		octets := significantOctets(mask)
+		if position+1+octets+4 > len(data) {
+			return nil, utils.NewError(consts.InvalidDHCPOptionCode, consts.InvalidDHCPOptionError)
+		}
+
+		destination := readOctets(data[position+1:position+1+octets]) << (consts.GroupSize * uint8(4-octets))
+		router := readOctets(data[position+1+octets : position+1+octets+4])
+
+		routes = append(routes, StaticRoute{
+			Destination: fromIPAndMask(destination, mask),
+			Router:      utils.ConvertIPToString(router),
+		})
+		position += 1 + octets + 4
+
+	}
+
+	return routes, nil
+
+}
+
+// significantOctets returns the number of octets of the destination that are encoded for a given mask
+// @input mask uint8: The mask of the destination
+// @returns int: Number of significant octets (0-4)
+func significantOctets(mask uint8) int {
+
+	return int((mask + consts.GroupSize - 1) / consts.GroupSize)
+
+}
+
+// appendOctets appends the most significant octets of an IP address to a byte slice
+// @input data []byte: The byte slice to append to
+// @input ip uint32: The IP address in integer representation
+// @input octets int: Number of octets to append (0-4)
+// @returns []byte: The extended byte slice
+func appendOctets(data []byte, ip uint32, octets int) []byte {
+
+	for n := 0; n < octets; n++ {
+		data = append(data, byte(ip>>(consts.GroupSize*uint8(3-n))))
+	}
+
+	return data
+
+}
+
+// readOctets reads a big-endian integer from up to 4 octets
+// @input octets []byte: The octets
+// @returns uint32: The integer value
+func readOctets(octets []byte) uint32 {
+
+	value := uint32(0)
+	for _, octet := range octets {
+		value = value<<consts.GroupSize | uint32(octet)
+	}
+
+	return value
+
+}
@@ -0,0 +1,85 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"testing"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEncodeClasslessStaticRoutes encodes routes with destinations of various masks
+// Success Metric: The encoding matches the examples of RFC 3442
+func TestEncodeClasslessStaticRoutes(t *testing.T) {
+
+	destinations := parseAll(t, "10.17.0.0/16", "10.27.129.0/24", "0.0.0.0/0", "10.229.0.128/25")
+	routes := []StaticRoute{
+		{Destination: destinations[0], Router: "10.0.0.1"},
+		{Destination: destinations[1], Router: "10.0.0.2"},
+		{Destination: destinations[2], Router: "10.0.0.254"},
+		{Destination: destinations[3], Router: "10.0.0.3"},
+	}
+
+	data, err := EncodeClasslessStaticRoutes(routes)
+	assert.Nil(t, err, "All routes are valid, they should be encoded.")
+
+	expected := []byte{
+		16, 10, 17, 10, 0, 0, 1,
+		24, 10, 27, 129, 10, 0, 0, 2,
+		0, 10, 0, 0, 254,
+		25, 10, 229, 0, 128, 10, 0, 0, 3,
+	}
+	assert.Equal(t, expected, data)
+
+	decoded, err := DecodeClasslessStaticRoutes(data)
+	assert.Nil(t, err, "The encoded data is valid, it should be decoded.")
+	if assert.Len(t, decoded, 4) {
+
+		for n := range routes {
+			assert.Equal(t, routes[n].Destination.ToString(), decoded[n].Destination.ToString())
+			assert.Equal(t, routes[n].Router, decoded[n].Router)
+		}
+
+	}
+
+}
+
+// TestEncodeClasslessStaticRoutesInvalidRouter encodes a route with an invalid router
+// Success Metric: Throw an error saying the IP is invalid
+func TestEncodeClasslessStaticRoutesInvalidRouter(t *testing.T) {
+
+	destination, _ := NewIPv4CIDR("10.0.0.0/8", false)
+	_, err := EncodeClasslessStaticRoutes([]StaticRoute{{Destination: destination, Router: "10.0.0.256"}})
+	if assert.Error(t, err, "10.0.0.256 is an invalid router. An error should be thrown.") {
+
+		assert.Equal(t, consts.InvalidIPv4Error, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidIPv4Error)
+
+	}
+
+}
+
+// TestDecodeClasslessStaticRoutesInvalidData decodes truncated and malformed option data
+// Success Metric: Throw an error saying the option data is invalid
+func TestDecodeClasslessStaticRoutesInvalidData(t *testing.T) {
+
+	testInputs := [][]byte{
+		{33, 10, 0, 0, 0, 10, 0, 0, 1},
+		{16, 10, 17, 10, 0, 0},
+		{24, 10, 27},
+	}
+
+	for _, input := range testInputs {
+
+		_, err := DecodeClasslessStaticRoutes(input)
+		if assert.Error(t, err, "%v is invalid option data. An error should be thrown.", input) {
+
+			assert.Equal(t, consts.InvalidDHCPOptionError, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidDHCPOptionError)
+
+		}
+
+	}
+
+}
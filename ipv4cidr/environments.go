@@ -0,0 +1,137 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"sort"
+)
+
+// EnvironmentConflict describes two CIDR ranges from different environments that share IP addresses
+// @field FirstEnvironment string: Name of the environment containing the first CIDR range
+// @field SecondEnvironment string: Name of the environment containing the second CIDR range
+// @field Overlap Overlap: The overlapping CIDR ranges and the region they share
+type EnvironmentConflict struct {
+	FirstEnvironment  string
+	SecondEnvironment string
+	Overlap           Overlap
+}
+
+// EnvironmentAdjacency describes two CIDR ranges from different environments that are contiguous, i.e. the first ends right before the second begins
+// @field FirstEnvironment string: Name of the environment containing the lower CIDR range
+// @field SecondEnvironment string: Name of the environment containing the higher CIDR range
+// @field First *IPv4CIDR: The lower CIDR range
+// @field Second *IPv4CIDR: The higher CIDR range
+type EnvironmentAdjacency struct {
+	FirstEnvironment  string
+	SecondEnvironment string
+	First             *IPv4CIDR
+	Second            *IPv4CIDR
+}
+
+// EnvironmentReport is the consolidated result of comparing the address space of multiple environments
+// @field Conflicts []EnvironmentConflict: Every pair of overlapping CIDR ranges across environments, largest overlap first
+// @field Adjacencies []EnvironmentAdjacency: Every pair of contiguous CIDR ranges across environments, in order of IP
+type EnvironmentReport struct {
+	Conflicts   []EnvironmentConflict
+	Adjacencies []EnvironmentAdjacency
+}
+
+// environmentCIDR tags a CIDR range with the name of the environment it belongs to
+type environmentCIDR struct {
+	environment string
+	cidr        *IPv4CIDR
+}
+
+// AnalyzeEnvironments compares the address space of multiple environments (e.g. subscriptions, accounts or sites) and reports conflicts and adjacencies between them
+// Ranges within the same environment are not compared with each other.
+// @input environments map[string][]string: Map of environment name to the CIDR ranges it uses, in format a.b.c.d/e or a.b.c.d
+// @returns *EnvironmentReport: The conflicts and adjacencies across environments
+// @returns error: If any CIDR range is invalid, the appropriate error is returned
+func AnalyzeEnvironments(environments map[string][]string) (*EnvironmentReport, error) {
+
+	// Visit environments in a fixed order so that the report is deterministic
+	names := make([]string, 0, len(environments))
+	for name := range environments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	entries := make([]environmentCIDR, 0)
+	for _, name := range names {
+		for _, input := range environments[name] {
+
+			cidr, err := NewIPv4CIDR(input, false)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, environmentCIDR{environment: name, cidr: cidr})
+
+		}
+	}
+
+	sort.SliceStable(entries, func(a, b int) bool {
+		return entries[a].cidr.Compare(entries[b].cidr) < 0
+	})
+
+	report := EnvironmentReport{
+		Conflicts:   make([]EnvironmentConflict, 0),
+		Adjacencies: make([]EnvironmentAdjacency, 0),
+	}
+
+	// Index the entries by their first IP to find the blocks starting right after each block
+	byStart := make(map[uint64][]environmentCIDR)
+	for _, entry := range entries {
+		byStart[uint64(entry.cidr.ip)] = append(byStart[uint64(entry.cidr.ip)], entry)
+	}
+
+	for a := range entries {
+
+		first := entries[a]
+
+		// Only the ranges starting before the end of this one can overlap it, and those follow it directly in sorted order
+		for b := a + 1; b < len(entries) && entries[b].cidr.ip <= first.cidr.lastIP(); b++ {
+
+			second := entries[b]
+			if first.environment == second.environment {
+				continue
+			}
+
+			region := intersect(first.cidr, second.cidr)
+			report.Conflicts = append(report.Conflicts, EnvironmentConflict{
+				FirstEnvironment:  first.environment,
+				SecondEnvironment: second.environment,
+				Overlap: Overlap{
+					First:  first.cidr,
+					Second: second.cidr,
+					Region: region,
+					Size:   region.size(),
+				},
+			})
+
+		}
+
+		for _, second := range byStart[uint64(first.cidr.lastIP())+1] {
+
+			if first.environment == second.environment {
+				continue
+			}
+
+			report.Adjacencies = append(report.Adjacencies, EnvironmentAdjacency{
+				FirstEnvironment:  first.environment,
+				SecondEnvironment: second.environment,
+				First:             first.cidr,
+				Second:            second.cidr,
+			})
+
+		}
+
+	}
+
+	sort.SliceStable(report.Conflicts, func(a, b int) bool {
+		return report.Conflicts[a].Overlap.Size > report.Conflicts[b].Overlap.Size
+	})
+
+	return &report, nil
+
+}
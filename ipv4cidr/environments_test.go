@@ -0,0 +1,70 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"testing"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAnalyzeEnvironments compares the address space of three environments
+// Success Metric: Conflicts and adjacencies across environments are reported, but not within an environment
+func TestAnalyzeEnvironments(t *testing.T) {
+
+	environments := map[string][]string{
+		"contoso":  {"10.0.0.0/16", "10.1.0.0/16"},
+		"fabrikam": {"10.0.128.0/24", "10.2.0.0/16"},
+		"tailspin": {"10.1.0.0/24", "192.168.0.0/16"},
+	}
+
+	report, err := AnalyzeEnvironments(environments)
+	assert.Nil(t, err, "All CIDR ranges are valid, a report should be created.")
+
+	if assert.Len(t, report.Conflicts, 2) {
+
+		assert.Equal(t, "contoso", report.Conflicts[0].FirstEnvironment)
+		assert.Equal(t, "fabrikam", report.Conflicts[0].SecondEnvironment)
+		assert.Equal(t, "10.0.128.0/24", report.Conflicts[0].Overlap.Region.ToString())
+
+		assert.Equal(t, "contoso", report.Conflicts[1].FirstEnvironment)
+		assert.Equal(t, "tailspin", report.Conflicts[1].SecondEnvironment)
+		assert.Equal(t, "10.1.0.0/24", report.Conflicts[1].Overlap.Region.ToString())
+
+	}
+
+	if assert.Len(t, report.Adjacencies, 2) {
+
+		assert.Equal(t, "contoso", report.Adjacencies[0].FirstEnvironment)
+		assert.Equal(t, "tailspin", report.Adjacencies[0].SecondEnvironment)
+		assert.Equal(t, "10.0.0.0/16", report.Adjacencies[0].First.ToString())
+		assert.Equal(t, "10.1.0.0/24", report.Adjacencies[0].Second.ToString())
+
+		assert.Equal(t, "contoso", report.Adjacencies[1].FirstEnvironment)
+		assert.Equal(t, "fabrikam", report.Adjacencies[1].SecondEnvironment)
+		assert.Equal(t, "10.1.0.0/16", report.Adjacencies[1].First.ToString())
+		assert.Equal(t, "10.2.0.0/16", report.Adjacencies[1].Second.ToString())
+
+	}
+
+}
+
+// TestAnalyzeEnvironmentsInvalidInput compares environments where one of the CIDR ranges is invalid
+// Success Metric: Throw an error saying the CIDR range is invalid
+func TestAnalyzeEnvironmentsInvalidInput(t *testing.T) {
+
+	environments := map[string][]string{
+		"contoso": {"10.0.0.0/33"},
+	}
+
+	_, err := AnalyzeEnvironments(environments)
+	if assert.Error(t, err, "10.0.0.0/33 is an invalid CIDR block. An error should be thrown.") {
+
+		assert.Equal(t, consts.InvalidIPv4CIDRError, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidIPv4CIDRError)
+
+	}
+
+}
@@ -0,0 +1,103 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/utils"
+)
+
+// GetErrorCode returns the machine-readable code of an error returned by this package, so callers can map errors to statuses without string matching
+// @input err error: The error returned by this package, possibly wrapped
+// @returns string: The error code (e.g. CIDR_INVALID_INPUT), or an empty string if the error does not carry a code
+func GetErrorCode(err error) string {
+
+	var codedErr *utils.Error
+	if errors.As(err, &codedErr) {
+		return codedErr.Code
+	}
+
+	return ""
+
+}
+
+// GetErrorDetail returns the detailed message of an error returned by this package, embedding the offending input, e.g. "mask 36 out of range [0,32] in \"10.0.0.0/36\""
+// @input err error: The error returned by this package, possibly wrapped
+// @returns string: The detailed message, the message of the error if it does not carry a detail, or an empty string if the error is nil
+func GetErrorDetail(err error) string {
+
+	if err == nil {
+		return ""
+	}
+
+	var codedErr *utils.Error
+	if errors.As(err, &codedErr) {
+		return codedErr.Detail
+	}
+
+	return err.Error()
+
+}
+
+// SetErrorTemplate replaces the template of the detailed messages of an error code, e.g. to translate them. It is safe to call concurrently with the rest of the package
+// @input code string: The error code, e.g. consts.InvalidMaskCode
+// @input template string: The template, taking the same parameters as the one in consts.ErrorTemplates. If empty, errors of the code use their message as detail
+func SetErrorTemplate(code string, template string) {
+
+	utils.SetErrorTemplate(code, template)
+
+}
+
+// newInvalidInputError creates the error returned for an input that is not a valid IP address or CIDR range, with a detail saying which part of it is invalid
+// @input input string: The invalid input
+// @returns error: The new error
+func newInvalidInputError(input string) error {
+
+	return utils.NewDetailedError(consts.InvalidInputCode, consts.InvalidIPv4CIDRError, diagnoseInput(input), input)
+
+}
+
+// diagnoseInput finds the reason why an input is not a valid IP address or CIDR range
+// @input input string: The invalid input
+// @returns string: The reason, e.g. "mask 36 out of range [0,32]"
+func diagnoseInput(input string) string {
+
+	sections := strings.Split(input, "/")
+	if len(sections) > 2 {
+		return "expected at most one \"/\""
+	}
+
+	if len(sections) == 2 {
+		mask, err := strconv.Atoi(sections[1])
+		if err != nil {
+			return fmt.Sprintf("mask %q is not a number", sections[1])
+		}
+		if mask < 0 || mask > int(consts.MaxBits) {
+			return fmt.Sprintf("mask %d out of range [0,%d]", mask, consts.MaxBits)
+		}
+	}
+
+	octets := strings.Split(sections[0], ".")
+	if len(octets) != 4 {
+		return fmt.Sprintf("expected 4 octets, found %d", len(octets))
+	}
+
+	for _, octet := range octets {
+		value, err := strconv.Atoi(octet)
+		if err != nil {
+			return fmt.Sprintf("octet %q is not a number", octet)
+		}
+		if value < 0 || value > int(consts.EightBits) {
+			return fmt.Sprintf("octet %d out of range [0,%d]", value, consts.EightBits)
+		}
+	}
+
+	return "expected the format a.b.c.d or a.b.c.d/e"
+
+}
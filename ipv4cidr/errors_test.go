@@ -0,0 +1,111 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGetErrorCode gets the code of errors returned by the package
+// Success Metric: The matching code is returned, also for wrapped errors
+func TestGetErrorCode(t *testing.T) {
+
+	_, err := NewIPv4CIDR("10.10.0.0/33", false)
+	assert.Equal(t, consts.InvalidInputCode, GetErrorCode(err))
+
+	_, err = NewIPv4CIDR("10.10.0.1/26", false)
+	assert.Equal(t, consts.NotStandardizedCode, GetErrorCode(err))
+
+	CIDR, _ := NewIPv4CIDR("10.10.0.0/32", false)
+	_, _, err = CIDR.Split()
+	assert.Equal(t, consts.SplitNotPossibleCode, GetErrorCode(err))
+
+	_, err = CIDR.GetIPInRange(2, false)
+	assert.Equal(t, consts.OutOfRangeCode, GetErrorCode(err))
+
+	wrapped := fmt.Errorf("could not plan subnet: %w", err)
+	assert.Equal(t, consts.OutOfRangeCode, GetErrorCode(wrapped), "Wrapped errors should keep their code")
+
+}
+
+// TestGetErrorCodeForeignError gets the code of errors not returned by the package
+// Success Metric: An empty code is returned
+func TestGetErrorCodeForeignError(t *testing.T) {
+
+	assert.Equal(t, "", GetErrorCode(errors.New("some other error")))
+	assert.Equal(t, "", GetErrorCode(nil))
+
+}
+
+// TestGetErrorDetail gets the detail of errors returned by the package
+// Success Metric: The detail embeds the offending input and says what is wrong with it, also for wrapped errors
+func TestGetErrorDetail(t *testing.T) {
+
+	for input, expected := range map[string]string{
+		"10.0.0.0/36":   "mask 36 out of range [0,32] in \"10.0.0.0/36\"",
+		"10.0.0.0/x":    "mask \"x\" is not a number in \"10.0.0.0/x\"",
+		"10.0.0.0/8/8":  "expected at most one \"/\" in \"10.0.0.0/8/8\"",
+		"10.0.0/24":     "expected 4 octets, found 3 in \"10.0.0/24\"",
+		"10.0.a.0/24":   "octet \"a\" is not a number in \"10.0.a.0/24\"",
+		"10.0.256.0/24": "octet 256 out of range [0,255] in \"10.0.256.0/24\"",
+		"10.10.0.0/05":  "expected the format a.b.c.d or a.b.c.d/e in \"10.10.0.0/05\"",
+		"10.10.0.1/26":  "\"10.10.0.1/26\" is not standardized, it should be 10.10.0.0/26",
+	} {
+
+		_, err := NewIPv4CIDR(input, false)
+		assert.Equal(t, expected, GetErrorDetail(err), "Detail of the error for %s", input)
+
+	}
+
+	CIDR, _ := NewIPv4CIDR("10.0.0.0/24", false)
+
+	_, err := CIDR.NthSubnet(20, 0)
+	assert.Equal(t, "mask 20 out of range [24,32]", GetErrorDetail(err))
+
+	_, err = CIDR.NthSubnet(26, 4)
+	assert.Equal(t, "4 out of range [0,3]", GetErrorDetail(err))
+
+	_, err = CIDR.Widen(30)
+	assert.Equal(t, "mask -6 out of range [0,32]", GetErrorDetail(err))
+
+	parent, _ := NewIPv4CIDR("10.1.0.0/16", false)
+	_, err = CIDR.SubnetIndex(parent)
+	wrapped := fmt.Errorf("could not plan subnet: %w", err)
+	assert.Equal(t, "10.0.0.0/24 is not within 10.1.0.0/16", GetErrorDetail(wrapped), "Wrapped errors should keep their detail")
+
+}
+
+// TestSetErrorTemplate replaces the template of an error code, then restores it
+// Success Metric: Errors created afterwards use the new template, and an empty template falls back to the message
+func TestSetErrorTemplate(t *testing.T) {
+
+	defer SetErrorTemplate(consts.InvalidMaskCode, consts.ErrorTemplates[consts.InvalidMaskCode])
+
+	CIDR, _ := NewIPv4CIDR("10.0.0.0/24", false)
+
+	SetErrorTemplate(consts.InvalidMaskCode, "masque %d hors de [%d,%d]")
+	_, err := CIDR.Widen(30)
+	assert.Equal(t, "masque -6 hors de [0,32]", GetErrorDetail(err))
+	assert.Equal(t, consts.InvalidMaskError, err.Error(), "The message should not depend on the template")
+
+	SetErrorTemplate(consts.InvalidMaskCode, "")
+	_, err = CIDR.Widen(30)
+	assert.Equal(t, consts.InvalidMaskError, GetErrorDetail(err))
+
+}
+
+// TestGetErrorDetailForeignError gets the detail of errors not returned by the package
+// Success Metric: The message of the error is returned, or an empty string for nil
+func TestGetErrorDetailForeignError(t *testing.T) {
+
+	assert.Equal(t, "some other error", GetErrorDetail(errors.New("some other error")))
+	assert.Equal(t, "", GetErrorDetail(nil))
+
+}
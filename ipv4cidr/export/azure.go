@@ -0,0 +1,170 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package export
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/utils"
+)
+
+// AzureIPGroup models an Azure Firewall IP Group resource, as used in ARM templates
+// @field Type string: The resource type, Microsoft.Network/ipGroups
+// @field APIVersion string: The API version of the resource
+// @field Name string: The name of the IP Group
+// @field Location string: The Azure region of the IP Group
+// @field Properties AzureIPGroupProperties: The properties of the IP Group
+type AzureIPGroup struct {
+	Type       string                 `json:"type"`
+	APIVersion string                 `json:"apiVersion"`
+	Name       string                 `json:"name"`
+	Location   string                 `json:"location"`
+	Properties AzureIPGroupProperties `json:"properties"`
+}
+
+// AzureIPGroupProperties models the properties of an Azure Firewall IP Group
+// @field IPAddresses []string: The CIDR ranges of the IP Group, in format a.b.c.d/e
+type AzureIPGroupProperties struct {
+	IPAddresses []string `json:"ipAddresses"`
+}
+
+// AzureRoute models a route of an Azure route table (user-defined route), as used in ARM templates
+// @field Name string: The name of the route, unique within the route table
+// @field Properties AzureRouteProperties: The properties of the route
+type AzureRoute struct {
+	Name       string               `json:"name"`
+	Properties AzureRouteProperties `json:"properties"`
+}
+
+// AzureRouteProperties models the properties of an Azure route
+// @field AddressPrefix string: The destination CIDR range, in format a.b.c.d/e
+// @field NextHopType string: The type of the next hop, e.g. VirtualAppliance
+// @field NextHopIPAddress string: The IP address of the next hop, for VirtualAppliance routes only
+type AzureRouteProperties struct {
+	AddressPrefix    string `json:"addressPrefix"`
+	NextHopType      string `json:"nextHopType"`
+	NextHopIPAddress string `json:"nextHopIpAddress,omitempty"`
+}
+
+// AzureNextHop models the next hop of Azure routes
+// @field Type string: The type of the next hop, one of the consts.AzureNextHop* values
+// @field IPAddress string: The IP address of the next hop in format a.b.c.d, required for VirtualAppliance and empty otherwise
+type AzureNextHop struct {
+	Type      string
+	IPAddress string
+}
+
+// AzureIPGroups renders a list of CIDR ranges as Azure Firewall IP Groups
+// The list is aggregated first, and split into several IP Groups (named name-1, name-2, ...) if it exceeds the limit of addresses per IP Group
+// @input name string: The name of the IP Group
+// @input location string: The Azure region of the IP Group, e.g. westeurope
+// @input cidrs []*ipv4cidr.IPv4CIDR: The CIDR ranges
+// @returns []AzureIPGroup: The IP Groups, ready to be marshaled to JSON
+func AzureIPGroups(name string, location string, cidrs []*ipv4cidr.IPv4CIDR) []AzureIPGroup {
+
+	addresses := cidrStrings(ipv4cidr.Aggregate(cidrs))
+
+	groups := make([]AzureIPGroup, 0, len(addresses)/consts.AzureIPGroupMaxAddresses+1)
+	for start := 0; start == 0 || start < len(addresses); start += consts.AzureIPGroupMaxAddresses {
+
+		end := start + consts.AzureIPGroupMaxAddresses
+		if end > len(addresses) {
+			end = len(addresses)
+		}
+
+		groups = append(groups, AzureIPGroup{
+			Type:       consts.AzureIPGroupType,
+			APIVersion: consts.AzureIPGroupAPIVersion,
+			Location:   location,
+			Properties: AzureIPGroupProperties{
+				IPAddresses: addresses[start:end],
+			},
+		})
+
+	}
+
+	// Only number the IP Groups if the list had to be split
+	for n := range groups {
+		groups[n].Name = name
+		if len(groups) > 1 {
+			groups[n].Name = name + "-" + strconv.Itoa(n+1)
+		}
+	}
+
+	return groups
+
+}
+
+// AzureRoutes renders a list of CIDR ranges as Azure route table routes towards the same next hop
+// The CIDR ranges are kept as given, since aggregating routes could change which route wins for addresses also matched by other routes of the table
+// @input prefix string: The prefix of the route names. Each route is named prefix-a.b.c.d_e after its CIDR range
+// @input cidrs []*ipv4cidr.IPv4CIDR: The destination CIDR ranges
+// @input nextHop AzureNextHop: The next hop of the routes
+// @returns []AzureRoute: The routes, ready to be marshaled to JSON
+// @returns error: If the next hop is invalid, an error is returned
+func AzureRoutes(prefix string, cidrs []*ipv4cidr.IPv4CIDR, nextHop AzureNextHop) ([]AzureRoute, error) {
+
+	if err := validateNextHop(nextHop); err != nil {
+		return nil, err
+	}
+
+	routes := make([]AzureRoute, 0, len(cidrs))
+	for _, cidr := range cidrs {
+
+		if cidr == nil {
+			continue
+		}
+
+		routes = append(routes, AzureRoute{
+			Name: prefix + "-" + strings.Replace(cidr.ToString(), "/", "_", 1),
+			Properties: AzureRouteProperties{
+				AddressPrefix:    cidr.ToString(),
+				NextHopType:      nextHop.Type,
+				NextHopIPAddress: nextHop.IPAddress,
+			},
+		})
+
+	}
+
+	return routes, nil
+
+}
+
+// validateNextHop checks that the next hop type is known, and that an IP address is given for virtual appliances only
+// @input nextHop AzureNextHop: The next hop to check
+// @returns error: If the next hop is invalid, an error is returned
+func validateNextHop(nextHop AzureNextHop) error {
+
+	switch nextHop.Type {
+	case consts.AzureNextHopVirtualAppliance:
+		if _, err := utils.ParseIPUint32(nextHop.IPAddress); err != nil {
+			return utils.NewError(consts.InvalidNextHopCode, consts.InvalidNextHopError)
+		}
+		return nil
+	case consts.AzureNextHopVirtualNetworkGateway, consts.AzureNextHopVnetLocal, consts.AzureNextHopInternet, consts.AzureNextHopNone:
+		if nextHop.IPAddress == "" {
+			return nil
+		}
+	}
+
+	return utils.NewError(consts.InvalidNextHopCode, consts.InvalidNextHopError)
+
+}
+
+// cidrStrings converts a list of CIDR ranges to their string representations
+// @input cidrs []*ipv4cidr.IPv4CIDR: The CIDR ranges
+// @returns []string: The CIDR ranges in format a.b.c.d/e
+func cidrStrings(cidrs []*ipv4cidr.IPv4CIDR) []string {
+
+	strs := make([]string, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		strs = append(strs, cidr.ToString())
+	}
+
+	return strs
+
+}
@@ -0,0 +1,120 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package export
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// parseAll parses a list of CIDR ranges, failing the test if any is invalid
+func parseAll(t *testing.T, inputs ...string) []*ipv4cidr.IPv4CIDR {
+
+	cidrs := make([]*ipv4cidr.IPv4CIDR, 0, len(inputs))
+	for _, input := range inputs {
+		cidr, err := ipv4cidr.NewIPv4CIDR(input, false)
+		assert.Nil(t, err, "%s is a valid CIDR block", input)
+		cidrs = append(cidrs, cidr)
+	}
+
+	return cidrs
+
+}
+
+// TestAzureIPGroups renders a list of CIDR ranges as an IP Group
+// Success Metric: The list is aggregated and rendered as an ARM resource
+func TestAzureIPGroups(t *testing.T) {
+
+	groups := AzureIPGroups("onprem", "westeurope", parseAll(t, "10.0.1.0/24", "10.0.0.0/24", "10.0.0.128/25", "192.168.1.7"))
+	if assert.Len(t, groups, 1) {
+
+		output, err := json.Marshal(groups[0])
+		assert.Nil(t, err)
+		assert.Equal(t, `{"type":"Microsoft.Network/ipGroups","apiVersion":"2023-04-01","name":"onprem","location":"westeurope","properties":{"ipAddresses":["10.0.0.0/23","192.168.1.7/32"]}}`, string(output))
+
+	}
+
+}
+
+// TestAzureIPGroupsSplit renders a list of CIDR ranges exceeding the limit of addresses per IP Group
+// Success Metric: The list is split into numbered IP Groups within the limit
+func TestAzureIPGroupsSplit(t *testing.T) {
+
+	// Every other /32 of 10.0.0.0/18, so that nothing can be aggregated
+	parent, _ := ipv4cidr.NewIPv4CIDR("10.0.0.0/18", false)
+	cidrs := make([]*ipv4cidr.IPv4CIDR, 0, 8192)
+	for n := uint64(0); n < 16384; n += 2 {
+		cidr, _ := parent.NthSubnet(32, n)
+		cidrs = append(cidrs, cidr)
+	}
+
+	groups := AzureIPGroups("blocked", "westeurope", cidrs)
+	if assert.Len(t, groups, 2) {
+
+		assert.Equal(t, "blocked-1", groups[0].Name)
+		assert.Len(t, groups[0].Properties.IPAddresses, consts.AzureIPGroupMaxAddresses)
+		assert.Equal(t, "blocked-2", groups[1].Name)
+		assert.Len(t, groups[1].Properties.IPAddresses, 8192-consts.AzureIPGroupMaxAddresses)
+
+	}
+
+}
+
+// TestAzureRoutes renders a list of CIDR ranges as routes
+// Success Metric: Routes are named after their CIDR range, and only virtual appliance routes have a next hop IP
+func TestAzureRoutes(t *testing.T) {
+
+	cidrs := parseAll(t, "10.0.0.0/16", "10.0.1.0/24")
+
+	routes, err := AzureRoutes("to-nva", cidrs, AzureNextHop{Type: consts.AzureNextHopVirtualAppliance, IPAddress: "10.255.0.4"})
+	assert.Nil(t, err)
+	if assert.Len(t, routes, 2) {
+
+		output, err := json.Marshal(routes[1])
+		assert.Nil(t, err)
+		assert.Equal(t, `{"name":"to-nva-10.0.1.0_24","properties":{"addressPrefix":"10.0.1.0/24","nextHopType":"VirtualAppliance","nextHopIpAddress":"10.255.0.4"}}`, string(output))
+
+	}
+
+	routes, err = AzureRoutes("blackhole", cidrs[:1], AzureNextHop{Type: consts.AzureNextHopNone})
+	assert.Nil(t, err)
+	if assert.Len(t, routes, 1) {
+
+		output, err := json.Marshal(routes[0])
+		assert.Nil(t, err)
+		assert.Equal(t, `{"name":"blackhole-10.0.0.0_16","properties":{"addressPrefix":"10.0.0.0/16","nextHopType":"None"}}`, string(output))
+
+	}
+
+}
+
+// TestAzureRoutesInvalidNextHop renders routes with invalid next hops
+// Success Metric: Throw an error saying the next hop is invalid
+func TestAzureRoutesInvalidNextHop(t *testing.T) {
+
+	cidrs := parseAll(t, "10.0.0.0/16")
+	nextHops := []AzureNextHop{
+		{Type: consts.AzureNextHopVirtualAppliance},
+		{Type: consts.AzureNextHopVirtualAppliance, IPAddress: "10.255.0"},
+		{Type: consts.AzureNextHopInternet, IPAddress: "10.255.0.4"},
+		{Type: "Firewall"},
+	}
+
+	for _, nextHop := range nextHops {
+
+		_, err := AzureRoutes("routes", cidrs, nextHop)
+		if assert.Error(t, err, "%v is an invalid next hop. An error should be thrown.", nextHop) {
+
+			assert.Equal(t, consts.InvalidNextHopError, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidNextHopError)
+
+		}
+
+	}
+
+}
@@ -0,0 +1,115 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package export
+
+import (
+	"strings"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/utils"
+)
+
+// EnvoyPrincipal models an Envoy RBAC principal matching the source IP address of a connection
+// @field SourceIP EnvoyCIDRRange: The CIDR range the source IP address must be in
+type EnvoyPrincipal struct {
+	SourceIP EnvoyCIDRRange `json:"source_ip"`
+}
+
+// EnvoyCIDRRange models an Envoy CIDR range (config.core.v3.CidrRange)
+// @field AddressPrefix string: The IP of the CIDR range in format a.b.c.d
+// @field PrefixLen uint8: The mask of the CIDR range
+type EnvoyCIDRRange struct {
+	AddressPrefix string `json:"address_prefix"`
+	PrefixLen     uint8  `json:"prefix_len"`
+}
+
+// EnvoyPrincipals renders a list of CIDR ranges as Envoy RBAC principals, to be used in the principals of an RBAC policy
+// The list is aggregated first, so the fewest principals are rendered. Nil CIDR ranges are skipped
+// @input cidrs []*ipv4cidr.IPv4CIDR: The CIDR ranges
+// @returns []EnvoyPrincipal: The principals, ready to be marshaled to JSON or YAML
+func EnvoyPrincipals(cidrs []*ipv4cidr.IPv4CIDR) []EnvoyPrincipal {
+
+	aggregated := ipv4cidr.Aggregate(cidrs)
+
+	principals := make([]EnvoyPrincipal, 0, len(aggregated))
+	for _, cidr := range aggregated {
+		principals = append(principals, EnvoyPrincipal{
+			SourceIP: EnvoyCIDRRange{
+				AddressPrefix: cidr.GetIP(),
+				PrefixLen:     cidr.GetMask(),
+			},
+		})
+	}
+
+	return principals
+
+}
+
+// NginxAllow renders a list of CIDR ranges as Nginx access directives allowing only those ranges
+// @input cidrs []*ipv4cidr.IPv4CIDR: The CIDR ranges to allow
+// @returns string: One allow directive per aggregated CIDR range, followed by deny all
+func NginxAllow(cidrs []*ipv4cidr.IPv4CIDR) string {
+
+	directives, _ := NginxACL(aclRules(consts.ACLAllow, cidrs), consts.ACLDeny)
+
+	return directives
+
+}
+
+// NginxDeny renders a list of CIDR ranges as Nginx access directives denying only those ranges
+// @input cidrs []*ipv4cidr.IPv4CIDR: The CIDR ranges to deny
+// @returns string: One deny directive per aggregated CIDR range, followed by allow all
+func NginxDeny(cidrs []*ipv4cidr.IPv4CIDR) string {
+
+	directives, _ := NginxACL(aclRules(consts.ACLDeny, cidrs), consts.ACLAllow)
+
+	return directives
+
+}
+
+// NginxACL renders an ordered list of ACL rules as Nginx access directives, which Nginx also evaluates in order with the first match winning
+// @input rules []ipv4cidr.ACLRule: The ordered list of rules
+// @input defaultAction string: The action for addresses not matched by any rule, either "allow" or "deny". If empty, no final directive is rendered
+// @returns string: One directive per line
+// @returns error: If a rule has an invalid action or no CIDR range, or the default has an invalid action, an error is returned
+func NginxACL(rules []ipv4cidr.ACLRule, defaultAction string) (string, error) {
+
+	if err := ipv4cidr.ValidateACL(rules); err != nil {
+		return "", err
+	}
+
+	var builder strings.Builder
+	for _, rule := range rules {
+		builder.WriteString(rule.Action + " " + rule.CIDR.Format(ipv4cidr.FormatOptions{OmitHostMask: true}) + ";\n")
+	}
+
+	switch defaultAction {
+	case "":
+	case consts.ACLAllow, consts.ACLDeny:
+		builder.WriteString(defaultAction + " all;\n")
+	default:
+		return "", utils.NewError(consts.InvalidACLCode, consts.InvalidACLActionError)
+	}
+
+	return builder.String(), nil
+
+}
+
+// aclRules converts a list of CIDR ranges into ACL rules with the same action, aggregating them first
+// @input action string: The action of the rules
+// @input cidrs []*ipv4cidr.IPv4CIDR: The CIDR ranges
+// @returns []ipv4cidr.ACLRule: The rules, in order of IP
+func aclRules(action string, cidrs []*ipv4cidr.IPv4CIDR) []ipv4cidr.ACLRule {
+
+	aggregated := ipv4cidr.Aggregate(cidrs)
+
+	rules := make([]ipv4cidr.ACLRule, 0, len(aggregated))
+	for _, cidr := range aggregated {
+		rules = append(rules, ipv4cidr.ACLRule{Action: action, CIDR: cidr})
+	}
+
+	return rules
+
+}
@@ -0,0 +1,90 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package export
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEnvoyPrincipals renders a list of CIDR ranges as Envoy RBAC principals
+// Success Metric: The list is aggregated and rendered with source_ip principals
+func TestEnvoyPrincipals(t *testing.T) {
+
+	principals := EnvoyPrincipals(parseAll(t, "10.0.1.0/24", "10.0.0.0/24", "192.168.1.7"))
+
+	output, err := json.Marshal(principals)
+	assert.Nil(t, err)
+	assert.Equal(t, `[{"source_ip":{"address_prefix":"10.0.0.0","prefix_len":23}},{"source_ip":{"address_prefix":"192.168.1.7","prefix_len":32}}]`, string(output))
+
+	principals = EnvoyPrincipals(append(parseAll(t, "10.0.0.0/24"), nil))
+	assert.Equal(t, []EnvoyPrincipal{{SourceIP: EnvoyCIDRRange{AddressPrefix: "10.0.0.0", PrefixLen: 24}}}, principals, "Nil CIDR ranges should be skipped")
+
+}
+
+// TestNginxAllowAndDeny renders allow and deny lists as Nginx directives
+// Success Metric: The aggregated CIDR ranges are rendered, followed by the opposite default
+func TestNginxAllowAndDeny(t *testing.T) {
+
+	cidrs := parseAll(t, "10.0.1.0/24", "10.0.0.0/24", "192.168.1.7")
+
+	assert.Equal(t, "allow 10.0.0.0/23;\nallow 192.168.1.7;\ndeny all;\n", NginxAllow(cidrs))
+	assert.Equal(t, "deny 10.0.0.0/23;\ndeny 192.168.1.7;\nallow all;\n", NginxDeny(cidrs))
+	assert.Equal(t, "deny all;\n", NginxAllow(nil), "Allowing nothing denies everything")
+
+}
+
+// TestNginxACL renders an ordered list of ACL rules as Nginx directives
+// Success Metric: The rules are rendered in order, and invalid actions are rejected
+func TestNginxACL(t *testing.T) {
+
+	cidrs := parseAll(t, "10.0.0.1", "10.0.0.0/8")
+	rules := []ipv4cidr.ACLRule{
+		{Action: consts.ACLDeny, CIDR: cidrs[0]},
+		{Action: consts.ACLAllow, CIDR: cidrs[1]},
+	}
+
+	directives, err := NginxACL(rules, "")
+	assert.Nil(t, err)
+	assert.Equal(t, "deny 10.0.0.1;\nallow 10.0.0.0/8;\n", directives)
+
+	_, err = NginxACL(rules, "reject")
+	if assert.Error(t, err, "reject is an invalid action. An error should be thrown.") {
+
+		assert.Equal(t, consts.InvalidACLActionError, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidACLActionError)
+
+	}
+
+	_, err = NginxACL([]ipv4cidr.ACLRule{{Action: "permit", CIDR: cidrs[0]}}, consts.ACLDeny)
+	if assert.Error(t, err, "permit is an invalid action. An error should be thrown.") {
+
+		assert.Equal(t, consts.InvalidACLActionError, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidACLActionError)
+
+	}
+
+}
+
+// TestNginxACLMissingCIDR renders an ordered list of ACL rules where a rule has no CIDR range
+// Success Metric: Throw an error saying the rule should have a CIDR range instead of panicking
+func TestNginxACLMissingCIDR(t *testing.T) {
+
+	rules := []ipv4cidr.ACLRule{
+		{Action: consts.ACLAllow, CIDR: parseAll(t, "10.0.0.0/8")[0]},
+		{Action: consts.ACLDeny},
+	}
+
+	_, err := NginxACL(rules, consts.ACLDeny)
+	if assert.Error(t, err, "A rule has no CIDR range. An error should be thrown.") {
+
+		assert.Equal(t, consts.MissingACLCIDRError, err.Error(), "Error thrown should be: \"%s\"", consts.MissingACLCIDRError)
+		assert.Equal(t, consts.InvalidACLCode, ipv4cidr.GetErrorCode(err))
+
+	}
+
+}
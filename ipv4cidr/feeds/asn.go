@@ -0,0 +1,93 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package feeds
+
+import (
+	"github.com/microsoft/go-cidr-manager/ipv4cidr"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/utils"
+)
+
+// ASNIndex indexes announced prefixes by origin AS and by prefix, to group prefixes per AS and find which AS announces an IP
+// @field byASN map[uint32][]*ipv4cidr.IPv4CIDR: The prefixes announced by each AS, as announced
+// @field byPrefix [consts.MaxBits + 1]map[uint32][]Announcement: The announcements of each prefix, indexed by mask then by first IP
+type ASNIndex struct {
+	byASN    map[uint32][]*ipv4cidr.IPv4CIDR
+	byPrefix [consts.MaxBits + 1]map[uint32][]Announcement
+}
+
+// NewASNIndex indexes a list of announcements, e.g. as read by ParseMRT
+// @input announcements []Announcement: The (prefix, origin AS) pairs
+// @returns *ASNIndex: The index of the announcements
+func NewASNIndex(announcements []Announcement) *ASNIndex {
+
+	index := &ASNIndex{byASN: make(map[uint32][]*ipv4cidr.IPv4CIDR)}
+
+	for _, announcement := range announcements {
+
+		// Announcements without a single origin (paths ending with an AS_SET) can still be looked up, but belong to no AS
+		if announcement.OriginASN != 0 {
+			index.byASN[announcement.OriginASN] = append(index.byASN[announcement.OriginASN], announcement.CIDR)
+		}
+
+		mask := announcement.CIDR.GetMask()
+		if index.byPrefix[mask] == nil {
+			index.byPrefix[mask] = make(map[uint32][]Announcement)
+		}
+
+		first, _ := announcement.CIDR.Range()
+		index.byPrefix[mask][first] = append(index.byPrefix[mask][first], announcement)
+
+	}
+
+	return index
+
+}
+
+// ByASN groups the announced prefixes by origin AS, aggregating the prefixes of each AS
+// @returns map[uint32][]*ipv4cidr.IPv4CIDR: The minimal list of CIDR ranges announced by each AS
+func (x *ASNIndex) ByASN() map[uint32][]*ipv4cidr.IPv4CIDR {
+
+	groups := make(map[uint32][]*ipv4cidr.IPv4CIDR, len(x.byASN))
+	for asn, cidrs := range x.byASN {
+		groups[asn] = ipv4cidr.Aggregate(cidrs)
+	}
+
+	return groups
+
+}
+
+// Prefixes returns the prefixes announced by an AS, aggregated
+// @input asn uint32: The AS number
+// @returns []*ipv4cidr.IPv4CIDR: The minimal list of CIDR ranges announced by the AS, empty if it announces nothing
+func (x *ASNIndex) Prefixes(asn uint32) []*ipv4cidr.IPv4CIDR {
+
+	return ipv4cidr.Aggregate(x.byASN[asn])
+
+}
+
+// Lookup finds which AS announces an IP address, i.e. the announcements of the most specific prefix containing it
+// More than one announcement is returned when the prefix is announced by several origin ASes (MOAS)
+// @input ip string: The IP address in format a.b.c.d
+// @returns []Announcement: The announcements of the longest matching prefix, empty if the IP address is not announced
+// @returns error: If the IP address is invalid, an error is returned
+func (x *ASNIndex) Lookup(ip string) ([]Announcement, error) {
+
+	value, err := utils.ParseIPUint32(ip)
+	if err != nil {
+		return nil, err
+	}
+
+	// Try the prefixes containing the IP from the most specific to the least specific
+	for mask := int(consts.MaxBits); mask >= 0; mask-- {
+
+		if announcements, ok := x.byPrefix[mask][utils.Standardize(value, utils.GetNetmask(uint8(mask)))]; ok {
+			return announcements, nil
+		}
+
+	}
+
+	return make([]Announcement, 0), nil
+
+}
@@ -0,0 +1,98 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package feeds
+
+import (
+	"testing"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestIndex builds an ASN index from (CIDR range, origin AS) pairs
+func newTestIndex(t *testing.T, pairs map[string][]uint32) *ASNIndex {
+
+	announcements := make([]Announcement, 0)
+	for prefix, origins := range pairs {
+
+		cidr, err := ipv4cidr.NewIPv4CIDR(prefix, false)
+		assert.Nil(t, err, "%s is a valid CIDR block", prefix)
+
+		for _, origin := range origins {
+			announcements = append(announcements, Announcement{CIDR: cidr, OriginASN: origin})
+		}
+
+	}
+
+	return NewASNIndex(announcements)
+
+}
+
+// TestASNIndexGrouping groups announced prefixes by origin AS
+// Success Metric: The prefixes of each AS are aggregated, and prefixes without a single origin belong to no AS
+func TestASNIndexGrouping(t *testing.T) {
+
+	index := newTestIndex(t, map[string][]uint32{
+		"1.0.0.0/24":      {13335},
+		"1.1.1.0/24":      {13335},
+		"1.1.0.0/24":      {13335},
+		"8.0.0.0/8":       {3356},
+		"203.0.113.0/24":  {0},
+		"198.51.100.0/24": {64500, 64501},
+	})
+
+	groups := index.ByASN()
+	assert.Equal(t, []string{"1.0.0.0/24", "1.1.0.0/23"}, toStrings(groups[13335]))
+	assert.Equal(t, []string{"8.0.0.0/8"}, toStrings(groups[3356]))
+	assert.Equal(t, []string{"198.51.100.0/24"}, toStrings(groups[64501]))
+	assert.NotContains(t, groups, uint32(0), "Announcements without a single origin should belong to no AS.")
+
+	assert.Equal(t, []string{"1.0.0.0/24", "1.1.0.0/23"}, toStrings(index.Prefixes(13335)))
+	assert.Empty(t, index.Prefixes(64999))
+
+}
+
+// TestASNIndexLookup finds which AS announces IPs
+// Success Metric: The announcements of the longest matching prefix are returned
+func TestASNIndexLookup(t *testing.T) {
+
+	index := newTestIndex(t, map[string][]uint32{
+		"8.0.0.0/8":       {3356},
+		"8.8.8.0/24":      {15169},
+		"198.51.100.0/24": {64500, 64501},
+		"0.0.0.0/0":       {64496},
+	})
+
+	for ip, expected := range map[string][]uint32{
+		"8.8.8.8":        {15169},
+		"8.8.4.4":        {3356},
+		"198.51.100.200": {64500, 64501},
+		"192.0.2.1":      {64496},
+	} {
+
+		announcements, err := index.Lookup(ip)
+		assert.Nil(t, err)
+
+		origins := make([]uint32, 0)
+		for _, announcement := range announcements {
+			origins = append(origins, announcement.OriginASN)
+		}
+		assert.ElementsMatch(t, expected, origins, "Origins of %s", ip)
+
+	}
+
+	announcements, err := newTestIndex(t, nil).Lookup("8.8.8.8")
+	assert.Nil(t, err)
+	assert.Empty(t, announcements, "Nothing is announced, there should be no match.")
+
+	_, err = index.Lookup("8.8.8")
+	if assert.Error(t, err, "8.8.8 is an invalid IP. An error should be thrown.") {
+
+		assert.Equal(t, consts.InvalidIPv4Error, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidIPv4Error)
+
+	}
+
+}
@@ -0,0 +1,77 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package feeds
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// azureServiceTags models the published Azure Service Tags JSON file (ServiceTags_Public_*.json)
+type azureServiceTags struct {
+	Values []struct {
+		Name       string `json:"name"`
+		Properties struct {
+			Region          string   `json:"region"`
+			AddressPrefixes []string `json:"addressPrefixes"`
+		} `json:"properties"`
+	} `json:"values"`
+}
+
+// awsIPRanges models the published AWS ip-ranges.json file
+type awsIPRanges struct {
+	Prefixes []struct {
+		IPPrefix string `json:"ip_prefix"`
+		Region   string `json:"region"`
+		Service  string `json:"service"`
+	} `json:"prefixes"`
+}
+
+// ParseAzureServiceTags parses the published Azure Service Tags JSON file
+// The service of each range is the name of its service tag (e.g. Storage.WestEurope), and global service tags have an empty region. IPv6 prefixes are skipped
+// @input r io.Reader: The content of the file
+// @returns *Feed: The IPv4 CIDR ranges of every service tag
+// @returns error: If the content is not valid JSON or contains an invalid CIDR range, an error is returned
+func ParseAzureServiceTags(r io.Reader) (*Feed, error) {
+
+	var tags azureServiceTags
+	if err := json.NewDecoder(r).Decode(&tags); err != nil {
+		return nil, err
+	}
+
+	feed := &Feed{Ranges: make([]Range, 0)}
+	for _, tag := range tags.Values {
+		for _, prefix := range tag.Properties.AddressPrefixes {
+			if err := feed.add(prefix, tag.Name, tag.Properties.Region); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return feed, nil
+
+}
+
+// ParseAWSIPRanges parses the published AWS ip-ranges.json file
+// The service of each range is the AWS service (e.g. EC2), and global ranges have the region GLOBAL, as published. IPv6 prefixes are skipped
+// @input r io.Reader: The content of the file
+// @returns *Feed: The IPv4 CIDR ranges of every service
+// @returns error: If the content is not valid JSON or contains an invalid CIDR range, an error is returned
+func ParseAWSIPRanges(r io.Reader) (*Feed, error) {
+
+	var ranges awsIPRanges
+	if err := json.NewDecoder(r).Decode(&ranges); err != nil {
+		return nil, err
+	}
+
+	feed := &Feed{Ranges: make([]Range, 0)}
+	for _, prefix := range ranges.Prefixes {
+		if err := feed.add(prefix.IPPrefix, prefix.Service, prefix.Region); err != nil {
+			return nil, err
+		}
+	}
+
+	return feed, nil
+
+}
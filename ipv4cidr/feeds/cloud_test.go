@@ -0,0 +1,107 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package feeds
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// azureServiceTagsSample is an excerpt of the published Azure Service Tags JSON file
+const azureServiceTagsSample = `{
+  "changeNumber": 312,
+  "cloud": "Public",
+  "values": [
+    {
+      "name": "Storage.WestEurope",
+      "id": "Storage.WestEurope",
+      "properties": {
+        "changeNumber": 40,
+        "region": "westeurope",
+        "regionId": 18,
+        "platform": "Azure",
+        "systemService": "AzureStorage",
+        "addressPrefixes": ["13.69.40.0/24", "13.69.41.0/24", "2603:1020:206::/48"]
+      }
+    },
+    {
+      "name": "AzureFrontDoor.Frontend",
+      "id": "AzureFrontDoor.Frontend",
+      "properties": {
+        "changeNumber": 12,
+        "region": "",
+        "platform": "Azure",
+        "systemService": "AzureFrontDoor",
+        "addressPrefixes": ["13.107.246.0/24"]
+      }
+    }
+  ]
+}`
+
+// awsIPRangesSample is an excerpt of the published AWS ip-ranges.json file
+const awsIPRangesSample = `{
+  "syncToken": "1700000000",
+  "createDate": "2023-11-14-22-13-20",
+  "prefixes": [
+    {"ip_prefix": "3.5.140.0/22", "region": "ap-northeast-2", "service": "AMAZON", "network_border_group": "ap-northeast-2"},
+    {"ip_prefix": "3.5.140.0/22", "region": "ap-northeast-2", "service": "S3", "network_border_group": "ap-northeast-2"},
+    {"ip_prefix": "52.94.76.0/22", "region": "GLOBAL", "service": "AMAZON", "network_border_group": "GLOBAL"}
+  ],
+  "ipv6_prefixes": [
+    {"ipv6_prefix": "2600:1f14::/35", "region": "eu-west-1", "service": "EC2", "network_border_group": "eu-west-1"}
+  ]
+}`
+
+// TestParseAzureServiceTags parses an excerpt of the Azure Service Tags file
+// Success Metric: IPv4 ranges are loaded with their service tag and region, and IPv6 ranges are skipped
+func TestParseAzureServiceTags(t *testing.T) {
+
+	feed, err := ParseAzureServiceTags(strings.NewReader(azureServiceTagsSample))
+	assert.Nil(t, err, "The sample is a valid Service Tags file, it should be parsed.")
+
+	if assert.Len(t, feed.Ranges, 3) {
+
+		assert.Equal(t, "13.69.40.0/24", feed.Ranges[0].CIDR.ToString())
+		assert.Equal(t, "Storage.WestEurope", feed.Ranges[0].Service)
+		assert.Equal(t, "westeurope", feed.Ranges[0].Region)
+		assert.Equal(t, "", feed.Ranges[2].Region, "Global service tags have no region")
+
+	}
+
+	assert.Equal(t, []string{"13.69.40.0/23"}, toStrings(feed.ByService()["Storage.WestEurope"]))
+
+}
+
+// TestParseAWSIPRanges parses an excerpt of the AWS ip-ranges.json file
+// Success Metric: IPv4 ranges are loaded with their service and region, and IPv6 ranges are skipped
+func TestParseAWSIPRanges(t *testing.T) {
+
+	feed, err := ParseAWSIPRanges(strings.NewReader(awsIPRangesSample))
+	assert.Nil(t, err, "The sample is a valid ip-ranges.json file, it should be parsed.")
+
+	assert.Len(t, feed.Ranges, 3)
+	assert.Equal(t, []string{"3.5.140.0/22", "52.94.76.0/22"}, toStrings(feed.ByService()["AMAZON"]))
+	assert.Equal(t, []string{"52.94.76.0/22"}, toStrings(feed.ByRegion()["GLOBAL"]))
+
+}
+
+// TestParseCloudFeedsInvalidInput parses invalid feed files
+// Success Metric: Errors are returned for invalid JSON and invalid CIDR ranges
+func TestParseCloudFeedsInvalidInput(t *testing.T) {
+
+	_, err := ParseAzureServiceTags(strings.NewReader(`{"values": [`))
+	assert.Error(t, err, "The content is not valid JSON. An error should be thrown.")
+
+	_, err = ParseAWSIPRanges(strings.NewReader(`{"prefixes": [{"ip_prefix": "3.5.140.1/22"}]}`))
+	if assert.Error(t, err, "3.5.140.1/22 is not standardized. An error should be thrown.") {
+
+		assert.Equal(t, consts.NonStandardizedIPError, err.Error(), "Error thrown should be: \"%s\"", consts.NonStandardizedIPError)
+
+	}
+
+}
@@ -0,0 +1,111 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package feeds
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/utils"
+)
+
+// Range is a CIDR range published in an IP range feed, along with what it is published for
+// @field CIDR *ipv4cidr.IPv4CIDR: The CIDR range
+// @field Service string: The service the CIDR range belongs to, e.g. a service tag or an AWS service
+// @field Region string: The region the CIDR range belongs to, or an empty string for global ranges
+type Range struct {
+	CIDR    *ipv4cidr.IPv4CIDR
+	Service string
+	Region  string
+}
+
+// Feed is a list of published CIDR ranges, keyed by service and region
+// @field Ranges []Range: The CIDR ranges, in the order of the feed
+type Feed struct {
+	Ranges []Range
+}
+
+// ByService groups the CIDR ranges of the feed by service, aggregating the CIDR ranges of each service
+// @returns map[string][]*ipv4cidr.IPv4CIDR: The minimal list of CIDR ranges of each service
+func (f *Feed) ByService() map[string][]*ipv4cidr.IPv4CIDR {
+
+	return f.groupBy(func(r Range) string { return r.Service })
+
+}
+
+// ByRegion groups the CIDR ranges of the feed by region, aggregating the CIDR ranges of each region
+// Global ranges are grouped under an empty region
+// @returns map[string][]*ipv4cidr.IPv4CIDR: The minimal list of CIDR ranges of each region
+func (f *Feed) ByRegion() map[string][]*ipv4cidr.IPv4CIDR {
+
+	return f.groupBy(func(r Range) string { return r.Region })
+
+}
+
+// Match finds the published CIDR ranges containing an IP address, e.g. to attribute traffic to a cloud service
+// @input ip string: The IP address in format a.b.c.d
+// @returns []Range: The matching ranges, most specific first
+// @returns error: If the IP address is invalid, an error is returned
+func (f *Feed) Match(ip string) ([]Range, error) {
+
+	value, err := utils.ParseIPUint32(ip)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]Range, 0)
+	for _, r := range f.Ranges {
+		if r.CIDR.ContainsRange(value, value) {
+			matches = append(matches, r)
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].CIDR.GetMask() > matches[j].CIDR.GetMask()
+	})
+
+	return matches, nil
+
+}
+
+// groupBy groups the CIDR ranges of the feed by a key, aggregating the CIDR ranges of each group
+// @input key func(Range) string: The function returning the key of a range
+// @returns map[string][]*ipv4cidr.IPv4CIDR: The minimal list of CIDR ranges of each group
+func (f *Feed) groupBy(key func(Range) string) map[string][]*ipv4cidr.IPv4CIDR {
+
+	groups := make(map[string][]*ipv4cidr.IPv4CIDR)
+	for _, r := range f.Ranges {
+		groups[key(r)] = append(groups[key(r)], r.CIDR)
+	}
+
+	for k, cidrs := range groups {
+		groups[k] = ipv4cidr.Aggregate(cidrs)
+	}
+
+	return groups
+
+}
+
+// add parses a published CIDR range and adds it to the feed. IPv6 CIDR ranges are skipped, since this package only handles IPv4
+// @input prefix string: The CIDR range in format a.b.c.d/e
+// @input service string: The service the CIDR range belongs to
+// @input region string: The region the CIDR range belongs to
+// @returns error: If the CIDR range is invalid, an error is returned
+func (f *Feed) add(prefix string, service string, region string) error {
+
+	if strings.Contains(prefix, ":") {
+		return nil
+	}
+
+	cidr, err := ipv4cidr.NewIPv4CIDR(prefix, false)
+	if err != nil {
+		return err
+	}
+
+	f.Ranges = append(f.Ranges, Range{CIDR: cidr, Service: service, Region: region})
+
+	return nil
+
+}
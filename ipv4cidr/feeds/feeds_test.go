@@ -0,0 +1,87 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package feeds
+
+import (
+	"testing"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// toStrings converts a list of CIDR ranges to strings for comparison in tests
+func toStrings(cidrs []*ipv4cidr.IPv4CIDR) []string {
+
+	strs := make([]string, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		strs = append(strs, cidr.ToString())
+	}
+
+	return strs
+
+}
+
+// newTestFeed builds a feed from (CIDR range, service, region) triples
+func newTestFeed(t *testing.T, entries ...[3]string) *Feed {
+
+	feed := &Feed{}
+	for _, entry := range entries {
+		assert.Nil(t, feed.add(entry[0], entry[1], entry[2]), "%s is a valid CIDR block", entry[0])
+	}
+
+	return feed
+
+}
+
+// TestFeedGrouping groups the ranges of a feed by service and region
+// Success Metric: Each group holds the aggregated CIDR ranges of its ranges
+func TestFeedGrouping(t *testing.T) {
+
+	feed := newTestFeed(t,
+		[3]string{"10.0.0.0/24", "web", "east"},
+		[3]string{"10.0.1.0/24", "web", "west"},
+		[3]string{"10.1.0.0/16", "db", "east"},
+		[3]string{"2001:db8::/32", "web", "east"},
+	)
+
+	services := feed.ByService()
+	assert.Equal(t, []string{"10.0.0.0/23"}, toStrings(services["web"]))
+	assert.Equal(t, []string{"10.1.0.0/16"}, toStrings(services["db"]))
+
+	regions := feed.ByRegion()
+	assert.Equal(t, []string{"10.0.0.0/24", "10.1.0.0/16"}, toStrings(regions["east"]))
+	assert.Equal(t, []string{"10.0.1.0/24"}, toStrings(regions["west"]))
+
+}
+
+// TestFeedMatch finds the ranges of a feed containing IPs
+// Success Metric: All matching ranges are returned, most specific first
+func TestFeedMatch(t *testing.T) {
+
+	feed := newTestFeed(t,
+		[3]string{"10.0.0.0/8", "cloud", ""},
+		[3]string{"10.0.0.0/24", "web", "east"},
+	)
+
+	matches, err := feed.Match("10.0.0.7")
+	assert.Nil(t, err)
+	if assert.Len(t, matches, 2) {
+		assert.Equal(t, "web", matches[0].Service)
+		assert.Equal(t, "cloud", matches[1].Service)
+	}
+
+	matches, err = feed.Match("192.168.0.1")
+	assert.Nil(t, err)
+	assert.Empty(t, matches)
+
+	_, err = feed.Match("10.0.0")
+	if assert.Error(t, err, "10.0.0 is an invalid IP. An error should be thrown.") {
+
+		assert.Equal(t, consts.InvalidIPv4Error, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidIPv4Error)
+
+	}
+
+}
@@ -0,0 +1,224 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package feeds
+
+import (
+	"encoding/binary"
+	"io"
+	"strconv"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/utils"
+)
+
+// This set of constants defines the parts of the MRT format (RFC 6396) and of BGP path attributes (RFC 4271) used by the MRT reader
+const (
+	mrtHeaderLength       = 12
+	mrtTableDumpV2        = 13
+	mrtRIBIPv4Unicast     = 2
+	bgpExtendedLength     = 0x10
+	bgpASPathAttribute    = 2
+	bgpASSequenceSegment  = 2
+	mrtRIBEntryHeaderSize = 8
+)
+
+// mrtMaxMessageLength is the largest message length accepted in a record header, so that a corrupt length cannot force a huge allocation.
+// It leaves ample room for RIB records seen from thousands of peers, which are far below a megabyte in practice
+const mrtMaxMessageLength = 16 << 20
+
+// Announcement is a prefix announced in BGP, along with the AS it originates from
+// @field CIDR *ipv4cidr.IPv4CIDR: The announced prefix
+// @field OriginASN uint32: The origin AS, i.e. the last AS of the AS path, or 0 if the path ends with an AS_SET
+type Announcement struct {
+	CIDR      *ipv4cidr.IPv4CIDR
+	OriginASN uint32
+}
+
+// ParseMRT reads the announced IPv4 prefixes from an MRT TABLE_DUMP_V2 RIB dump (RFC 6396), as published by RouteViews and RIPE RIS
+// Each distinct (prefix, origin AS) pair of the RIB_IPV4_UNICAST records is returned once, however many peers it was seen from
+// Other record types (peer index tables, IPv6 and multicast RIBs, BGP4MP updates) are skipped. Compressed dumps should be decompressed by the caller
+// @input r io.Reader: The content of the dump
+// @returns []Announcement: The announcements, in the order of the dump
+// @returns error: If a record is truncated, malformed, or longer than 16 MiB, an error is returned
+func ParseMRT(r io.Reader) ([]Announcement, error) {
+
+	announcements := make([]Announcement, 0)
+
+	header := make([]byte, mrtHeaderLength)
+	for {
+
+		// The common header is timestamp (4 bytes), type (2 bytes), subtype (2 bytes), message length (4 bytes)
+		if _, err := io.ReadFull(r, header); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, utils.NewError(consts.InvalidFeedRecordCode, consts.InvalidMRTRecordError)
+		}
+
+		length := binary.BigEndian.Uint32(header[8:12])
+		if length > mrtMaxMessageLength {
+			return nil, utils.NewError(consts.InvalidFeedRecordCode, consts.InvalidMRTRecordError)
+		}
+
+		message := make([]byte, length)
+		if _, err := io.ReadFull(r, message); err != nil {
+			return nil, utils.NewError(consts.InvalidFeedRecordCode, consts.InvalidMRTRecordError)
+		}
+
+		if binary.BigEndian.Uint16(header[4:6]) != mrtTableDumpV2 || binary.BigEndian.Uint16(header[6:8]) != mrtRIBIPv4Unicast {
+			continue
+		}
+
+		records, err := parseRIBIPv4Unicast(message)
+		if err != nil {
+			return nil, err
+		}
+		announcements = append(announcements, records...)
+
+	}
+
+	return announcements, nil
+
+}
+
+// parseRIBIPv4Unicast parses the message of a RIB_IPV4_UNICAST record
+// The message is sequence number (4 bytes), prefix length (1 byte), prefix (as many bytes as the prefix length needs), entry count (2 bytes), then the RIB entries
+// @input message []byte: The message of the record
+// @returns []Announcement: One announcement per distinct origin AS of the entries
+// @returns error: If the message is truncated or malformed, an error is returned
+func parseRIBIPv4Unicast(message []byte) ([]Announcement, error) {
+
+	invalid := utils.NewError(consts.InvalidFeedRecordCode, consts.InvalidMRTRecordError)
+
+	if len(message) < 5 || message[4] > consts.MaxBits {
+		return nil, invalid
+	}
+
+	mask := message[4]
+	prefixBytes := int(mask+7) / 8
+	position := 5 + prefixBytes
+	if len(message) < position+2 {
+		return nil, invalid
+	}
+
+	var ip [4]byte
+	copy(ip[:], message[5:position])
+	cidr, err := ipv4cidr.NewIPv4CIDR(utils.ConvertIPToString(binary.BigEndian.Uint32(ip[:]))+"/"+strconv.Itoa(int(mask)), false)
+	if err != nil {
+		return nil, invalid
+	}
+
+	entries := int(binary.BigEndian.Uint16(message[position : position+2]))
+	position += 2
+
+	announcements := make([]Announcement, 0, 1)
+	seen := make(map[uint32]bool)
+	for n := 0; n < entries; n++ {
+
+		// Each entry is peer index (2 bytes), originated time (4 bytes), attribute length (2 bytes), then the BGP path attributes
+		if len(message) < position+mrtRIBEntryHeaderSize {
+			return nil, invalid
+		}
+
+		attributesLength := int(binary.BigEndian.Uint16(message[position+6 : position+8]))
+		position += mrtRIBEntryHeaderSize
+		if len(message) < position+attributesLength {
+			return nil, invalid
+		}
+
+		origin, err := originASN(message[position : position+attributesLength])
+		if err != nil {
+			return nil, err
+		}
+		position += attributesLength
+
+		if !seen[origin] {
+			seen[origin] = true
+			announcements = append(announcements, Announcement{CIDR: cidr, OriginASN: origin})
+		}
+
+	}
+
+	return announcements, nil
+
+}
+
+// originASN finds the origin AS in the BGP path attributes of a RIB entry
+// In TABLE_DUMP_V2, AS numbers in the AS_PATH attribute are always encoded in 4 bytes
+// @input attributes []byte: The BGP path attributes
+// @returns uint32: The last AS of the AS path, or 0 if there is no AS path or it ends with an AS_SET
+// @returns error: If the attributes are truncated, an error is returned
+func originASN(attributes []byte) (uint32, error) {
+
+	invalid := utils.NewError(consts.InvalidFeedRecordCode, consts.InvalidMRTRecordError)
+
+	for position := 0; position < len(attributes); {
+
+		// Each attribute is flags (1 byte), type (1 byte), length (1 byte, or 2 bytes with the extended length flag), then the value
+		if len(attributes) < position+3 {
+			return 0, invalid
+		}
+
+		flags := attributes[position]
+		attributeType := attributes[position+1]
+		length := int(attributes[position+2])
+		position += 3
+
+		if flags&bgpExtendedLength != 0 {
+			if len(attributes) < position+1 {
+				return 0, invalid
+			}
+			length = length<<8 | int(attributes[position])
+			position++
+		}
+
+		if len(attributes) < position+length {
+			return 0, invalid
+		}
+
+		if attributeType == bgpASPathAttribute {
+			return lastASN(attributes[position : position+length])
+		}
+		position += length
+
+	}
+
+	return 0, nil
+
+}
+
+// lastASN finds the last AS of an AS_PATH attribute
+// The attribute is a list of segments, each made of segment type (1 byte), AS count (1 byte), then the 4-byte AS numbers
+// @input path []byte: The value of the AS_PATH attribute
+// @returns uint32: The last AS of the path, or 0 if the path is empty or ends with an AS_SET
+// @returns error: If the attribute is truncated, an error is returned
+func lastASN(path []byte) (uint32, error) {
+
+	origin := uint32(0)
+	for position := 0; position < len(path); {
+
+		if len(path) < position+2 {
+			return 0, utils.NewError(consts.InvalidFeedRecordCode, consts.InvalidMRTRecordError)
+		}
+
+		segmentType := path[position]
+		count := int(path[position+1])
+		position += 2
+
+		if len(path) < position+4*count {
+			return 0, utils.NewError(consts.InvalidFeedRecordCode, consts.InvalidMRTRecordError)
+		}
+
+		// An AS_SET at the end of the path (from aggregation) has no single origin
+		origin = 0
+		if segmentType == bgpASSequenceSegment && count > 0 {
+			origin = binary.BigEndian.Uint32(path[position+4*(count-1) : position+4*count])
+		}
+		position += 4 * count
+
+	}
+
+	return origin, nil
+
+}
@@ -0,0 +1,128 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package feeds
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// mrtRecord encodes an MRT record with the common header
+func mrtRecord(recordType uint16, subtype uint16, message []byte) []byte {
+
+	record := make([]byte, mrtHeaderLength, mrtHeaderLength+len(message))
+	binary.BigEndian.PutUint16(record[4:6], recordType)
+	binary.BigEndian.PutUint16(record[6:8], subtype)
+	binary.BigEndian.PutUint32(record[8:12], uint32(len(message)))
+
+	return append(record, message...)
+
+}
+
+// asPath encodes the BGP path attributes of a RIB entry, with an ORIGIN attribute and an AS_PATH attribute made of the given segments
+func asPath(segments ...[]uint32) []byte {
+
+	path := make([]byte, 0)
+	for _, segment := range segments {
+
+		// The first AS of a segment is its type, the rest are the AS numbers
+		path = append(path, byte(segment[0]), byte(len(segment)-1))
+		for _, asn := range segment[1:] {
+			path = append(path, byte(asn>>24), byte(asn>>16), byte(asn>>8), byte(asn))
+		}
+
+	}
+
+	attributes := []byte{0x40, 1, 1, 0}
+	attributes = append(attributes, 0x50, bgpASPathAttribute, byte(len(path)>>8), byte(len(path)))
+
+	return append(attributes, path...)
+
+}
+
+// ribIPv4Unicast encodes a RIB_IPV4_UNICAST message with one RIB entry per attribute list
+func ribIPv4Unicast(prefix []byte, mask uint8, entries ...[]byte) []byte {
+
+	message := []byte{0, 0, 0, 1, mask}
+	message = append(message, prefix...)
+	message = append(message, 0, byte(len(entries)))
+
+	for _, attributes := range entries {
+		message = append(message, 0, 0, 0, 0, 0, 0, byte(len(attributes)>>8), byte(len(attributes)))
+		message = append(message, attributes...)
+	}
+
+	return message
+
+}
+
+// TestParseMRT reads announcements from a small TABLE_DUMP_V2 dump
+// Success Metric: One announcement is returned per distinct prefix and origin AS, and other records are skipped
+func TestParseMRT(t *testing.T) {
+
+	dump := bytes.Buffer{}
+	dump.Write(mrtRecord(mrtTableDumpV2, 1, []byte{1, 2, 3, 4, 0, 0, 0, 0}))
+	dump.Write(mrtRecord(mrtTableDumpV2, mrtRIBIPv4Unicast, ribIPv4Unicast([]byte{1, 0, 0}, 24,
+		asPath([]uint32{bgpASSequenceSegment, 3356, 13335}),
+		asPath([]uint32{bgpASSequenceSegment, 174, 13335}),
+	)))
+	dump.Write(mrtRecord(mrtTableDumpV2, 4, []byte{0, 0, 0, 2, 32, 0x20, 0x01, 0x0d, 0xb8, 0, 0}))
+	dump.Write(mrtRecord(mrtTableDumpV2, mrtRIBIPv4Unicast, ribIPv4Unicast([]byte{8}, 8,
+		asPath([]uint32{bgpASSequenceSegment, 3356}),
+		asPath([]uint32{bgpASSequenceSegment, 174, 3549}),
+	)))
+	dump.Write(mrtRecord(mrtTableDumpV2, mrtRIBIPv4Unicast, ribIPv4Unicast([]byte{203, 0, 113}, 24,
+		asPath([]uint32{bgpASSequenceSegment, 174}, []uint32{1, 64500, 64501}),
+	)))
+
+	announcements, err := ParseMRT(&dump)
+	assert.Nil(t, err, "The dump is valid, it should be read.")
+
+	if assert.Len(t, announcements, 4) {
+
+		assert.Equal(t, "1.0.0.0/24", announcements[0].CIDR.ToString())
+		assert.Equal(t, uint32(13335), announcements[0].OriginASN, "Both peers see the same origin, it should be returned once.")
+		assert.Equal(t, "8.0.0.0/8", announcements[1].CIDR.ToString())
+		assert.Equal(t, uint32(3356), announcements[1].OriginASN)
+		assert.Equal(t, uint32(3549), announcements[2].OriginASN, "Peers see different origins, both should be returned.")
+		assert.Equal(t, uint32(0), announcements[3].OriginASN, "The path ends with an AS_SET, there is no single origin.")
+
+	}
+
+}
+
+// TestParseMRTInvalidInput reads truncated and malformed dumps
+// Success Metric: Errors are returned for truncated headers, messages and attributes, for invalid prefixes, and for message lengths too large to allocate
+func TestParseMRTInvalidInput(t *testing.T) {
+
+	valid := mrtRecord(mrtTableDumpV2, mrtRIBIPv4Unicast, ribIPv4Unicast([]byte{1, 0, 0}, 24, asPath([]uint32{bgpASSequenceSegment, 13335})))
+
+	// A header claiming a 4 GiB message, followed by a few bytes only
+	oversized := append([]byte{}, valid...)
+	binary.BigEndian.PutUint32(oversized[8:12], consts.MaxUInt32)
+
+	for _, dump := range [][]byte{
+		valid[:6],
+		valid[:len(valid)-1],
+		oversized,
+		mrtRecord(mrtTableDumpV2, mrtRIBIPv4Unicast, ribIPv4Unicast([]byte{1, 0, 0}, 33)),
+		mrtRecord(mrtTableDumpV2, mrtRIBIPv4Unicast, ribIPv4Unicast([]byte{1, 1}, 15)),
+		mrtRecord(mrtTableDumpV2, mrtRIBIPv4Unicast, ribIPv4Unicast([]byte{1, 0, 0}, 24, []byte{0x40, bgpASPathAttribute, 6, 2, 1, 0, 0})),
+	} {
+
+		_, err := ParseMRT(bytes.NewReader(dump))
+		if assert.Error(t, err, "The dump is truncated or malformed. An error should be thrown.") {
+
+			assert.Equal(t, consts.InvalidMRTRecordError, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidMRTRecordError)
+
+		}
+
+	}
+
+}
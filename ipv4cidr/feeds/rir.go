@@ -0,0 +1,149 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package feeds
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/utils"
+)
+
+// Delegation is a block of IPv4 addresses recorded in an RIR delegated-extended statistics file
+// @field CIDR *ipv4cidr.IPv4CIDR: The CIDR range. Records that are not a power of 2 in size are split into several delegations
+// @field Registry string: The registry the block is delegated from, e.g. ripencc
+// @field Country string: The ISO 3166 country code of the holder, or ZZ / an empty string for undelegated blocks
+// @field Status string: The status of the block, one of consts.RIRAllocated, consts.RIRAssigned, consts.RIRAvailable or consts.RIRReserved
+type Delegation struct {
+	CIDR     *ipv4cidr.IPv4CIDR
+	Registry string
+	Country  string
+	Status   string
+}
+
+// DelegationStats is the list of IPv4 blocks of one or more RIR delegated-extended statistics files
+// @field Delegations []Delegation: The IPv4 blocks, in the order of the files
+type DelegationStats struct {
+	Delegations []Delegation
+}
+
+// ParseDelegationStats parses an RIR delegated-extended statistics file, as published by ARIN, RIPE NCC, APNIC, LACNIC and AFRINIC
+// The version line, summary lines, comments, and the ASN and IPv6 records are skipped
+// @input r io.Reader: The content of the file
+// @returns *DelegationStats: The IPv4 blocks of the file
+// @returns error: If an IPv4 record is malformed, an error is returned
+func ParseDelegationStats(r io.Reader) (*DelegationStats, error) {
+
+	stats := &DelegationStats{Delegations: make([]Delegation, 0)}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// Summary lines are registry|*|type|*|count|summary
+		fields := strings.Split(line, "|")
+		if len(fields) == 6 && fields[5] == "summary" {
+			continue
+		}
+
+		// Records are registry|cc|type|start|value|date|status, optionally followed by an opaque ID and extensions
+		// The version line has 7 fields too, but its third field is a serial number rather than a type
+		if len(fields) < 7 {
+			return nil, utils.NewError(consts.InvalidFeedRecordCode, consts.InvalidDelegationRecordError)
+		}
+
+		if fields[2] != "ipv4" {
+			continue
+		}
+
+		if err := stats.add(fields[0], fields[1], fields[3], fields[4], fields[6]); err != nil {
+			return nil, err
+		}
+
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+
+}
+
+// ByCountry groups the delegated (allocated or assigned) blocks by country, aggregating the CIDR ranges of each country
+// @returns map[string][]*ipv4cidr.IPv4CIDR: The minimal list of CIDR ranges of each country
+func (s *DelegationStats) ByCountry() map[string][]*ipv4cidr.IPv4CIDR {
+
+	return s.groupBy(func(d Delegation) string { return d.Country })
+
+}
+
+// ByRegistry groups the delegated (allocated or assigned) blocks by registry, aggregating the CIDR ranges of each registry
+// @returns map[string][]*ipv4cidr.IPv4CIDR: The minimal list of CIDR ranges of each registry
+func (s *DelegationStats) ByRegistry() map[string][]*ipv4cidr.IPv4CIDR {
+
+	return s.groupBy(func(d Delegation) string { return d.Registry })
+
+}
+
+// groupBy groups the delegated blocks by a key, aggregating the CIDR ranges of each group
+// Available and reserved blocks are not held by anyone, so they are left out
+// @input key func(Delegation) string: The function returning the key of a delegation
+// @returns map[string][]*ipv4cidr.IPv4CIDR: The minimal list of CIDR ranges of each group
+func (s *DelegationStats) groupBy(key func(Delegation) string) map[string][]*ipv4cidr.IPv4CIDR {
+
+	groups := make(map[string][]*ipv4cidr.IPv4CIDR)
+	for _, d := range s.Delegations {
+		if d.Status == consts.RIRAllocated || d.Status == consts.RIRAssigned {
+			groups[key(d)] = append(groups[key(d)], d.CIDR)
+		}
+	}
+
+	for k, cidrs := range groups {
+		groups[k] = ipv4cidr.Aggregate(cidrs)
+	}
+
+	return groups
+
+}
+
+// add parses an IPv4 record and adds its CIDR ranges to the list
+// @input registry string: The registry field of the record
+// @input country string: The country code field of the record
+// @input start string: The first IP address of the block
+// @input value string: The number of IP addresses in the block, which is not necessarily a power of 2
+// @input status string: The status field of the record
+// @returns error: If the first IP address or the number of IP addresses is invalid, an error is returned
+func (s *DelegationStats) add(registry string, country string, start string, value string, status string) error {
+
+	first, err := utils.ParseIPUint32(start)
+	if err != nil {
+		return err
+	}
+
+	count, err := strconv.ParseUint(value, 10, 64)
+	if err != nil || count == 0 || uint64(first)+count-1 > uint64(consts.MaxUInt32) {
+		return utils.NewError(consts.InvalidFeedRecordCode, consts.InvalidDelegationRecordError)
+	}
+
+	cidrs, err := ipv4cidr.SummarizeRange(start, utils.ConvertIPToString(first+uint32(count-1)))
+	if err != nil {
+		return err
+	}
+
+	for _, cidr := range cidrs {
+		s.Delegations = append(s.Delegations, Delegation{CIDR: cidr, Registry: registry, Country: country, Status: status})
+	}
+
+	return nil
+
+}
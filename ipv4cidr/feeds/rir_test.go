@@ -0,0 +1,80 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package feeds
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// delegationStatsSample is an excerpt of a published RIR delegated-extended statistics file
+const delegationStatsSample = `# delegated-ripencc-extended
+2|ripencc|20231114|4|19830705|20231113|+0100
+ripencc|*|asn|*|1|summary
+ripencc|*|ipv4|*|3|summary
+ripencc|*|ipv6|*|1|summary
+ripencc|NL|asn|1101|1|19930901|allocated|6c4e5f6a
+ripencc|NL|ipv4|2.56.0.0|1024|20190524|allocated|a1b2c3d4
+ripencc|NL|ipv4|2.56.4.0|1024|20190524|allocated|a1b2c3d4
+ripencc|DE|ipv4|5.1.0.0|768|20111221|assigned|e5f6a7b8
+ripencc|ZZ|ipv4|5.2.0.0|256||available|
+ripencc|FR|ipv6|2001:660::|32|19990816|allocated|f9e8d7c6
+`
+
+// TestParseDelegationStats parses an excerpt of an RIR delegated-extended statistics file
+// Success Metric: IPv4 records are split into CIDR ranges, and other lines are skipped
+func TestParseDelegationStats(t *testing.T) {
+
+	stats, err := ParseDelegationStats(strings.NewReader(delegationStatsSample))
+	assert.Nil(t, err, "The sample is a valid delegated-extended file, it should be parsed.")
+
+	if assert.Len(t, stats.Delegations, 5) {
+
+		assert.Equal(t, "5.1.0.0/23", stats.Delegations[2].CIDR.ToString(), "5.1.0.0 + 768 addresses is not a power of 2, it should be split.")
+		assert.Equal(t, "5.1.2.0/24", stats.Delegations[3].CIDR.ToString())
+		assert.Equal(t, "DE", stats.Delegations[3].Country)
+		assert.Equal(t, consts.RIRAvailable, stats.Delegations[4].Status)
+
+	}
+
+	countries := stats.ByCountry()
+	assert.Equal(t, []string{"2.56.0.0/21"}, toStrings(countries["NL"]))
+	assert.Equal(t, []string{"5.1.0.0/23", "5.1.2.0/24"}, toStrings(countries["DE"]))
+	assert.NotContains(t, countries, "ZZ", "Available blocks are not delegated, they should be left out.")
+
+	assert.Equal(t, []string{"2.56.0.0/21", "5.1.0.0/23", "5.1.2.0/24"}, toStrings(stats.ByRegistry()["ripencc"]))
+
+}
+
+// TestParseDelegationStatsInvalidInput parses malformed delegated-extended files
+// Success Metric: Errors are returned for truncated records, invalid IPs and invalid block sizes
+func TestParseDelegationStatsInvalidInput(t *testing.T) {
+
+	for _, content := range []string{
+		"ripencc|NL|ipv4|2.56.0.0|1024",
+		"ripencc|NL|ipv4|2.56.0.0|0|20190524|allocated",
+		"ripencc|NL|ipv4|255.255.255.0|1024|20190524|allocated",
+	} {
+
+		_, err := ParseDelegationStats(strings.NewReader(content))
+		if assert.Error(t, err, "%s is a malformed record. An error should be thrown.", content) {
+
+			assert.Equal(t, consts.InvalidDelegationRecordError, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidDelegationRecordError)
+
+		}
+
+	}
+
+	_, err := ParseDelegationStats(strings.NewReader("ripencc|NL|ipv4|2.56.0|1024|20190524|allocated"))
+	if assert.Error(t, err, "2.56.0 is an invalid IP. An error should be thrown.") {
+
+		assert.Equal(t, consts.InvalidIPv4Error, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidIPv4Error)
+
+	}
+
+}
@@ -0,0 +1,71 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"strconv"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/utils"
+)
+
+// FormatOptions configures the string representation produced by Format, since different downstream systems require different canonical strings
+// The zero value produces the same string as ToString, e.g. 10.0.0.0/24
+// @field OmitHostMask bool: Omit the mask for single IP addresses, e.g. 10.0.0.1 instead of 10.0.0.1/32
+// @field ZeroPad bool: Pad every octet to 3 digits, e.g. 010.000.000.000/24
+// @field Netmask bool: Write the mask as a netmask, e.g. 10.0.0.0/255.255.255.0
+type FormatOptions struct {
+	OmitHostMask bool
+	ZeroPad      bool
+	Netmask      bool
+}
+
+// Format converts the IPv4CIDR object to its string representation, with configurable formatting
+// @input opts FormatOptions: The formatting options
+// @returns string: The formatted CIDR range
+func (i *IPv4CIDR) Format(opts FormatOptions) string {
+
+	buffer := make([]byte, 0, 31)
+	buffer = appendFormattedIP(buffer, i.ip, opts.ZeroPad)
+
+	if opts.OmitHostMask && i.IsSingleIP() {
+		return string(buffer)
+	}
+
+	buffer = append(buffer, '/')
+	if opts.Netmask {
+		buffer = appendFormattedIP(buffer, i.netmask, opts.ZeroPad)
+	} else {
+		buffer = strconv.AppendUint(buffer, uint64(i.mask), 10)
+	}
+
+	return string(buffer)
+
+}
+
+// appendFormattedIP appends the string representation of an integer IP address to a byte slice, optionally padding every octet to 3 digits
+// @input dst []byte: The byte slice to append to
+// @input ip uint32: IP address in integer representation
+// @input zeroPad bool: Pad every octet to 3 digits
+// @returns []byte: The extended byte slice
+func appendFormattedIP(dst []byte, ip uint32, zeroPad bool) []byte {
+
+	if !zeroPad {
+		return utils.AppendIPString(dst, ip)
+	}
+
+	for n := 3; n >= 0; n-- {
+
+		section := (ip >> (uint8(n) * consts.GroupSize)) & consts.EightBits
+		dst = append(dst, byte('0'+section/100), byte('0'+(section/10)%10), byte('0'+section%10))
+
+		if n > 0 {
+			dst = append(dst, '.')
+		}
+
+	}
+
+	return dst
+
+}
@@ -0,0 +1,28 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFormat formats CIDR ranges with every combination of options
+// Success Metric: The string representation matches the requested format
+func TestFormat(t *testing.T) {
+
+	network, _ := NewIPv4CIDR("10.0.0.0/24", false)
+	host, _ := NewIPv4CIDR("192.168.1.7", false)
+
+	assert.Equal(t, network.ToString(), network.Format(FormatOptions{}), "The default format should match ToString")
+	assert.Equal(t, "192.168.1.7/32", host.Format(FormatOptions{}), "The mask should be included by default")
+	assert.Equal(t, "192.168.1.7", host.Format(FormatOptions{OmitHostMask: true}))
+	assert.Equal(t, "10.0.0.0/24", network.Format(FormatOptions{OmitHostMask: true}), "The mask should only be omitted for single IPs")
+	assert.Equal(t, "010.000.000.000/24", network.Format(FormatOptions{ZeroPad: true}))
+	assert.Equal(t, "10.0.0.0/255.255.255.0", network.Format(FormatOptions{Netmask: true}))
+	assert.Equal(t, "010.000.000.000/255.255.255.000", network.Format(FormatOptions{ZeroPad: true, Netmask: true}))
+	assert.Equal(t, "192.168.001.007", host.Format(FormatOptions{OmitHostMask: true, ZeroPad: true, Netmask: true}))
+
+}
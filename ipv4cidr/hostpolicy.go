@@ -0,0 +1,75 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+)
+
+// HostPolicy defines which addresses of a CIDR range are usable by hosts, so that every usable-host calculation applies the same rules
+// A /32 always has exactly one usable address, the single host.
+// @field ReserveNetworkAndBroadcast bool: If set, the first (network) and last (broadcast) addresses of ranges of /30 and larger are not usable
+// @field PointToPoint bool: If set, both addresses of a /31 are usable, as on point-to-point links (RFC 3021). Else, a /31 has no usable address when network and broadcast are reserved
+type HostPolicy struct {
+	ReserveNetworkAndBroadcast bool
+	PointToPoint               bool
+}
+
+// DefaultHostPolicy reserves the network and broadcast addresses and follows RFC 3021 for /31
+var DefaultHostPolicy = HostPolicy{
+	ReserveNetworkAndBroadcast: true,
+	PointToPoint:               true,
+}
+
+// UsableRange returns the first and last usable host addresses of a CIDR range
+// @input cidr *IPv4CIDR: The CIDR range
+// @returns uint32: The first usable IP address in integer representation
+// @returns uint32: The last usable IP address in integer representation
+// @returns bool: False if the CIDR range has no usable address under this policy
+func (p HostPolicy) UsableRange(cidr *IPv4CIDR) (uint32, uint32, bool) {
+
+	first := cidr.ip
+	last := cidr.lastIP()
+
+	switch {
+	case cidr.IsSingleIP():
+		return first, last, true
+	case cidr.mask == consts.MaxBits-1 && p.ReserveNetworkAndBroadcast && !p.PointToPoint:
+		return 0, 0, false
+	case cidr.mask < consts.MaxBits-1 && p.ReserveNetworkAndBroadcast:
+		return first + 1, last - 1, true
+	}
+
+	return first, last, true
+
+}
+
+// UsableHostCount returns the number of usable host addresses of a CIDR range
+// @input cidr *IPv4CIDR: The CIDR range
+// @returns uint64: Number of usable host addresses
+func (p HostPolicy) UsableHostCount(cidr *IPv4CIDR) uint64 {
+
+	first, last, ok := p.UsableRange(cidr)
+	if !ok {
+		return 0
+	}
+
+	return uint64(last) - uint64(first) + 1
+
+}
+
+// UsableAddresses counts the number of usable host addresses covered by a list of CIDR ranges
+// Overlapping ranges are only counted once, and each outermost block is treated as a subnet
+// @input cidrs []*IPv4CIDR: The list of CIDR ranges
+// @returns uint64: Number of usable host addresses in the union of the CIDR ranges
+func (p HostPolicy) UsableAddresses(cidrs []*IPv4CIDR) uint64 {
+
+	total := uint64(0)
+	for _, cidr := range normalize(cidrs) {
+		total += p.UsableHostCount(cidr)
+	}
+
+	return total
+
+}
@@ -0,0 +1,66 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"testing"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDefaultHostPolicy calculates the usable hosts of CIDR ranges of various sizes with the default policy
+// Success Metric: Network and broadcast are excluded, except for /31 (RFC 3021) and /32
+func TestDefaultHostPolicy(t *testing.T) {
+
+	testInputs := map[string]uint64{
+		"10.0.0.0/24": 254,
+		"10.0.0.0/30": 2,
+		"10.0.0.0/31": 2,
+		"10.0.0.1":    1,
+		"0.0.0.0/0":   (uint64(1) << 32) - 2,
+	}
+
+	for input, expected := range testInputs {
+
+		CIDR, _ := NewIPv4CIDR(input, false)
+		assert.Equal(t, expected, DefaultHostPolicy.UsableHostCount(CIDR), "Usable hosts of %s should be %d", input, expected)
+
+	}
+
+	CIDR, _ := NewIPv4CIDR("10.0.0.0/24", false)
+	first, last, ok := DefaultHostPolicy.UsableRange(CIDR)
+	assert.True(t, ok, "10.0.0.0/24 has usable hosts")
+	assert.Equal(t, "10.0.0.1", utils.ConvertIPToString(first))
+	assert.Equal(t, "10.0.0.254", utils.ConvertIPToString(last))
+
+}
+
+// TestHostPolicyWithoutPointToPoint calculates the usable hosts of a /31 when RFC 3021 is not followed
+// Success Metric: A /31 has no usable hosts
+func TestHostPolicyWithoutPointToPoint(t *testing.T) {
+
+	policy := HostPolicy{ReserveNetworkAndBroadcast: true}
+
+	CIDR, _ := NewIPv4CIDR("10.0.0.0/31", false)
+	_, _, ok := policy.UsableRange(CIDR)
+	assert.False(t, ok, "10.0.0.0/31 has no usable hosts without RFC 3021")
+	assert.Equal(t, uint64(0), policy.UsableHostCount(CIDR))
+
+	CIDR, _ = NewIPv4CIDR("10.0.0.5", false)
+	assert.Equal(t, uint64(1), policy.UsableHostCount(CIDR), "A /32 always has a single usable host")
+
+}
+
+// TestHostPolicyAllAddresses calculates the usable hosts when network and broadcast are not reserved
+// Success Metric: Every address of the range is usable
+func TestHostPolicyAllAddresses(t *testing.T) {
+
+	policy := HostPolicy{}
+	cidrs := parseAll(t, "10.0.0.0/24", "10.0.1.0/31")
+
+	assert.Equal(t, uint64(258), policy.UsableAddresses(cidrs))
+
+}
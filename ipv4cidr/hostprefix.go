@@ -0,0 +1,77 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"strconv"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/utils"
+)
+
+// HostPrefix models an IP address together with the CIDR range it belongs to, e.g. an interface address such as 10.0.0.5/24
+// Unlike IPv4CIDR, the host part of the IP address is kept instead of being lost to standardization
+// @field host uint32: The IP address in integer representation
+// @field network *IPv4CIDR: The standardized CIDR range containing the IP address
+type HostPrefix struct {
+	host    uint32
+	network *IPv4CIDR
+}
+
+// NewHostPrefix parses a string representing an IP address and the mask of its CIDR range
+// @input IP string: The IP address and mask in format a.b.c.d/e, or a.b.c.d (in which case the mask is 32)
+// @returns *HostPrefix: Pointer to the new HostPrefix object
+// @returns error: If the input is invalid, an error is returned
+func NewHostPrefix(IP string) (*HostPrefix, error) {
+
+	host, mask, ok := utils.ParseCIDRUint32(IP)
+	if !ok {
+		return nil, newInvalidInputError(IP)
+	}
+
+	return &HostPrefix{
+		host:    host,
+		network: fromIPAndMask(host, mask),
+	}, nil
+
+}
+
+// Host returns the IP address, with its host part
+// @returns string: The IP address in format a.b.c.d
+func (h *HostPrefix) Host() string {
+
+	return utils.ConvertIPToString(h.host)
+
+}
+
+// Network returns the standardized CIDR range containing the IP address
+// @returns *IPv4CIDR: The CIDR range, e.g. 10.0.0.0/24 for 10.0.0.5/24
+func (h *HostPrefix) Network() *IPv4CIDR {
+
+	return h.network
+
+}
+
+// GetMask returns the mask of the CIDR range
+// @returns uint8: The mask of the CIDR range
+func (h *HostPrefix) GetMask() uint8 {
+
+	return h.network.mask
+
+}
+
+// IsNetworkAddress checks if the IP address is the first IP of its CIDR range, i.e. has no host part
+// @returns bool: True if the IP address is the first IP of the CIDR range
+func (h *HostPrefix) IsNetworkAddress() bool {
+
+	return h.host == h.network.ip
+
+}
+
+// ToString converts the HostPrefix to its string representation, keeping the host part
+// @returns string: The IP address and mask in format a.b.c.d/e
+func (h *HostPrefix) ToString() string {
+
+	return h.Host() + "/" + strconv.Itoa(int(h.network.mask))
+
+}
@@ -0,0 +1,52 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"testing"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestHostPrefix parses an interface address with a host part
+// Success Metric: Both the host address and the standardized network are available
+func TestHostPrefix(t *testing.T) {
+
+	prefix, err := NewHostPrefix("10.0.0.5/24")
+	assert.Nil(t, err, "Successfully created a HostPrefix object for 10.0.0.5/24")
+
+	assert.Equal(t, "10.0.0.5", prefix.Host(), "The host part should be kept")
+	assert.Equal(t, "10.0.0.0/24", prefix.Network().ToString(), "The network should be standardized")
+	assert.Equal(t, uint8(24), prefix.GetMask())
+	assert.Equal(t, "10.0.0.5/24", prefix.ToString())
+	assert.False(t, prefix.IsNetworkAddress(), "10.0.0.5 is not the first IP of 10.0.0.0/24")
+
+	prefix, err = NewHostPrefix("10.0.0.0/24")
+	assert.Nil(t, err, "Successfully created a HostPrefix object for 10.0.0.0/24")
+	assert.True(t, prefix.IsNetworkAddress(), "10.0.0.0 is the first IP of 10.0.0.0/24")
+
+	prefix, err = NewHostPrefix("10.0.0.5")
+	assert.Nil(t, err, "Successfully created a HostPrefix object for 10.0.0.5")
+	assert.Equal(t, "10.0.0.5/32", prefix.ToString(), "A single IP should have mask 32")
+
+}
+
+// TestHostPrefixInvalidInput parses invalid interface addresses
+// Success Metric: Throw an error saying the input is invalid
+func TestHostPrefixInvalidInput(t *testing.T) {
+
+	for _, input := range []string{"10.0.0.5/33", "10.0.0/24", "10.0.0.5/"} {
+
+		_, err := NewHostPrefix(input)
+		if assert.Error(t, err, "%s is invalid. An error should be thrown.", input) {
+
+			assert.Equal(t, consts.InvalidIPv4CIDRError, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidIPv4CIDRError)
+
+		}
+
+	}
+
+}
@@ -4,7 +4,6 @@
 package ipv4cidr
 
 import (
-	"errors"
 	"regexp"
 	"strconv"
 	"strings"
@@ -25,6 +24,66 @@ type IPv4CIDR struct {
 	rangeLength uint32
 }
 
+// privateRanges holds the private (RFC 1918) address space
+var privateRanges = []*IPv4CIDR{
+	mustParse(consts.PrivateRange10),
+	mustParse(consts.PrivateRange172),
+	mustParse(consts.PrivateRange192),
+}
+
+// sharedAddressRange holds the shared address space (RFC 6598) used for carrier-grade NAT
+var sharedAddressRange = mustParse(consts.SharedAddressRange)
+
+// Preallocated errors returned by Parser, so that parsing does not allocate even when it fails
+var (
+	errParserInvalidInput    = utils.NewError(consts.InvalidInputCode, consts.InvalidIPv4CIDRError)
+	errParserNotStandardized = utils.NewError(consts.NotStandardizedCode, consts.NonStandardizedIPError)
+)
+
+// Parser parses CIDR ranges into caller-provided IPv4CIDR objects without any heap allocation, for high-volume parsing where GC pressure matters
+// A Parser holds no mutable state, so it can be shared between goroutines
+// @field standardize bool: Flag for whether to standardize non-standard CIDR ranges or reject them
+type Parser struct {
+	standardize bool
+}
+
+// NewParser instantiates a new Parser object and returns it
+// @param standardize bool: If set to "true", non-standard CIDR ranges are converted to the first IP in range. If set to "false", they give an error
+// @returns *Parser: Pointer to the new Parser object
+func NewParser(standardize bool) *Parser {
+
+	return &Parser{
+		standardize: standardize,
+	}
+
+}
+
+// ParseInto parses a string representation of a CIDR range into an existing IPv4CIDR object, overwriting its contents
+// @input dst *IPv4CIDR: The IPv4CIDR object to parse into. It is left unchanged if an error is returned
+// @input IP string: A string representation of CIDR range in the format a.b.c.d/e or a.b.c.d
+// @returns error: If the string is invalid or not standardized (and standardization is off), the appropriate error is returned
+func (p *Parser) ParseInto(dst *IPv4CIDR, IP string) error {
+
+	ip, mask, ok := utils.ParseCIDRUint32(IP)
+	if !ok {
+		return errParserInvalidInput
+	}
+
+	netmask := utils.GetNetmask(mask)
+	standardIP := utils.Standardize(ip, netmask)
+	if standardIP != ip && !p.standardize {
+		return errParserNotStandardized
+	}
+
+	dst.ip = standardIP
+	dst.mask = mask
+	dst.netmask = netmask
+	dst.rangeLength = utils.GetCIDRRangeLength(mask)
+
+	return nil
+
+}
+
 // NewIPv4CIDR instantiates a new IPv4CIDR object and returns it
 // @param IP string: A string representation of CIDR range in the format a.b.c.d/e or a.b.c.d
 // @param standardize bool: If the IP part of the CIDR range is not the first IP in range, then setting this value to "true" will automatically convert it to the first IP in range. If set to "false", a non-standard CIDR will give an error
@@ -38,8 +97,7 @@ func NewIPv4CIDR(IP string, standardize bool) (*IPv4CIDR, error) {
 		return nil, err
 	}
 	if !isValid {
-		err := errors.New(consts.InvalidIPv4CIDRError)
-		return nil, err
+		return nil, newInvalidInputError(IP)
 	}
 
 	// Create an IPv4CIDR object
@@ -50,11 +108,27 @@ func NewIPv4CIDR(IP string, standardize bool) (*IPv4CIDR, error) {
 	if err != nil {
 		return nil, err
 	}
+	checkInvariants(&ip, "NewIPv4CIDR")
 
 	return &ip, nil
 
 }
 
+// mustParse instantiates a new IPv4CIDR object from a string that is known to be a valid, standard CIDR range
+// It is meant for package-level values built from constants, and panics if the string is invalid
+// @param IP string: A string representation of CIDR range in the format a.b.c.d/e or a.b.c.d
+// @returns *IPv4CIDR: Pointer to the new IPv4CIDR object
+func mustParse(IP string) *IPv4CIDR {
+
+	cidr, err := NewIPv4CIDR(IP, false)
+	if err != nil {
+		panic(err)
+	}
+
+	return cidr
+
+}
+
 // parse takes as input the IP string and standardize flag, and parses it
 // @input ipString string: A valid IP/CIDR string
 // @input standardize bool: Flag for whether to standardize non-standard IP string or throw an error
@@ -103,9 +177,8 @@ func (i *IPv4CIDR) parse(ipString string, standardize bool) error {
 	if standardize {
 		ip = utils.Standardize(ip, netmask)
 	} else {
-		err := utils.CheckStandardized(ip, netmask)
-		if err != nil {
-			return err
+		if utils.CheckStandardized(ip, netmask) != nil {
+			return utils.NewDetailedError(consts.NotStandardizedCode, consts.NonStandardizedIPError, ipString, fromIPAndMask(ip, mask).ToString())
 		}
 	}
 
@@ -119,6 +192,51 @@ func (i *IPv4CIDR) parse(ipString string, standardize bool) error {
 
 }
 
+// fromIPAndMask creates an IPv4CIDR object from an integer IP and a mask, standardizing the IP
+// @input ip uint32: The IP address in integer representation
+// @input mask uint8: The mask for the CIDR range (0-32)
+// @returns *IPv4CIDR: Pointer to the new IPv4CIDR object
+func fromIPAndMask(ip uint32, mask uint8) *IPv4CIDR {
+
+	netmask := utils.GetNetmask(mask)
+
+	cidr := &IPv4CIDR{
+		ip:          utils.Standardize(ip, netmask),
+		mask:        mask,
+		netmask:     netmask,
+		rangeLength: utils.GetCIDRRangeLength(mask),
+	}
+	checkInvariants(cidr, "fromIPAndMask")
+
+	return cidr
+
+}
+
+// lastIP returns the last IP address of the CIDR range in integer representation
+// @returns uint32: Last IP in the CIDR range
+func (i *IPv4CIDR) lastIP() uint32 {
+
+	return utils.GetLastIP(i.ip, i.netmask)
+
+}
+
+// contains checks if another CIDR range is entirely within this CIDR range
+// @input other *IPv4CIDR: The CIDR range to check
+// @returns bool: True if every IP of the other CIDR range is also in this CIDR range
+func (i *IPv4CIDR) contains(other *IPv4CIDR) bool {
+
+	return i.mask <= other.mask && utils.Standardize(other.ip, i.netmask) == i.ip
+
+}
+
+// size returns the number of IP addresses in the CIDR range as a 64-bit value, which is safe for /0
+// @returns uint64: Length of the CIDR range
+func (i *IPv4CIDR) size() uint64 {
+
+	return utils.GetCIDRRangeLength64(i.mask)
+
+}
+
 // Split splits the IPv4CIDR into two IPv4CIDRs of half the size (mask + 1)
 // @returns *IPv4CIDR: The first (lower) block
 // @returns *IPv4CIDR: The second (higher) block
@@ -126,19 +244,17 @@ func (i *IPv4CIDR) parse(ipString string, standardize bool) error {
 func (i *IPv4CIDR) Split() (*IPv4CIDR, *IPv4CIDR, error) {
 
 	// If we are already at a single-IP CIDR block, further splitting is not possible. Hence return an error
-	if i.rangeLength == 1 {
-		return nil, nil, errors.New(consts.NoMoreSplittingPossibleError)
+	if i.IsSingleIP() {
+		return nil, nil, utils.NewError(consts.SplitNotPossibleCode, consts.NoMoreSplittingPossibleError)
 	}
 
 	// The new mask becomes the old mask + 1
 	newMask := i.mask + 1
 
-	// The new range is half of old range
-	newRange := i.rangeLength / 2
-
-	// The new netmask has the leftmost 0 of the old netmask also set
-	// In other words, shift right and set the highest bit
-	newNetmask := (i.netmask >> 1) | consts.HighestBitSet
+	// The new range and netmask are looked up for the new mask
+	// The new range is half of old range, and the new netmask has the leftmost 0 of the old netmask also set
+	newRange := utils.GetCIDRRangeLength(newMask)
+	newNetmask := utils.GetNetmask(newMask)
 
 	// The lower CIDR block has the same IP
 	newIP1 := i.ip
@@ -161,11 +277,74 @@ func (i *IPv4CIDR) Split() (*IPv4CIDR, *IPv4CIDR, error) {
 		rangeLength: newRange,
 		netmask:     newNetmask,
 	}
+	checkInvariants(&IP1, "Split")
+	checkInvariants(&IP2, "Split")
 
 	return &IP1, &IP2, nil
 
 }
 
+// MergeWith merges this CIDR range with its sibling into their parent of twice the size (mask - 1), the inverse of Split
+// @input other *IPv4CIDR: The sibling CIDR range, i.e. the other half of the same parent, in either order
+// @returns *IPv4CIDR: The parent CIDR range
+// @returns error: If the CIDR ranges are not the two halves of the same parent, an error is returned
+func (i *IPv4CIDR) MergeWith(other *IPv4CIDR) (*IPv4CIDR, error) {
+
+	// Siblings have the same mask and differ only in the last bit of their network part
+	if i.mask == 0 || i.mask != other.mask || i.ip == other.ip {
+		return nil, utils.NewError(consts.MergeNotPossibleCode, consts.NotSiblingsError)
+	}
+
+	parent := fromIPAndMask(i.ip, i.mask-1)
+	if !parent.contains(other) {
+		return nil, utils.NewError(consts.MergeNotPossibleCode, consts.NotSiblingsError)
+	}
+
+	return parent, nil
+
+}
+
+// Widen returns the CIDR range containing this one with a prefix length shorter by n, standardizing its IP
+// @input n uint8: The number of bits to remove from the mask, e.g. 2 to widen a /24 into a /22
+// @returns *IPv4CIDR: The wider CIDR range
+// @returns error: If the resulting mask would be smaller than 0, an error is returned
+func (i *IPv4CIDR) Widen(n uint8) (*IPv4CIDR, error) {
+
+	if n > i.mask {
+		return nil, utils.NewDetailedError(consts.InvalidMaskCode, consts.InvalidMaskError, int(i.mask)-int(n), 0, consts.MaxBits)
+	}
+
+	return fromIPAndMask(i.ip, i.mask-n), nil
+
+}
+
+// Supernet returns the CIDR range enclosing this one with a mask one bit shorter, e.g. 10.10.0.0/25 for 10.10.0.64/26, the inverse of Split
+// @returns *IPv4CIDR: The enclosing CIDR range
+// @returns error: If the CIDR range is the entire IPv4 address space (/0), an error is returned
+func (i *IPv4CIDR) Supernet() (*IPv4CIDR, error) {
+
+	if i.mask == 0 {
+		return nil, utils.NewError(consts.OutOfRangeCode, consts.NoSupernetError)
+	}
+
+	return fromIPAndMask(i.ip, i.mask-1), nil
+
+}
+
+// Narrow returns the first child of this CIDR range with a prefix length longer by n
+// @input n uint8: The number of bits to add to the mask, e.g. 2 to narrow a /22 into its first /24
+// @returns *IPv4CIDR: The first (lowest) CIDR range of the longer mask within this one
+// @returns error: If the resulting mask would be larger than 32, an error is returned
+func (i *IPv4CIDR) Narrow(n uint8) (*IPv4CIDR, error) {
+
+	if n > consts.MaxBits-i.mask {
+		return nil, utils.NewDetailedError(consts.InvalidMaskCode, consts.InvalidMaskError, int(i.mask)+int(n), 0, consts.MaxBits)
+	}
+
+	return fromIPAndMask(i.ip, i.mask+n), nil
+
+}
+
 // GetIPInRange returns the nth IP address in the CIDR block
 // @input n uint32: The value of n, representing the nth IP to return
 // @input withCIDR bool: Flag corresponding to whether to append the CIDR mask with the returned IP or not
@@ -175,10 +354,11 @@ func (i *IPv4CIDR) GetIPInRange(n uint32, withCIDR bool) (string, error) {
 
 	// Check if range exceeded, return error if yes
 	if i.rangeLength < n {
-		return "", errors.New(consts.RequestedIPExceedsCIDRRangeError)
+		return "", utils.NewDetailedError(consts.OutOfRangeCode, consts.RequestedIPExceedsCIDRRangeError, n, 1, i.rangeLength)
 	}
 
 	// The nth IP is obtained by simply adding n-1 to the 1st IP in CIDR range
+	checkOffset(i, uint64(n)-1, "GetIPInRange")
 	nthIP := i.ip + n - 1
 
 	// Convert the IP to string
@@ -213,6 +393,45 @@ func (i *IPv4CIDR) GetIP() string {
 
 }
 
+// GetLastIP returns the last IP address of the CIDR range, e.g. 10.10.0.63 for 10.10.0.0/26
+// @returns string: String corresponding to the last IP address in CIDR range in format a.b.c.d
+func (i *IPv4CIDR) GetLastIP() string {
+
+	return utils.ConvertIPToString(i.lastIP())
+
+}
+
+// GetBroadcastAddress returns the broadcast address of the CIDR range, i.e. its last IP address
+// A /31 (RFC 3021) or a /32 has no broadcast address, in which case the last IP address is still returned
+// @returns string: String corresponding to the last IP address in CIDR range in format a.b.c.d
+func (i *IPv4CIDR) GetBroadcastAddress() string {
+
+	return i.GetLastIP()
+
+}
+
+// GetFirstUsableIP returns the first usable host address of the CIDR range under DefaultHostPolicy
+// The network address is skipped for /30 and larger, while both addresses of a /31 (RFC 3021) and the single address of a /32 are usable
+// @returns string: String corresponding to the first usable IP address in format a.b.c.d
+func (i *IPv4CIDR) GetFirstUsableIP() string {
+
+	first, _, _ := DefaultHostPolicy.UsableRange(i)
+
+	return utils.ConvertIPToString(first)
+
+}
+
+// GetLastUsableIP returns the last usable host address of the CIDR range under DefaultHostPolicy
+// The broadcast address is skipped for /30 and larger, while both addresses of a /31 (RFC 3021) and the single address of a /32 are usable
+// @returns string: String corresponding to the last usable IP address in format a.b.c.d
+func (i *IPv4CIDR) GetLastUsableIP() string {
+
+	_, last, _ := DefaultHostPolicy.UsableRange(i)
+
+	return utils.ConvertIPToString(last)
+
+}
+
 // GetCIDRRangeLength returns the number of IP addresses contained in the CIDR range
 // @returns uint32: Length of the CIDR range
 func (i *IPv4CIDR) GetCIDRRangeLength() uint32 {
@@ -221,6 +440,15 @@ func (i *IPv4CIDR) GetCIDRRangeLength() uint32 {
 
 }
 
+// GetUsableHostCount returns the number of assignable host addresses in the CIDR range under DefaultHostPolicy
+// The network and broadcast addresses are subtracted for /30 and larger, while a /31 (RFC 3021) has 2 usable addresses and a /32 has 1
+// @returns uint64: Number of usable host addresses, 64-bit for consistency with HostPolicy.UsableHostCount
+func (i *IPv4CIDR) GetUsableHostCount() uint64 {
+
+	return DefaultHostPolicy.UsableHostCount(i)
+
+}
+
 // GetMask returns the mask part of the CIDR range (0-32)
 // @returns uint8: Mask of the CIDR range
 func (i *IPv4CIDR) GetMask() uint8 {
@@ -236,3 +464,119 @@ func (i *IPv4CIDR) GetNetmask() string {
 	return utils.ConvertIPToString(i.netmask)
 
 }
+
+// IsPrivate checks if the CIDR range is entirely within the private (RFC 1918) address space
+// @returns bool: True if the CIDR range is within 10.0.0.0/8, 172.16.0.0/12 or 192.168.0.0/16
+func (i *IPv4CIDR) IsPrivate() bool {
+
+	for _, privateRange := range privateRanges {
+		if privateRange.contains(i) {
+			return true
+		}
+	}
+
+	return false
+
+}
+
+// IsSharedAddressSpace checks if the CIDR range is entirely within the shared address space (RFC 6598) used for carrier-grade NAT
+// The shared address space is not private address space, but large Kubernetes and carrier deployments commonly use it as an additional internal pool
+// @returns bool: True if the CIDR range is within 100.64.0.0/10
+func (i *IPv4CIDR) IsSharedAddressSpace() bool {
+
+	return sharedAddressRange.contains(i)
+
+}
+
+// IsSingleIP checks if the CIDR range contains exactly one IP address
+// @returns bool: True if the mask is 32
+func (i *IPv4CIDR) IsSingleIP() bool {
+
+	return i.mask == consts.MaxBits
+
+}
+
+// IsHostRoute checks if the CIDR range is a host route, i.e. a route to a single destination address
+// It is equivalent to IsSingleIP, and reads better in routing code
+// @returns bool: True if the mask is 32
+func (i *IPv4CIDR) IsHostRoute() bool {
+
+	return i.IsSingleIP()
+
+}
+
+// IsEntireIPv4Space checks if the CIDR range covers the whole IPv4 address space, i.e. is the default route 0.0.0.0/0
+// @returns bool: True if the mask is 0
+func (i *IPv4CIDR) IsEntireIPv4Space() bool {
+
+	return i.mask == 0
+
+}
+
+// Range returns the first and last IP addresses of the CIDR range in integer representation, for interoperating with interval-based systems
+// @returns uint32: First IP in the CIDR range
+// @returns uint32: Last IP in the CIDR range
+func (i *IPv4CIDR) Range() (uint32, uint32) {
+
+	return i.ip, i.lastIP()
+
+}
+
+// ContainsRange checks if an inclusive range of IP addresses is entirely within the CIDR range
+// @input start uint32: First IP of the range in integer representation
+// @input end uint32: Last IP of the range in integer representation
+// @returns bool: True if every IP from start to end is in the CIDR range. False if start is after end
+func (i *IPv4CIDR) ContainsRange(start uint32, end uint32) bool {
+
+	return start <= end && i.ip <= start && end <= i.lastIP()
+
+}
+
+// Overlaps checks if two CIDR ranges share any IP address, e.g. to validate that peered address spaces do not collide
+// The ranges are compared as intervals of IP addresses: they overlap when each one starts at or before the last IP of the other. This does not rely on CIDR ranges being aligned
+// @input other *IPv4CIDR: The CIDR range to check
+// @returns bool: True if at least one IP is in both CIDR ranges
+func (i *IPv4CIDR) Overlaps(other *IPv4CIDR) bool {
+
+	return i.ip <= other.lastIP() && other.ip <= i.lastIP()
+
+}
+
+// IsAdjacent checks if two CIDR ranges of any size are contiguous, i.e. one ends exactly where the other begins
+// @input other *IPv4CIDR: The CIDR range to check
+// @returns bool: True if the IP after the last IP of one CIDR range is the first IP of the other
+func (i *IPv4CIDR) IsAdjacent(other *IPv4CIDR) bool {
+
+	// Computed in 64 bits, so that a range ending at 255.255.255.255 is not adjacent to one starting at 0.0.0.0
+	return uint64(i.lastIP())+1 == uint64(other.ip) || uint64(other.lastIP())+1 == uint64(i.ip)
+
+}
+
+// Equal checks if two CIDR ranges are the same block, i.e. have the same IP and mask
+// @input other *IPv4CIDR: The CIDR range to compare with
+// @returns bool: True if both CIDR ranges cover exactly the same IP addresses
+func (i *IPv4CIDR) Equal(other *IPv4CIDR) bool {
+
+	return i.ip == other.ip && i.mask == other.mask
+
+}
+
+// Compare orders two CIDR ranges by IP, and for the same IP puts the larger block (smaller mask) first, e.g. to sort and deduplicate lists deterministically
+// @input other *IPv4CIDR: The CIDR range to compare with
+// @returns int: -1 if this CIDR range comes first, 1 if the other comes first, 0 if they are equal
+func (i *IPv4CIDR) Compare(other *IPv4CIDR) int {
+
+	switch {
+	case i.ip < other.ip:
+		return -1
+	case i.ip > other.ip:
+		return 1
+	case i.mask < other.mask:
+		return -1
+	case i.mask > other.mask:
+		return 1
+	}
+
+	return 0
+
+}
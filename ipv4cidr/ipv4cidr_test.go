@@ -121,6 +121,82 @@ func TestUnsplittableCIDRRange(t *testing.T) {
 
 }
 
+// TestWidenAndNarrow changes the mask of a CIDR range in both directions
+// Success Metric: Widening standardizes the IP, and narrowing returns the first child
+func TestWidenAndNarrow(t *testing.T) {
+
+	CIDR, _ := NewIPv4CIDR("10.10.5.0/24", false)
+
+	wider, err := CIDR.Widen(2)
+	assert.Nil(t, err, "10.10.5.0/24 can be widened by 2 bits")
+	assert.Equal(t, "10.10.4.0/22", wider.ToString(), "The IP should be standardized to the wider range")
+
+	wider, err = CIDR.Widen(24)
+	assert.Nil(t, err, "10.10.5.0/24 can be widened by 24 bits")
+	assert.Equal(t, "0.0.0.0/0", wider.ToString())
+
+	narrower, err := CIDR.Narrow(4)
+	assert.Nil(t, err, "10.10.5.0/24 can be narrowed by 4 bits")
+	assert.Equal(t, "10.10.5.0/28", narrower.ToString())
+
+	narrower, err = CIDR.Narrow(0)
+	assert.Nil(t, err, "Narrowing by 0 bits returns the same range")
+	assert.Equal(t, "10.10.5.0/24", narrower.ToString())
+
+}
+
+// TestWidenAndNarrowInvalidMask changes the mask of a CIDR range past 0 or 32
+// Success Metric: Throw an error saying the mask is invalid
+func TestWidenAndNarrowInvalidMask(t *testing.T) {
+
+	CIDR, _ := NewIPv4CIDR("10.10.5.0/24", false)
+
+	_, err := CIDR.Widen(25)
+	if assert.Error(t, err, "%s cannot be widened by 25 bits. An error should be thrown.", "10.10.5.0/24") {
+
+		assert.Equal(t, consts.InvalidMaskError, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidMaskError)
+
+	}
+
+	_, err = CIDR.Narrow(9)
+	if assert.Error(t, err, "%s cannot be narrowed by 9 bits. An error should be thrown.", "10.10.5.0/24") {
+
+		assert.Equal(t, consts.InvalidMaskError, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidMaskError)
+
+	}
+
+}
+
+// TestSupernet gets the enclosing CIDR range of CIDR ranges of various sizes
+// Success Metric: The CIDR range with a mask one bit shorter is returned, with its IP standardized
+func TestSupernet(t *testing.T) {
+
+	for input, expected := range map[string]string{
+		"10.10.0.64/26":  "10.10.0.0/25",
+		"10.10.0.0/26":   "10.10.0.0/25",
+		"10.10.0.5":      "10.10.0.4/31",
+		"192.168.0.0/16": "192.168.0.0/15",
+		"128.0.0.0/1":    "0.0.0.0/0",
+	} {
+
+		CIDR, _ := NewIPv4CIDR(input, false)
+		supernet, err := CIDR.Supernet()
+		if assert.Nil(t, err, "%s has a supernet", input) {
+			assert.Equal(t, expected, supernet.ToString())
+		}
+
+	}
+
+	CIDR, _ := NewIPv4CIDR("0.0.0.0/0", false)
+	_, err := CIDR.Supernet()
+	if assert.Error(t, err, "0.0.0.0/0 has no supernet. An error should be thrown.") {
+
+		assert.Equal(t, consts.NoSupernetError, err.Error(), "Error thrown should be: \"%s\"", consts.NoSupernetError)
+
+	}
+
+}
+
 // TestSingleIPInput takes an IP address as valid CIDR input
 // Success Metric: Create an IPv4CIDR object with mask = 32
 func TestSingleIPInput(t *testing.T) {
@@ -166,3 +242,359 @@ func TestNthIPNotInRange(t *testing.T) {
 	}
 
 }
+
+// TestIsPrivate checks CIDR ranges against the private (RFC 1918) address space
+// Success Metric: Only ranges entirely within the private address space are private
+func TestIsPrivate(t *testing.T) {
+
+	privateInputs := []string{"10.10.0.0/16", "172.31.255.0/24", "192.168.1.1", "10.0.0.0/8"}
+	for _, input := range privateInputs {
+
+		CIDR, _ := NewIPv4CIDR(input, false)
+		assert.True(t, CIDR.IsPrivate(), "%s is private", input)
+
+	}
+
+	publicInputs := []string{"8.8.8.8", "172.32.0.0/16", "0.0.0.0/0", "172.0.0.0/8", "100.64.0.0/10"}
+	for _, input := range publicInputs {
+
+		CIDR, _ := NewIPv4CIDR(input, false)
+		assert.False(t, CIDR.IsPrivate(), "%s is not private", input)
+
+	}
+
+}
+
+// TestIsSharedAddressSpace checks CIDR ranges within and outside the shared address space
+// Success Metric: Only CIDR ranges within 100.64.0.0/10 are shared, and they are not private
+func TestIsSharedAddressSpace(t *testing.T) {
+
+	sharedInputs := []string{"100.64.0.0/10", "100.100.0.0/16", "100.127.255.255"}
+	for _, input := range sharedInputs {
+
+		CIDR, _ := NewIPv4CIDR(input, false)
+		assert.True(t, CIDR.IsSharedAddressSpace(), "%s is shared", input)
+		assert.False(t, CIDR.IsPrivate(), "%s is not private", input)
+
+	}
+
+	otherInputs := []string{"100.0.0.0/8", "100.128.0.0/16", "10.0.0.0/8"}
+	for _, input := range otherInputs {
+
+		CIDR, _ := NewIPv4CIDR(input, false)
+		assert.False(t, CIDR.IsSharedAddressSpace(), "%s is not shared", input)
+
+	}
+
+}
+
+// TestParserParseInto parses CIDR ranges into an existing IPv4CIDR object
+// Success Metric: The object matches the one created by NewIPv4CIDR, and invalid inputs throw the same errors
+func TestParserParseInto(t *testing.T) {
+
+	inputs := []string{"10.10.0.0/26", "10.2.3.4", "0.0.0.0/0", "10.10.0.1/26"}
+	parser := NewParser(true)
+	CIDR := IPv4CIDR{}
+
+	for _, input := range inputs {
+
+		expected, _ := NewIPv4CIDR(input, true)
+		err := parser.ParseInto(&CIDR, input)
+		assert.Nil(t, err, "%s is a valid CIDR block, it should be parsed.", input)
+		assert.Equal(t, *expected, CIDR, "Parsed object for %s should match NewIPv4CIDR", input)
+
+	}
+
+	err := NewParser(false).ParseInto(&CIDR, "10.10.0.1/26")
+	if assert.Error(t, err, "10.10.0.1/26 is not standard. An error should be thrown.") {
+
+		assert.Equal(t, consts.NonStandardizedIPError, err.Error(), "Error thrown should be: \"%s\"", consts.NonStandardizedIPError)
+
+	}
+
+	err = parser.ParseInto(&CIDR, "10.10.0.0/33")
+	if assert.Error(t, err, "10.10.0.0/33 is an invalid CIDR block. An error should be thrown.") {
+
+		assert.Equal(t, consts.InvalidIPv4CIDRError, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidIPv4CIDRError)
+
+	}
+	assert.Equal(t, "10.10.0.0/26", CIDR.ToString(), "The object should be unchanged after an error")
+
+}
+
+// TestParserParseIntoAllocations checks that parsing into an existing object does not allocate
+// Success Metric: Zero allocations per parse, for both valid and invalid inputs
+func TestParserParseIntoAllocations(t *testing.T) {
+
+	parser := NewParser(false)
+	CIDR := IPv4CIDR{}
+
+	allocations := testing.AllocsPerRun(100, func() {
+		parser.ParseInto(&CIDR, "192.168.100.0/24")
+		parser.ParseInto(&CIDR, "192.168.100.1/24")
+		parser.ParseInto(&CIDR, "192.168.100.256")
+	})
+	assert.Equal(t, float64(0), allocations)
+
+}
+
+// BenchmarkParserParseInto measures parsing a CIDR range into an existing IPv4CIDR object
+func BenchmarkParserParseInto(b *testing.B) {
+
+	parser := NewParser(true)
+	CIDR := IPv4CIDR{}
+
+	for n := 0; n < b.N; n++ {
+		parser.ParseInto(&CIDR, "10.10.0.1/26")
+	}
+
+}
+
+// BenchmarkNewIPv4CIDR measures parsing a CIDR range from its string representation
+func BenchmarkNewIPv4CIDR(b *testing.B) {
+
+	for n := 0; n < b.N; n++ {
+		NewIPv4CIDR("10.10.0.1/26", true)
+	}
+
+}
+
+// BenchmarkSplit measures splitting a CIDR range into two halves
+func BenchmarkSplit(b *testing.B) {
+
+	CIDR, _ := NewIPv4CIDR("10.10.0.0/16", false)
+
+	for n := 0; n < b.N; n++ {
+		CIDR.Split()
+	}
+
+}
+
+// TestPredicates checks the single IP, host route and entire space predicates on edge case CIDR ranges
+// Success Metric: Only /32 ranges are single IPs and host routes, and only /0 is the entire IPv4 space
+func TestPredicates(t *testing.T) {
+
+	CIDR, _ := NewIPv4CIDR("10.10.0.1", false)
+	assert.True(t, CIDR.IsSingleIP(), "10.10.0.1 is a single IP")
+	assert.True(t, CIDR.IsHostRoute(), "10.10.0.1 is a host route")
+	assert.False(t, CIDR.IsEntireIPv4Space(), "10.10.0.1 is not the entire IPv4 space")
+
+	CIDR, _ = NewIPv4CIDR("10.10.0.0/31", false)
+	assert.False(t, CIDR.IsSingleIP(), "10.10.0.0/31 has two IPs")
+	assert.False(t, CIDR.IsHostRoute(), "10.10.0.0/31 is not a host route")
+
+	CIDR, _ = NewIPv4CIDR("0.0.0.0/0", false)
+	assert.False(t, CIDR.IsSingleIP(), "0.0.0.0/0 is not a single IP")
+	assert.True(t, CIDR.IsEntireIPv4Space(), "0.0.0.0/0 is the entire IPv4 space")
+
+}
+
+// TestRange gets the first and last IPs of CIDR ranges in integer format
+// Success Metric: The correct bounds are returned, including for 0.0.0.0/0
+func TestRange(t *testing.T) {
+
+	CIDR, _ := NewIPv4CIDR("10.10.0.0/26", false)
+	start, end := CIDR.Range()
+	assert.Equal(t, uint32(168427520), start, "First IP should be 10.10.0.0")
+	assert.Equal(t, uint32(168427583), end, "Last IP should be 10.10.0.63")
+
+	CIDR, _ = NewIPv4CIDR("0.0.0.0/0", false)
+	start, end = CIDR.Range()
+	assert.Equal(t, uint32(0), start, "First IP should be 0.0.0.0")
+	assert.Equal(t, consts.MaxUInt32, end, "Last IP should be 255.255.255.255")
+
+}
+
+// TestContainsRange checks if ranges of IPs in integer format are within a CIDR range
+// Success Metric: Only ranges entirely within the CIDR range are contained
+func TestContainsRange(t *testing.T) {
+
+	CIDR, _ := NewIPv4CIDR("10.10.0.0/26", false)
+
+	assert.True(t, CIDR.ContainsRange(168427520, 168427583), "The CIDR range contains itself")
+	assert.True(t, CIDR.ContainsRange(168427530, 168427530), "The CIDR range contains a single IP within it")
+	assert.False(t, CIDR.ContainsRange(168427519, 168427530), "The range starts before the CIDR range")
+	assert.False(t, CIDR.ContainsRange(168427530, 168427584), "The range ends after the CIDR range")
+	assert.False(t, CIDR.ContainsRange(168427540, 168427530), "The range starts after it ends")
+
+}
+
+// TestOverlaps checks if pairs of CIDR ranges share IP addresses
+// Success Metric: Nested and identical ranges overlap in both directions, and disjoint or adjacent ranges do not
+func TestOverlaps(t *testing.T) {
+
+	CIDR, _ := NewIPv4CIDR("10.10.0.0/16", false)
+
+	for other, expected := range map[string]bool{
+		"10.10.0.0/16":   true,
+		"10.10.4.0/24":   true,
+		"10.10.255.255":  true,
+		"10.0.0.0/8":     true,
+		"0.0.0.0/0":      true,
+		"10.11.0.0/16":   false,
+		"10.9.255.255":   false,
+		"192.168.0.0/16": false,
+	} {
+
+		otherCIDR, _ := NewIPv4CIDR(other, false)
+		assert.Equal(t, expected, CIDR.Overlaps(otherCIDR), "Overlap of 10.10.0.0/16 and %s", other)
+		assert.Equal(t, expected, otherCIDR.Overlaps(CIDR), "Overlap of %s and 10.10.0.0/16", other)
+
+	}
+
+}
+
+// TestEqualAndCompare compares and sorts CIDR ranges
+// Success Metric: Ranges are equal only with the same IP and mask, and are ordered by IP then by mask
+func TestEqualAndCompare(t *testing.T) {
+
+	CIDR, _ := NewIPv4CIDR("10.10.0.0/16", false)
+	same, _ := NewIPv4CIDR("10.10.0.1/16", true)
+	child, _ := NewIPv4CIDR("10.10.0.0/24", false)
+	next, _ := NewIPv4CIDR("10.11.0.0/16", false)
+
+	assert.True(t, CIDR.Equal(same), "10.10.0.1/16 standardizes to 10.10.0.0/16")
+	assert.False(t, CIDR.Equal(child), "10.10.0.0/24 has a different mask")
+	assert.False(t, CIDR.Equal(next), "10.11.0.0/16 has a different IP")
+
+	assert.Equal(t, 0, CIDR.Compare(same))
+	assert.Equal(t, -1, CIDR.Compare(child), "The larger block comes first for the same IP")
+	assert.Equal(t, 1, child.Compare(CIDR))
+	assert.Equal(t, -1, child.Compare(next), "The lower IP comes first, whatever the mask")
+	assert.Equal(t, 1, next.Compare(child))
+
+}
+
+// TestGetLastIP gets the last IP address of CIDR ranges
+// Success Metric: The last IP address is returned, also as the broadcast address, including for /32 and 0.0.0.0/0
+func TestGetLastIP(t *testing.T) {
+
+	for cidr, expected := range map[string]string{
+		"10.10.0.0/26": "10.10.0.63",
+		"10.10.0.0/31": "10.10.0.1",
+		"10.10.0.7":    "10.10.0.7",
+		"0.0.0.0/0":    "255.255.255.255",
+	} {
+
+		CIDR, _ := NewIPv4CIDR(cidr, false)
+		assert.Equal(t, expected, CIDR.GetLastIP(), "Last IP of %s", cidr)
+		assert.Equal(t, expected, CIDR.GetBroadcastAddress(), "Broadcast address of %s", cidr)
+
+	}
+
+}
+
+// TestGetUsableIPs gets the first and last usable host addresses of CIDR ranges
+// Success Metric: Network and broadcast are skipped for /30 and larger, and every address of a /31 or /32 is usable
+func TestGetUsableIPs(t *testing.T) {
+
+	for cidr, expected := range map[string][2]string{
+		"10.10.0.0/26": {"10.10.0.1", "10.10.0.62"},
+		"10.10.0.0/30": {"10.10.0.1", "10.10.0.2"},
+		"10.10.0.0/31": {"10.10.0.0", "10.10.0.1"},
+		"10.10.0.7":    {"10.10.0.7", "10.10.0.7"},
+		"0.0.0.0/0":    {"0.0.0.1", "255.255.255.254"},
+	} {
+
+		CIDR, _ := NewIPv4CIDR(cidr, false)
+		assert.Equal(t, expected[0], CIDR.GetFirstUsableIP(), "First usable IP of %s", cidr)
+		assert.Equal(t, expected[1], CIDR.GetLastUsableIP(), "Last usable IP of %s", cidr)
+
+	}
+
+}
+
+// TestGetUsableHostCount counts the usable host addresses of CIDR ranges
+// Success Metric: Network and broadcast are subtracted for /30 and larger, and /31 and /32 are special-cased
+func TestGetUsableHostCount(t *testing.T) {
+
+	for cidr, expected := range map[string]uint64{
+		"10.10.0.0/24": 254,
+		"10.10.0.0/30": 2,
+		"10.10.0.0/31": 2,
+		"10.10.0.7":    1,
+		"0.0.0.0/0":    4294967294,
+	} {
+
+		CIDR, _ := NewIPv4CIDR(cidr, false)
+		assert.Equal(t, expected, CIDR.GetUsableHostCount(), "Usable hosts of %s", cidr)
+
+	}
+
+}
+
+// TestIsAdjacent checks if pairs of CIDR ranges are contiguous
+// Success Metric: Ranges of any size ending right before the other begins are adjacent, in both directions, without wrapping around the IPv4 space
+func TestIsAdjacent(t *testing.T) {
+
+	CIDR, _ := NewIPv4CIDR("10.10.0.0/24", false)
+
+	for other, expected := range map[string]bool{
+		"10.10.1.0/24":   true,
+		"10.10.1.0/32":   true,
+		"10.10.2.0/23":   false,
+		"10.9.255.255":   true,
+		"10.9.0.0/16":    true,
+		"10.10.0.0/25":   false,
+		"10.10.0.0/24":   false,
+		"192.168.0.0/24": false,
+	} {
+
+		otherCIDR, _ := NewIPv4CIDR(other, false)
+		assert.Equal(t, expected, CIDR.IsAdjacent(otherCIDR), "Adjacency of 10.10.0.0/24 and %s", other)
+		assert.Equal(t, expected, otherCIDR.IsAdjacent(CIDR), "Adjacency of %s and 10.10.0.0/24", other)
+
+	}
+
+	first, _ := NewIPv4CIDR("0.0.0.0/8", false)
+	last, _ := NewIPv4CIDR("255.0.0.0/8", false)
+	assert.False(t, first.IsAdjacent(last), "The IPv4 space does not wrap around")
+
+}
+
+// TestMergeWith merges the halves of split CIDR ranges back into their parent
+// Success Metric: Siblings merge into their parent in either order, including the two /1s into 0.0.0.0/0
+func TestMergeWith(t *testing.T) {
+
+	for _, input := range []string{"10.10.0.0/26", "10.10.0.0/31", "0.0.0.0/0"} {
+
+		CIDR, _ := NewIPv4CIDR(input, false)
+		lower, upper, _ := CIDR.Split()
+
+		merged, err := lower.MergeWith(upper)
+		assert.Nil(t, err, "The halves of %s are siblings, they should be merged", input)
+		assert.Equal(t, input, merged.ToString())
+
+		merged, err = upper.MergeWith(lower)
+		assert.Nil(t, err, "Siblings should be merged in either order")
+		assert.Equal(t, input, merged.ToString())
+
+	}
+
+}
+
+// TestMergeWithNotSiblings attempts to merge CIDR ranges that are not the two halves of the same parent
+// Success Metric: Throw an error saying the CIDR ranges cannot be merged
+func TestMergeWithNotSiblings(t *testing.T) {
+
+	for _, pair := range [][2]string{
+		{"10.10.0.64/26", "10.10.0.128/26"},
+		{"10.10.0.0/26", "10.10.0.64/27"},
+		{"10.10.0.0/26", "10.10.0.0/26"},
+		{"10.10.0.0/26", "10.20.0.64/26"},
+		{"0.0.0.0/0", "0.0.0.0/0"},
+	} {
+
+		first, _ := NewIPv4CIDR(pair[0], false)
+		second, _ := NewIPv4CIDR(pair[1], false)
+
+		_, err := first.MergeWith(second)
+		if assert.Error(t, err, "%s and %s are not siblings. An error should be thrown.", pair[0], pair[1]) {
+
+			assert.Equal(t, consts.NotSiblingsError, err.Error(), "Error thrown should be: \"%s\"", consts.NotSiblingsError)
+
+		}
+
+	}
+
+}
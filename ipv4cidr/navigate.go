@@ -0,0 +1,83 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/utils"
+)
+
+// BoundaryBehavior defines what NextIP and PrevIP do when stepping past the bounds of the CIDR range
+type BoundaryBehavior int
+
+const (
+	// BoundaryError returns an error when stepping past the bounds of the CIDR range
+	BoundaryError BoundaryBehavior = iota
+	// BoundaryWrap wraps around to the other end of the CIDR range
+	BoundaryWrap
+	// BoundaryStepOutside returns the IP address outside of the CIDR range, as long as it is within the IPv4 address space
+	BoundaryStepOutside
+)
+
+// NextIP returns the IP address following an IP address of the CIDR range
+// @input ip string: The IP address in format a.b.c.d, within the CIDR range
+// @input boundary BoundaryBehavior: What to do if the IP address is the last IP of the CIDR range
+// @returns string: The next IP address in format a.b.c.d
+// @returns error: If the IP address is invalid or not in the CIDR range, or the step past the last IP is not allowed, the appropriate error is returned
+func (i *IPv4CIDR) NextIP(ip string, boundary BoundaryBehavior) (string, error) {
+
+	return i.step(ip, 1, boundary)
+
+}
+
+// PrevIP returns the IP address preceding an IP address of the CIDR range
+// @input ip string: The IP address in format a.b.c.d, within the CIDR range
+// @input boundary BoundaryBehavior: What to do if the IP address is the first IP of the CIDR range
+// @returns string: The previous IP address in format a.b.c.d
+// @returns error: If the IP address is invalid or not in the CIDR range, or the step past the first IP is not allowed, the appropriate error is returned
+func (i *IPv4CIDR) PrevIP(ip string, boundary BoundaryBehavior) (string, error) {
+
+	return i.step(ip, -1, boundary)
+
+}
+
+// step moves from an IP address of the CIDR range to its neighbor, applying the boundary behavior at the bounds of the CIDR range
+// @input ip string: The IP address in format a.b.c.d, within the CIDR range
+// @input direction int64: 1 to move to the next IP address, -1 to move to the previous one
+// @input boundary BoundaryBehavior: What to do when stepping past the bounds of the CIDR range
+// @returns string: The neighboring IP address in format a.b.c.d
+// @returns error: If the IP address is invalid or not in the CIDR range, or the step is not allowed, the appropriate error is returned
+func (i *IPv4CIDR) step(ip string, direction int64, boundary BoundaryBehavior) (string, error) {
+
+	value, err := utils.ParseIPUint32(ip)
+	if err != nil {
+		return "", err
+	}
+
+	if !i.ContainsRange(value, value) {
+		return "", utils.NewError(consts.OutOfRangeCode, consts.IPNotInCIDRRangeError)
+	}
+
+	// Compute in 64 bits, so that stepping past either end of the IPv4 space can be detected
+	neighbor := int64(value) + direction
+	if neighbor >= int64(i.ip) && neighbor <= int64(i.lastIP()) {
+		return utils.ConvertIPToString(uint32(neighbor)), nil
+	}
+
+	switch boundary {
+	case BoundaryWrap:
+		if direction > 0 {
+			return utils.ConvertIPToString(i.ip), nil
+		}
+		return utils.ConvertIPToString(i.lastIP()), nil
+	case BoundaryStepOutside:
+		if neighbor < 0 || neighbor > int64(consts.MaxUInt32) {
+			return "", utils.NewError(consts.OutOfRangeCode, consts.EndOfAddressSpaceError)
+		}
+		return utils.ConvertIPToString(uint32(neighbor)), nil
+	}
+
+	return "", utils.NewError(consts.OutOfRangeCode, consts.RequestedIPExceedsCIDRRangeError)
+
+}
@@ -0,0 +1,89 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"testing"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNextIPAndPrevIP steps between IPs within a CIDR range
+// Success Metric: The neighboring IPs are returned, across octet boundaries
+func TestNextIPAndPrevIP(t *testing.T) {
+
+	CIDR, _ := NewIPv4CIDR("10.0.0.0/23", false)
+
+	ip, err := CIDR.NextIP("10.0.0.255", BoundaryError)
+	assert.Nil(t, err)
+	assert.Equal(t, "10.0.1.0", ip)
+
+	ip, err = CIDR.PrevIP("10.0.1.0", BoundaryError)
+	assert.Nil(t, err)
+	assert.Equal(t, "10.0.0.255", ip)
+
+}
+
+// TestNextIPAndPrevIPBoundary steps past the bounds of a CIDR range with every boundary behavior
+// Success Metric: The step fails, wraps around, or leaves the CIDR range as requested
+func TestNextIPAndPrevIPBoundary(t *testing.T) {
+
+	CIDR, _ := NewIPv4CIDR("10.0.0.0/24", false)
+
+	_, err := CIDR.NextIP("10.0.0.255", BoundaryError)
+	if assert.Error(t, err, "10.0.0.255 is the last IP of the CIDR range. An error should be thrown.") {
+
+		assert.Equal(t, consts.RequestedIPExceedsCIDRRangeError, err.Error(), "Error thrown should be: \"%s\"", consts.RequestedIPExceedsCIDRRangeError)
+
+	}
+
+	ip, err := CIDR.NextIP("10.0.0.255", BoundaryWrap)
+	assert.Nil(t, err)
+	assert.Equal(t, "10.0.0.0", ip)
+
+	ip, err = CIDR.PrevIP("10.0.0.0", BoundaryWrap)
+	assert.Nil(t, err)
+	assert.Equal(t, "10.0.0.255", ip)
+
+	ip, err = CIDR.NextIP("10.0.0.255", BoundaryStepOutside)
+	assert.Nil(t, err)
+	assert.Equal(t, "10.0.1.0", ip)
+
+	ip, err = CIDR.PrevIP("10.0.0.0", BoundaryStepOutside)
+	assert.Nil(t, err)
+	assert.Equal(t, "9.255.255.255", ip)
+
+	CIDR, _ = NewIPv4CIDR("0.0.0.0/0", false)
+	_, err = CIDR.PrevIP("0.0.0.0", BoundaryStepOutside)
+	if assert.Error(t, err, "There is no IP before 0.0.0.0. An error should be thrown.") {
+
+		assert.Equal(t, consts.EndOfAddressSpaceError, err.Error(), "Error thrown should be: \"%s\"", consts.EndOfAddressSpaceError)
+
+	}
+
+}
+
+// TestNextIPNotInRange checks IPs that are invalid or outside of the CIDR range
+// Success Metric: Errors are returned with the appropriate messages
+func TestNextIPNotInRange(t *testing.T) {
+
+	CIDR, _ := NewIPv4CIDR("10.0.0.0/24", false)
+
+	_, err := CIDR.NextIP("10.0.1.0", BoundaryStepOutside)
+	if assert.Error(t, err, "10.0.1.0 is not in the CIDR range. An error should be thrown.") {
+
+		assert.Equal(t, consts.IPNotInCIDRRangeError, err.Error(), "Error thrown should be: \"%s\"", consts.IPNotInCIDRRangeError)
+
+	}
+
+	_, err = CIDR.PrevIP("10.0.0.300", BoundaryError)
+	if assert.Error(t, err, "10.0.0.300 is an invalid IP. An error should be thrown.") {
+
+		assert.Equal(t, consts.InvalidIPv4Error, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidIPv4Error)
+
+	}
+
+}
@@ -0,0 +1,62 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"sort"
+)
+
+// Overlap describes a pair of CIDR ranges that share IP addresses
+// @field First *IPv4CIDR: The CIDR range with the lower IP (or the larger range, if both start at the same IP)
+// @field Second *IPv4CIDR: The other CIDR range
+// @field Region *IPv4CIDR: The CIDR range of addresses shared by both
+// @field Size uint64: Number of IP addresses shared by both
+type Overlap struct {
+	First  *IPv4CIDR
+	Second *IPv4CIDR
+	Region *IPv4CIDR
+	Size   uint64
+}
+
+// OverlapReport finds every pair of CIDR ranges in a list that share IP addresses
+// @input cidrs []*IPv4CIDR: The list of CIDR ranges to check
+// @returns []Overlap: Every overlapping pair, sorted by severity (largest overlap first)
+func OverlapReport(cidrs []*IPv4CIDR) []Overlap {
+
+	sorted := make([]*IPv4CIDR, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		if cidr != nil {
+			sorted = append(sorted, cidr)
+		}
+	}
+
+	sort.SliceStable(sorted, func(a, b int) bool {
+		return sorted[a].Compare(sorted[b]) < 0
+	})
+
+	report := make([]Overlap, 0)
+	for a := range sorted {
+
+		// Only the ranges starting before the end of this one can overlap it, and those follow it directly in sorted order
+		for b := a + 1; b < len(sorted) && sorted[b].ip <= sorted[a].lastIP(); b++ {
+
+			region := intersect(sorted[a], sorted[b])
+			report = append(report, Overlap{
+				First:  sorted[a],
+				Second: sorted[b],
+				Region: region,
+				Size:   region.size(),
+			})
+
+		}
+
+	}
+
+	sort.SliceStable(report, func(a, b int) bool {
+		return report[a].Size > report[b].Size
+	})
+
+	return report
+
+}
@@ -0,0 +1,47 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestOverlapReport finds every overlapping pair in a list of CIDR ranges
+// Success Metric: Every conflicting pair is reported with its overlap, largest overlap first
+func TestOverlapReport(t *testing.T) {
+
+	cidrs := parseAll(t, "10.0.0.0/24", "10.0.0.7", "10.1.0.0/16", "10.0.0.128/25", "10.1.0.0/16", "192.168.0.0/16")
+
+	report := OverlapReport(cidrs)
+	if assert.Len(t, report, 3) {
+
+		assert.Equal(t, "10.1.0.0/16", report[0].First.ToString())
+		assert.Equal(t, "10.1.0.0/16", report[0].Second.ToString())
+		assert.Equal(t, "10.1.0.0/16", report[0].Region.ToString())
+		assert.Equal(t, uint64(65536), report[0].Size)
+
+		assert.Equal(t, "10.0.0.0/24", report[1].First.ToString())
+		assert.Equal(t, "10.0.0.128/25", report[1].Second.ToString())
+		assert.Equal(t, "10.0.0.128/25", report[1].Region.ToString())
+		assert.Equal(t, uint64(128), report[1].Size)
+
+		assert.Equal(t, "10.0.0.0/24", report[2].First.ToString())
+		assert.Equal(t, "10.0.0.7/32", report[2].Second.ToString())
+		assert.Equal(t, uint64(1), report[2].Size)
+
+	}
+
+}
+
+// TestOverlapReportNoConflicts checks a list of adjacent but disjoint CIDR ranges
+// Success Metric: The report is empty
+func TestOverlapReportNoConflicts(t *testing.T) {
+
+	cidrs := parseAll(t, "10.0.0.0/25", "10.0.0.128/25", "10.0.1.0/24")
+
+	assert.Empty(t, OverlapReport(cidrs))
+
+}
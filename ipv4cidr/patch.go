@@ -0,0 +1,106 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/utils"
+)
+
+// PatchOperation models a single change to a list of CIDR ranges
+// @field Op string: The operation, either "add" or "remove"
+// @field CIDR string: The CIDR range to add or remove, in format a.b.c.d/e or a.b.c.d
+type PatchOperation struct {
+	Op   string `json:"op"`
+	CIDR string `json:"cidr"`
+}
+
+// Patch models an ordered list of changes to a list of CIDR ranges, so incremental updates can be distributed instead of full snapshots
+type Patch []PatchOperation
+
+// ApplyPatch applies a patch to a list of CIDR ranges
+// The patch is applied atomically: if any operation is invalid or conflicts with the list, no change is made and an error is returned.
+// @input cidrs []*IPv4CIDR: The list of CIDR ranges to patch. It is not modified
+// @input patch Patch: The operations to apply, in order
+// @returns []*IPv4CIDR: The patched list. Existing CIDR ranges keep their order, and added ones are appended
+// @returns error: If an operation is invalid, adds a CIDR range already in the list, or removes one that is not in the list, the appropriate error is returned
+func ApplyPatch(cidrs []*IPv4CIDR, patch Patch) ([]*IPv4CIDR, error) {
+
+	// Removed entries are set to nil and dropped at the end, so that each operation is a map lookup instead of a scan of the list
+	result := make([]*IPv4CIDR, 0, len(cidrs)+len(patch))
+	positions := make(map[cidrKey][]int, len(cidrs))
+	for _, cidr := range cidrs {
+		if cidr != nil {
+			key := cidrKey{ip: cidr.ip, mask: cidr.mask}
+			positions[key] = append(positions[key], len(result))
+			result = append(result, cidr)
+		}
+	}
+
+	removed := 0
+	for _, operation := range patch {
+
+		cidr, err := NewIPv4CIDR(operation.CIDR, false)
+		if err != nil {
+			return nil, err
+		}
+
+		// Positions of the CIDR range in the list, first occurrence first
+		key := cidrKey{ip: cidr.ip, mask: cidr.mask}
+		found := positions[key]
+
+		switch operation.Op {
+		case consts.PatchAdd:
+			if len(found) > 0 {
+				return nil, utils.NewError(consts.PatchConflictCode, consts.PatchAddConflictError)
+			}
+			positions[key] = append(found, len(result))
+			result = append(result, cidr)
+		case consts.PatchRemove:
+			if len(found) == 0 {
+				return nil, utils.NewError(consts.PatchConflictCode, consts.PatchRemoveConflictError)
+			}
+			result[found[0]] = nil
+			positions[key] = found[1:]
+			removed++
+		default:
+			return nil, utils.NewError(consts.InvalidPatchCode, consts.InvalidPatchOperationError)
+		}
+
+	}
+
+	patched := make([]*IPv4CIDR, 0, len(result)-removed)
+	for _, cidr := range result {
+		if cidr != nil {
+			patched = append(patched, cidr)
+		}
+	}
+
+	return patched, nil
+
+}
+
+// ApplyPatch applies a patch to the set, atomically: if any operation is invalid or conflicts with the set, the set is left unchanged
+// @input patch Patch: The operations to apply, in order
+// @returns error: If an operation is invalid, adds a CIDR range already in the set, or removes one that is not in the set, the appropriate error is returned
+func (s *Set) ApplyPatch(patch Patch) error {
+
+	patched, err := ApplyPatch(*s, patch)
+	if err != nil {
+		return err
+	}
+
+	*s = patched
+
+	return nil
+
+}
+
+// cidrKey identifies a CIDR range by value, to index lists of CIDR ranges in maps
+// @field ip uint32: The IP of the CIDR range in integer representation
+// @field mask uint8: The mask of the CIDR range
+type cidrKey struct {
+	ip   uint32
+	mask uint8
+}
@@ -0,0 +1,124 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"testing"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestApplyPatch applies add and remove operations to a list of CIDR ranges
+// Success Metric: The patched list is returned and the original list is left unchanged
+func TestApplyPatch(t *testing.T) {
+
+	cidrs := parseAll(t, "10.0.0.0/24", "10.0.1.0/24", "10.0.2.0/24")
+	patch := Patch{
+		{Op: consts.PatchRemove, CIDR: "10.0.1.0/24"},
+		{Op: consts.PatchAdd, CIDR: "192.168.0.0/16"},
+		{Op: consts.PatchAdd, CIDR: "10.0.1.0/25"},
+	}
+
+	patched, err := ApplyPatch(cidrs, patch)
+	assert.Nil(t, err, "All operations are valid, the patch should be applied.")
+	assert.Equal(t, []string{"10.0.0.0/24", "10.0.2.0/24", "192.168.0.0/16", "10.0.1.0/25"}, toStrings(patched))
+	assert.Equal(t, []string{"10.0.0.0/24", "10.0.1.0/24", "10.0.2.0/24"}, toStrings(cidrs), "The original list should not be modified.")
+
+}
+
+// TestSetApplyPatch applies patches to a Set
+// Success Metric: A valid patch updates the set, and an invalid one leaves it unchanged
+func TestSetApplyPatch(t *testing.T) {
+
+	set := Set(parseAll(t, "10.0.0.0/24", "10.0.1.0/24"))
+
+	err := set.ApplyPatch(Patch{
+		{Op: consts.PatchRemove, CIDR: "10.0.1.0/24"},
+		{Op: consts.PatchAdd, CIDR: "192.168.0.0/16"},
+	})
+	assert.Nil(t, err, "All operations are valid, the patch should be applied.")
+	assert.Equal(t, []string{"10.0.0.0/24", "192.168.0.0/16"}, toStrings(set))
+
+	err = set.ApplyPatch(Patch{
+		{Op: consts.PatchAdd, CIDR: "172.16.0.0/12"},
+		{Op: consts.PatchRemove, CIDR: "10.0.1.0/24"},
+	})
+	if assert.Error(t, err, "10.0.1.0/24 is no longer in the set. An error should be thrown.") {
+
+		assert.Equal(t, consts.PatchRemoveConflictError, err.Error(), "Error thrown should be: \"%s\"", consts.PatchRemoveConflictError)
+
+	}
+	assert.Equal(t, []string{"10.0.0.0/24", "192.168.0.0/16"}, toStrings(set), "A failed patch should not modify the set.")
+
+}
+
+// TestApplyPatchConflicts applies patches that conflict with the list or are invalid
+// Success Metric: Throw the appropriate error for each patch
+func TestApplyPatchConflicts(t *testing.T) {
+
+	cidrs := parseAll(t, "10.0.0.0/24")
+
+	testPatches := map[string]Patch{
+		consts.PatchAddConflictError:      {{Op: consts.PatchAdd, CIDR: "10.0.0.0/24"}},
+		consts.PatchRemoveConflictError:   {{Op: consts.PatchRemove, CIDR: "10.0.0.0/25"}},
+		consts.InvalidPatchOperationError: {{Op: "replace", CIDR: "10.0.0.0/24"}},
+		consts.InvalidIPv4CIDRError:       {{Op: consts.PatchAdd, CIDR: "10.0.0.0/33"}},
+	}
+
+	for expectedError, patch := range testPatches {
+
+		_, err := ApplyPatch(cidrs, patch)
+		if assert.Error(t, err, "The patch is invalid. An error should be thrown.") {
+
+			assert.Equal(t, expectedError, err.Error(), "Error thrown should be: \"%s\"", expectedError)
+
+		}
+
+	}
+
+}
+
+// TestApplyPatchReAddAndDuplicates removes and re-adds a CIDR range, and removes a CIDR range listed twice
+// Success Metric: A removed CIDR range can be added again, and duplicates are removed one at a time, first occurrence first
+func TestApplyPatchReAddAndDuplicates(t *testing.T) {
+
+	cidrs := parseAll(t, "10.0.0.0/24", "10.0.1.0/24", "10.0.0.0/24")
+	patch := Patch{
+		{Op: consts.PatchRemove, CIDR: "10.0.0.0/24"},
+		{Op: consts.PatchRemove, CIDR: "10.0.1.0/24"},
+		{Op: consts.PatchAdd, CIDR: "10.0.1.0/24"},
+	}
+
+	patched, err := ApplyPatch(cidrs, patch)
+	assert.Nil(t, err, "All operations are valid, the patch should be applied.")
+	assert.Equal(t, []string{"10.0.0.0/24", "10.0.1.0/24"}, toStrings(patched))
+
+	_, err = ApplyPatch(cidrs, Patch{{Op: consts.PatchRemove, CIDR: "10.0.0.0/24"}, {Op: consts.PatchRemove, CIDR: "10.0.0.0/24"}, {Op: consts.PatchRemove, CIDR: "10.0.0.0/24"}})
+	if assert.Error(t, err, "10.0.0.0/24 is only listed twice. An error should be thrown.") {
+
+		assert.Equal(t, consts.PatchRemoveConflictError, err.Error(), "Error thrown should be: \"%s\"", consts.PatchRemoveConflictError)
+
+	}
+
+}
+
+// BenchmarkApplyPatch applies a patch of many operations to a large list of CIDR ranges
+func BenchmarkApplyPatch(b *testing.B) {
+
+	parent := mustParse("10.0.0.0/12")
+	cidrs, _ := parent.SplitToMask(24)
+
+	patch := make(Patch, 0, len(cidrs)/2)
+	for n := 0; n < len(cidrs); n += 2 {
+		patch = append(patch, PatchOperation{Op: consts.PatchRemove, CIDR: cidrs[n].ToString()})
+	}
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		ApplyPatch(cidrs, patch)
+	}
+
+}
@@ -0,0 +1,93 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package planlint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr"
+)
+
+// Hash computes a canonical hash of the plan, so that CI can cheaply detect whether a change actually alters the address plan
+// The hash does not depend on the file format (YAML or JSON), formatting, or the order of the blocks within each list,
+// and valid CIDR ranges are compared in their canonical form (e.g. 10.0.0.1 and 10.0.0.1/32 are the same block)
+// Names, delegations and the policy are part of the plan, so changing them changes the hash
+// @returns string: The SHA-256 hash of the canonical form of the plan, in hexadecimal
+func (p *Plan) Hash() string {
+
+	canonical := Plan{
+		Supernets:    canonicalBlocks(p.Supernets),
+		Subnets:      canonicalBlocks(p.Subnets),
+		Reservations: canonicalBlocks(p.Reservations),
+		Policy:       p.Policy,
+	}
+
+	// Marshalling a struct is deterministic: fields are written in declaration order
+	data, _ := json.Marshal(canonical)
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+
+}
+
+// canonicalBlocks returns a copy of a list of blocks with canonical CIDR ranges, sorted by CIDR range then by name
+// Invalid and misaligned CIDR ranges are kept as written, since they are findings of their own
+// @input blocks []Block: The blocks
+// @returns []Block: The canonical list of blocks, never nil
+func canonicalBlocks(blocks []Block) []Block {
+
+	parsed := make([]parsedBlock, 0, len(blocks))
+	for _, block := range blocks {
+
+		cidr, err := ipv4cidr.NewIPv4CIDR(block.CIDR, false)
+		if err == nil {
+			block.CIDR = cidr.ToString()
+		}
+		parsed = append(parsed, parsedBlock{block: block, cidr: cidr})
+
+	}
+
+	// Valid CIDR ranges sort by IP then mask, before the invalid ones, which sort as written
+	sort.SliceStable(parsed, func(a, b int) bool {
+
+		blockA, blockB := parsed[a], parsed[b]
+		switch {
+		case (blockA.cidr == nil) != (blockB.cidr == nil):
+			return blockA.cidr != nil
+		case blockA.cidr != nil && blockA.cidr.Compare(blockB.cidr) != 0:
+			return blockA.cidr.Compare(blockB.cidr) < 0
+		case blockA.block.CIDR != blockB.block.CIDR:
+			return blockA.block.CIDR < blockB.block.CIDR
+		case blockA.block.Name != blockB.block.Name:
+			return blockA.block.Name < blockB.block.Name
+		}
+		return blockA.block.Delegation < blockB.block.Delegation
+
+	})
+
+	canonical := make([]Block, 0, len(parsed))
+	for _, block := range parsed {
+		canonical = append(canonical, block.block)
+	}
+
+	return canonical
+
+}
+
+// canonicalCIDR returns the canonical form of a CIDR range, so that equivalent spellings compare equal (e.g. 10.0.0.1 and 10.0.0.1/32)
+// @input cidr string: The CIDR range as written in the plan
+// @returns string: The canonical CIDR range, or the CIDR range as written if it is invalid or misaligned
+func canonicalCIDR(cidr string) string {
+
+	parsed, err := ipv4cidr.NewIPv4CIDR(cidr, false)
+	if err != nil {
+		return cidr
+	}
+
+	return parsed.ToString()
+
+}
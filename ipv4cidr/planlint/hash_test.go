@@ -0,0 +1,58 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package planlint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPlanHash hashes equivalent and different plans
+// Success Metric: Reformatting or reordering the plan keeps the hash, and changing any block or the policy changes it
+func TestPlanHash(t *testing.T) {
+
+	plan, err := ParsePlan([]byte(validPlan))
+	assert.Nil(t, err, "The plan is valid YAML, it should be parsed.")
+	hash := plan.Hash()
+	assert.Len(t, hash, 64, "The hash should be a hexadecimal SHA-256")
+
+	// The same plan, in JSON, with the keys and subnets in another order
+	equivalent, err := ParsePlan([]byte(`{
+		"policy": {"private": true, "maxPrefixLength": 28, "minPrefixLength": 20},
+		"reservations": [{"name": "future", "cidr": "10.0.128.0/17"}],
+		"subnets": [
+			{"name": "workloads", "cidr": "10.0.1.0/24"},
+			{"name": "firewall", "cidr": "10.0.0.0/26", "delegation": "AzureFirewallSubnet"}
+		],
+		"supernets": [{"name": "hub", "cidr": "10.0.0.0/16"}]
+	}`))
+	assert.Nil(t, err)
+	assert.Equal(t, hash, equivalent.Hash(), "Formatting and order should not change the hash")
+
+	for name, change := range map[string]func(p *Plan){
+		"subnet CIDR": func(p *Plan) { p.Subnets[1].CIDR = "10.0.2.0/24" },
+		"subnet name": func(p *Plan) { p.Subnets[1].Name = "apps" },
+		"delegation":  func(p *Plan) { p.Subnets[0].Delegation = "" },
+		"new subnet":  func(p *Plan) { p.Subnets = append(p.Subnets, Block{Name: "db", CIDR: "10.0.3.0/24"}) },
+		"moved block": func(p *Plan) { p.Reservations, p.Subnets = p.Subnets, p.Reservations },
+		"policy":      func(p *Plan) { p.Policy.Private = false },
+		"misaligned":  func(p *Plan) { p.Subnets[1].CIDR = "10.0.1.1/24" },
+	} {
+
+		changed, _ := ParsePlan([]byte(validPlan))
+		change(changed)
+		assert.NotEqual(t, hash, changed.Hash(), "Changing the %s should change the hash", name)
+
+	}
+
+	single, _ := ParsePlan([]byte("subnets: [{name: host, cidr: 10.0.0.1}]"))
+	singleWithMask, _ := ParsePlan([]byte("subnets: [{name: host, cidr: 10.0.0.1/32}]"))
+	assert.Equal(t, single.Hash(), singleWithMask.Hash(), "10.0.0.1 and 10.0.0.1/32 are the same block")
+
+	invalid, _ := ParsePlan([]byte("subnets: [{name: a, cidr: not-a-cidr}, {name: b, cidr: 10.0.0.0/24}]"))
+	reordered, _ := ParsePlan([]byte("subnets: [{name: b, cidr: 10.0.0.0/24}, {name: a, cidr: not-a-cidr}]"))
+	assert.Equal(t, invalid.Hash(), reordered.Hash(), "Invalid blocks should be sorted deterministically too")
+
+}
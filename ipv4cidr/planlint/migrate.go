@@ -0,0 +1,126 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package planlint
+
+import (
+	"fmt"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/utils"
+)
+
+// Conversion describes how a subnet of a plan is re-expressed at another prefix length
+// @field From Block: The subnet of the original plan
+// @field To []Block: The subnets replacing it in the migrated plan
+// @field Lossy bool: Whether the subnets replacing it cover more than the original subnet, i.e. the conversion cannot be reverted
+// @field Reason string: Human-readable description of why the conversion is lossy, empty if it is not
+type Conversion struct {
+	From   Block   `json:"from" yaml:"from"`
+	To     []Block `json:"to" yaml:"to"`
+	Lossy  bool    `json:"lossy" yaml:"lossy"`
+	Reason string  `json:"reason,omitempty" yaml:"reason,omitempty"`
+}
+
+// Migration is an address plan re-expressed at another prefix length
+// @field Plan *Plan: The migrated plan
+// @field Conversions []Conversion: How each subnet of the original plan was converted, in the order of the plan
+type Migration struct {
+	Plan        *Plan        `json:"plan" yaml:"plan"`
+	Conversions []Conversion `json:"conversions" yaml:"conversions"`
+}
+
+// Migrate re-expresses the subnets of an address plan at another prefix length, e.g. to convert a plan of /20s into /22s, without modifying the plan
+// Subnets larger than the prefix length are split into all of their children, named after the subnet with the index of the child appended, e.g. "apps-0".
+// Subnets smaller than the prefix length are widened and flagged as lossy. Every subnet of the migrated plan is merged into the first one with the same CIDR range, if any.
+// Subnets with an invalid CIDR range are kept as is and flagged as lossy. Supernets, reservations and the policy are kept as is
+// @input plan *Plan: The plan to migrate
+// @input mask uint8: The prefix length of the subnets of the migrated plan
+// @returns *Migration: The migrated plan and the conversion of each subnet
+// @returns error: If the prefix length is larger than 32, or more than consts.MaxMigrationBits longer than the mask of a subnet, an error is returned
+func Migrate(plan *Plan, mask uint8) (*Migration, error) {
+
+	if mask > consts.MaxBits {
+		return nil, utils.NewError(consts.InvalidMaskCode, consts.InvalidMaskError)
+	}
+
+	// One block is allocated per child of a split subnet, so the number of children is bounded
+	discarded := make([]Finding, 0)
+	for _, subnet := range parseBlocks(plan.Subnets, &discarded) {
+		if mask > subnet.cidr.GetMask() && mask-subnet.cidr.GetMask() > consts.MaxMigrationBits {
+			return nil, utils.NewError(consts.OutOfRangeCode, consts.MigrationTooLargeError)
+		}
+	}
+
+	migrated := *plan
+	migrated.Subnets = make([]Block, 0, len(plan.Subnets))
+
+	migration := &Migration{
+		Plan:        &migrated,
+		Conversions: make([]Conversion, 0, len(plan.Subnets)),
+	}
+
+	// First block of the migrated plan with each CIDR range, to merge the later blocks with the same CIDR range into it
+	emitted := make(map[string]Block)
+
+	for _, subnet := range plan.Subnets {
+
+		conversion := Conversion{From: subnet, To: make([]Block, 0)}
+
+		// Misaligned subnets are converted as if standardized, Lint reports them
+		parsed := parseBlocks([]Block{subnet}, &discarded)
+
+		switch {
+		case len(parsed) == 0:
+			conversion.To = append(conversion.To, subnet)
+			conversion.Lossy = true
+			conversion.Reason = fmt.Sprintf("%s is not a valid CIDR range, it was kept as is", subnet.CIDR)
+			migrated.Subnets = append(migrated.Subnets, subnet)
+
+		case parsed[0].cidr.GetMask() > mask:
+			cidr := parsed[0].cidr
+			wider, _ := cidr.Widen(cidr.GetMask() - mask)
+			added := utils.GetCIDRRangeLength64(mask) - utils.GetCIDRRangeLength64(cidr.GetMask())
+
+			conversion.Lossy = true
+			if existing, ok := emitted[wider.ToString()]; ok {
+				conversion.To = append(conversion.To, existing)
+				conversion.Reason = fmt.Sprintf("%s was widened to %s, adding %d addresses, and merged with subnet %s", subnet.CIDR, wider.ToString(), added, blockName(existing))
+			} else {
+				block := Block{Name: subnet.Name, CIDR: wider.ToString(), Delegation: subnet.Delegation}
+				emitted[block.CIDR] = block
+				conversion.To = append(conversion.To, block)
+				conversion.Reason = fmt.Sprintf("%s was widened to %s, adding %d addresses", subnet.CIDR, wider.ToString(), added)
+				migrated.Subnets = append(migrated.Subnets, block)
+			}
+
+		default:
+			cidr := parsed[0].cidr
+			count := uint64(1) << (mask - cidr.GetMask())
+			for n := uint64(0); n < count; n++ {
+
+				child, _ := cidr.NthSubnet(mask, n)
+				block := Block{Name: subnet.Name, CIDR: child.ToString(), Delegation: subnet.Delegation}
+				if count > 1 && subnet.Name != "" {
+					block.Name = fmt.Sprintf("%s-%d", subnet.Name, n)
+				}
+
+				if existing, ok := emitted[block.CIDR]; ok {
+					conversion.To = append(conversion.To, existing)
+					continue
+				}
+
+				emitted[block.CIDR] = block
+				conversion.To = append(conversion.To, block)
+				migrated.Subnets = append(migrated.Subnets, block)
+
+			}
+		}
+
+		migration.Conversions = append(migration.Conversions, conversion)
+
+	}
+
+	return migration, nil
+
+}
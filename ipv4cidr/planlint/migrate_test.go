@@ -0,0 +1,152 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package planlint
+
+import (
+	"testing"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMigrate re-expresses a plan with subnets of several sizes at /24
+// Success Metric: Larger subnets are split, smaller ones are widened, merged and flagged as lossy, and the plan is not modified
+func TestMigrate(t *testing.T) {
+
+	plan := &Plan{
+		Supernets: []Block{{Name: "hub", CIDR: "10.0.0.0/16"}},
+		Subnets: []Block{
+			{Name: "apps", CIDR: "10.0.4.0/23"},
+			{Name: "data", CIDR: "10.0.8.0/24"},
+			{Name: "firewall", CIDR: "10.0.9.0/26", Delegation: "AzureFirewallSubnet"},
+			{Name: "bastion", CIDR: "10.0.9.64/26"},
+			{Name: "broken", CIDR: "10.0.10.0/33"},
+		},
+	}
+
+	migration, err := Migrate(plan, 24)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "10.0.4.0/23", plan.Subnets[0].CIDR, "The original plan should not be modified")
+	assert.Equal(t, plan.Supernets, migration.Plan.Supernets)
+	assert.Equal(t, []Block{
+		{Name: "apps-0", CIDR: "10.0.4.0/24"},
+		{Name: "apps-1", CIDR: "10.0.5.0/24"},
+		{Name: "data", CIDR: "10.0.8.0/24"},
+		{Name: "firewall", CIDR: "10.0.9.0/24", Delegation: "AzureFirewallSubnet"},
+		{Name: "broken", CIDR: "10.0.10.0/33"},
+	}, migration.Plan.Subnets)
+
+	if assert.Len(t, migration.Conversions, 5) {
+
+		assert.False(t, migration.Conversions[0].Lossy, "Splitting is lossless")
+		assert.Len(t, migration.Conversions[0].To, 2)
+
+		assert.False(t, migration.Conversions[1].Lossy, "Subnets of the right size are kept")
+		assert.Equal(t, []Block{plan.Subnets[1]}, migration.Conversions[1].To)
+
+		assert.True(t, migration.Conversions[2].Lossy, "Widening is lossy")
+		assert.Equal(t, "10.0.9.0/26 was widened to 10.0.9.0/24, adding 192 addresses", migration.Conversions[2].Reason)
+
+		assert.True(t, migration.Conversions[3].Lossy)
+		assert.Equal(t, []Block{migration.Plan.Subnets[3]}, migration.Conversions[3].To, "Subnets widened into the same range are merged")
+		assert.Equal(t, "10.0.9.64/26 was widened to 10.0.9.0/24, adding 192 addresses, and merged with subnet firewall", migration.Conversions[3].Reason)
+
+		assert.True(t, migration.Conversions[4].Lossy, "Invalid subnets cannot be converted")
+		assert.Equal(t, []Block{plan.Subnets[4]}, migration.Conversions[4].To)
+
+	}
+
+}
+
+// TestMigrateMergesIntoExistingBlock migrates a plan where a subnet is widened into the CIDR range of a subnet that is kept
+// Success Metric: The widened subnet is merged into the kept one, so the migrated plan has no new overlap
+func TestMigrateMergesIntoExistingBlock(t *testing.T) {
+
+	plan := &Plan{
+		Supernets: []Block{{Name: "hub", CIDR: "10.0.0.0/16"}},
+		Subnets: []Block{
+			{Name: "a", CIDR: "10.0.0.0/22"},
+			{Name: "b", CIDR: "10.0.1.0/24"},
+		},
+	}
+
+	migration, err := Migrate(plan, 22)
+	assert.Nil(t, err)
+
+	assert.Equal(t, []Block{{Name: "a", CIDR: "10.0.0.0/22"}}, migration.Plan.Subnets)
+
+	if assert.Len(t, migration.Conversions, 2) {
+
+		assert.False(t, migration.Conversions[0].Lossy)
+		assert.True(t, migration.Conversions[1].Lossy)
+		assert.Equal(t, []Block{{Name: "a", CIDR: "10.0.0.0/22"}}, migration.Conversions[1].To)
+		assert.Equal(t, "10.0.1.0/24 was widened to 10.0.0.0/22, adding 768 addresses, and merged with subnet a", migration.Conversions[1].Reason)
+
+	}
+
+	for _, finding := range Lint(migration.Plan) {
+		assert.NotEqual(t, OverlapRule, finding.Rule, "The migrated plan should not have overlapping subnets")
+	}
+
+}
+
+// TestMigrateMergesSplitIntoWidened migrates a plan where a subnet is widened into the CIDR range of a later subnet that is kept
+// Success Metric: The kept subnet is merged into the widened one, so each CIDR range appears once in the migrated plan
+func TestMigrateMergesSplitIntoWidened(t *testing.T) {
+
+	plan := &Plan{
+		Supernets: []Block{{Name: "hub", CIDR: "10.0.0.0/16"}},
+		Subnets: []Block{
+			{Name: "b", CIDR: "10.0.1.0/24"},
+			{Name: "a", CIDR: "10.0.0.0/22"},
+		},
+	}
+
+	migration, err := Migrate(plan, 22)
+	assert.Nil(t, err)
+
+	assert.Equal(t, []Block{{Name: "b", CIDR: "10.0.0.0/22"}}, migration.Plan.Subnets)
+	if assert.Len(t, migration.Conversions, 2) {
+		assert.Equal(t, []Block{{Name: "b", CIDR: "10.0.0.0/22"}}, migration.Conversions[1].To)
+	}
+
+}
+
+// TestMigrateTooManySubnets migrates a plan where a subnet would be split into more than 65536 subnets
+// Success Metric: An error is thrown instead of allocating every subnet
+func TestMigrateTooManySubnets(t *testing.T) {
+
+	plan := &Plan{Subnets: []Block{{Name: "apps", CIDR: "10.0.0.0/8"}}}
+
+	_, err := Migrate(plan, 32)
+	if assert.Error(t, err, "Splitting a /8 into /32s is too large. An error should be thrown.") {
+
+		assert.Equal(t, consts.MigrationTooLargeError, err.Error(), "Error thrown should be: \"%s\"", consts.MigrationTooLargeError)
+		assert.Equal(t, consts.OutOfRangeCode, ipv4cidr.GetErrorCode(err))
+
+	}
+
+	_, err = Migrate(plan, 24)
+	assert.Nil(t, err, "Splitting a /8 into /24s is within the limit")
+
+}
+
+// TestMigrateInvalidMask migrates a plan to a prefix length larger than 32
+// Success Metric: An error is thrown
+func TestMigrateInvalidMask(t *testing.T) {
+
+	plan, err := ParsePlan([]byte(validPlan))
+	assert.Nil(t, err, "The plan is valid YAML, it should be parsed.")
+
+	_, err = Migrate(plan, 33)
+	if assert.Error(t, err, "/33 is not a valid prefix length. An error should be thrown.") {
+
+		assert.Equal(t, consts.InvalidMaskError, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidMaskError)
+
+	}
+
+}
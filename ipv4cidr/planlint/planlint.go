@@ -0,0 +1,258 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package planlint
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+	"gopkg.in/yaml.v3"
+)
+
+// This set of constants defines the names of the plan-level checks, in addition to the validation rules of the ipv4cidr package
+const (
+	InvalidRule     string = "invalid"
+	AlignmentRule   string = "alignment"
+	ContainmentRule string = "containment"
+	OverlapRule     string = "overlap"
+	ReservationRule string = "reservation"
+)
+
+// Block models a named CIDR range of an address plan
+// @field Name string: The name of the block, used to identify it in findings
+// @field CIDR string: The CIDR range in format a.b.c.d/e
+// @field Delegation string: Optional Azure subnet delegation or dedicated subnet name, checked with ipv4cidr.AzureDelegation
+type Block struct {
+	Name       string `json:"name" yaml:"name"`
+	CIDR       string `json:"cidr" yaml:"cidr"`
+	Delegation string `json:"delegation,omitempty" yaml:"delegation,omitempty"`
+}
+
+// Policy models the rules every subnet of an address plan must satisfy. Zero values disable the corresponding rule
+// @field MinPrefixLength uint8: The smallest allowed mask of a subnet
+// @field MaxPrefixLength uint8: The largest allowed mask of a subnet
+// @field Private bool: Require subnets to be within the private (RFC 1918) address space
+// @field AllowShared bool: With Private, also allow the shared (RFC 6598) address space
+type Policy struct {
+	MinPrefixLength uint8 `json:"minPrefixLength,omitempty" yaml:"minPrefixLength,omitempty"`
+	MaxPrefixLength uint8 `json:"maxPrefixLength,omitempty" yaml:"maxPrefixLength,omitempty"`
+	Private         bool  `json:"private,omitempty" yaml:"private,omitempty"`
+	AllowShared     bool  `json:"allowShared,omitempty" yaml:"allowShared,omitempty"`
+}
+
+// Plan models a declarative address plan
+// @field Supernets []Block: The address spaces the subnets are allocated from
+// @field Subnets []Block: The allocated subnets
+// @field Reservations []Block: CIDR ranges that no subnet may overlap
+// @field Policy Policy: The rules every subnet must satisfy
+type Plan struct {
+	Supernets    []Block `json:"supernets" yaml:"supernets"`
+	Subnets      []Block `json:"subnets" yaml:"subnets"`
+	Reservations []Block `json:"reservations,omitempty" yaml:"reservations,omitempty"`
+	Policy       Policy  `json:"policy" yaml:"policy"`
+}
+
+// Finding describes a problem found in an address plan, in a machine-readable form for CI gates
+// @field Rule string: Name of the check or validation rule that failed
+// @field Block string: Name of the block the finding is about, or its CIDR range if it has no name
+// @field CIDR string: The CIDR range of the block, as written in the plan
+// @field Message string: Human-readable description of the problem
+type Finding struct {
+	Rule    string `json:"rule" yaml:"rule"`
+	Block   string `json:"block" yaml:"block"`
+	CIDR    string `json:"cidr" yaml:"cidr"`
+	Message string `json:"message" yaml:"message"`
+}
+
+// ParsePlan parses an address plan in YAML or JSON format (JSON being a subset of YAML)
+// @input data []byte: The content of the plan
+// @returns *Plan: The parsed plan
+// @returns error: If the content is not a valid plan, an error is returned
+func ParsePlan(data []byte) (*Plan, error) {
+
+	plan := &Plan{}
+	if err := yaml.Unmarshal(data, plan); err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+
+}
+
+// LoadPlan reads and parses an address plan file in YAML or JSON format
+// @input path string: The path to the plan file
+// @returns *Plan: The parsed plan
+// @returns error: If the file cannot be read or is not a valid plan, an error is returned
+func LoadPlan(path string) (*Plan, error) {
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParsePlan(data)
+
+}
+
+// parsedBlock is a block of the plan along with its parsed CIDR range
+// @field block Block: The block as written in the plan
+// @field cidr *ipv4cidr.IPv4CIDR: The parsed, standardized CIDR range
+type parsedBlock struct {
+	block Block
+	cidr  *ipv4cidr.IPv4CIDR
+}
+
+// Lint validates an address plan and returns every problem found
+// Blocks with an invalid CIDR range are reported and skipped by the other checks, and blocks that are not aligned to their size are reported and checked as if standardized
+// @input plan *Plan: The plan to validate
+// @returns []Finding: The findings, grouped by check: invalid and misaligned blocks, then containment, overlaps between subnets, overlaps with reservations, and policy violations. Empty if the plan is valid
+func Lint(plan *Plan) []Finding {
+
+	findings := make([]Finding, 0)
+
+	supernets := parseBlocks(plan.Supernets, &findings)
+	subnets := parseBlocks(plan.Subnets, &findings)
+	reservations := parseBlocks(plan.Reservations, &findings)
+
+	// Every subnet must be within one of the supernets
+	within := ipv4cidr.Within(blockCIDRs(supernets)...)
+	for _, subnet := range subnets {
+		if violation := within.Check(subnet.cidr); violation != nil {
+			findings = append(findings, newFinding(ContainmentRule, subnet.block, violation.Message))
+		}
+	}
+
+	// Subnets must not overlap each other
+	byCIDR := make(map[*ipv4cidr.IPv4CIDR]Block, len(subnets))
+	for _, subnet := range subnets {
+		byCIDR[subnet.cidr] = subnet.block
+	}
+	for _, overlap := range ipv4cidr.OverlapReport(blockCIDRs(subnets)) {
+		first, second := byCIDR[overlap.First], byCIDR[overlap.Second]
+		message := fmt.Sprintf("Subnet %s (%s) overlaps subnet %s (%s) in %s", blockName(second), second.CIDR, blockName(first), first.CIDR, overlap.Region.ToString())
+		findings = append(findings, newFinding(OverlapRule, second, message))
+	}
+
+	// Subnets must not overlap the reservations
+	for _, reservation := range reservations {
+		notOverlapping := ipv4cidr.NotOverlapping(reservation.cidr)
+		for _, subnet := range subnets {
+			if notOverlapping.Check(subnet.cidr) != nil {
+				message := fmt.Sprintf("Subnet %s (%s) overlaps reservation %s (%s)", blockName(subnet.block), subnet.block.CIDR, blockName(reservation.block), reservation.block.CIDR)
+				findings = append(findings, newFinding(ReservationRule, subnet.block, message))
+			}
+		}
+	}
+
+	// Subnets must satisfy the policy, and be large enough for their delegation
+	validator := ipv4cidr.NewValidator(policyRules(plan.Policy)...)
+	for _, subnet := range subnets {
+		violations := validator.Validate(subnet.cidr)
+		if subnet.block.Delegation != "" {
+			violations = append(violations, ipv4cidr.NewValidator(ipv4cidr.AzureDelegation(subnet.block.Delegation)).Validate(subnet.cidr)...)
+		}
+		for _, violation := range violations {
+			findings = append(findings, newFinding(violation.Rule, subnet.block, violation.Message))
+		}
+	}
+
+	return findings
+
+}
+
+// parseBlocks parses the CIDR ranges of a list of blocks, reporting invalid and misaligned ones
+// @input blocks []Block: The blocks to parse
+// @input findings *[]Finding: The findings to append to
+// @returns []parsedBlock: The blocks with a valid CIDR range
+func parseBlocks(blocks []Block, findings *[]Finding) []parsedBlock {
+
+	parsed := make([]parsedBlock, 0, len(blocks))
+	for _, block := range blocks {
+
+		// Blocks not aligned to their size are reported, then checked as if standardized
+		cidr, err := ipv4cidr.NewIPv4CIDR(block.CIDR, false)
+		if ipv4cidr.GetErrorCode(err) == consts.NotStandardizedCode {
+			cidr, err = ipv4cidr.NewIPv4CIDR(block.CIDR, true)
+			message := fmt.Sprintf("%s is not aligned to its size, it should be %s", block.CIDR, cidr.ToString())
+			*findings = append(*findings, newFinding(AlignmentRule, block, message))
+		}
+		if err != nil {
+			*findings = append(*findings, newFinding(InvalidRule, block, err.Error()))
+			continue
+		}
+
+		parsed = append(parsed, parsedBlock{block: block, cidr: cidr})
+
+	}
+
+	return parsed
+
+}
+
+// blockCIDRs returns the CIDR ranges of a list of parsed blocks
+// @input blocks []parsedBlock: The parsed blocks
+// @returns []*ipv4cidr.IPv4CIDR: The CIDR ranges, in the order of the blocks
+func blockCIDRs(blocks []parsedBlock) []*ipv4cidr.IPv4CIDR {
+
+	cidrs := make([]*ipv4cidr.IPv4CIDR, 0, len(blocks))
+	for _, block := range blocks {
+		cidrs = append(cidrs, block.cidr)
+	}
+
+	return cidrs
+
+}
+
+// policyRules converts the policy of a plan into validation rules
+// @input policy Policy: The policy of the plan
+// @returns []ipv4cidr.Rule: The enabled rules
+func policyRules(policy Policy) []ipv4cidr.Rule {
+
+	rules := make([]ipv4cidr.Rule, 0)
+	if policy.MinPrefixLength > 0 {
+		rules = append(rules, ipv4cidr.MinPrefixLength(policy.MinPrefixLength))
+	}
+	if policy.MaxPrefixLength > 0 {
+		rules = append(rules, ipv4cidr.MaxPrefixLength(policy.MaxPrefixLength))
+	}
+	if policy.Private && policy.AllowShared {
+		rules = append(rules, ipv4cidr.PrivateOrShared())
+	} else if policy.Private {
+		rules = append(rules, ipv4cidr.Private())
+	}
+
+	return rules
+
+}
+
+// newFinding creates a finding about a block
+// @input rule string: Name of the check or validation rule that failed
+// @input block Block: The block the finding is about
+// @input message string: Human-readable description of the problem
+// @returns Finding: The new finding
+func newFinding(rule string, block Block, message string) Finding {
+
+	return Finding{
+		Rule:    rule,
+		Block:   blockName(block),
+		CIDR:    block.CIDR,
+		Message: message,
+	}
+
+}
+
+// blockName returns the name of a block, or its CIDR range if it has no name
+// @input block Block: The block
+// @returns string: The name identifying the block
+func blockName(block Block) string {
+
+	if block.Name != "" {
+		return block.Name
+	}
+
+	return block.CIDR
+
+}
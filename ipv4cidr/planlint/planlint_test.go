@@ -0,0 +1,104 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package planlint
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// validPlan is an address plan without any problem, in YAML format
+const validPlan = `
+supernets:
+  - name: hub
+    cidr: 10.0.0.0/16
+subnets:
+  - name: firewall
+    cidr: 10.0.0.0/26
+    delegation: AzureFirewallSubnet
+  - name: workloads
+    cidr: 10.0.1.0/24
+reservations:
+  - name: future
+    cidr: 10.0.128.0/17
+policy:
+  minPrefixLength: 20
+  maxPrefixLength: 28
+  private: true
+`
+
+// invalidPlan is an address plan with one problem of every kind, in JSON format
+const invalidPlan = `{
+	"supernets": [{"name": "hub", "cidr": "10.0.0.0/16"}],
+	"subnets": [
+		{"name": "broken", "cidr": "10.0.0.0/33"},
+		{"name": "misaligned", "cidr": "10.0.0.5/24"},
+		{"name": "outside", "cidr": "10.1.0.0/24"},
+		{"name": "inner", "cidr": "10.0.0.128/25"},
+		{"name": "reserved", "cidr": "10.0.200.0/24"},
+		{"name": "tiny", "cidr": "10.0.2.0/29", "delegation": "AzureBastionSubnet"}
+	],
+	"reservations": [{"name": "future", "cidr": "10.0.128.0/17"}],
+	"policy": {"maxPrefixLength": 28}
+}`
+
+// TestLintValidPlan parses and lints a valid YAML plan
+// Success Metric: The plan is parsed and no findings are returned
+func TestLintValidPlan(t *testing.T) {
+
+	plan, err := ParsePlan([]byte(validPlan))
+	assert.Nil(t, err, "The plan is valid YAML, it should be parsed.")
+
+	assert.Len(t, plan.Subnets, 2)
+	assert.Equal(t, "AzureFirewallSubnet", plan.Subnets[0].Delegation)
+	assert.Equal(t, uint8(20), plan.Policy.MinPrefixLength)
+	assert.Empty(t, Lint(plan))
+
+}
+
+// TestLintInvalidPlan parses and lints a JSON plan with one problem of every kind
+// Success Metric: Every problem is reported with the right rule and block
+func TestLintInvalidPlan(t *testing.T) {
+
+	plan, err := ParsePlan([]byte(invalidPlan))
+	assert.Nil(t, err, "The plan is valid JSON, it should be parsed.")
+
+	findings := Lint(plan)
+	rules := make([]string, 0, len(findings))
+	blocks := make([]string, 0, len(findings))
+	for _, finding := range findings {
+		rules = append(rules, finding.Rule)
+		blocks = append(blocks, finding.Block)
+	}
+
+	assert.Equal(t, []string{InvalidRule, AlignmentRule, ContainmentRule, OverlapRule, ReservationRule, consts.MaxPrefixLengthRule, consts.AzureDelegationRule}, rules)
+	assert.Equal(t, []string{"broken", "misaligned", "outside", "inner", "reserved", "tiny", "tiny"}, blocks)
+
+	assert.Equal(t, consts.InvalidIPv4CIDRError, findings[0].Message)
+	assert.Equal(t, "10.0.0.5/24 is not aligned to its size, it should be 10.0.0.0/24", findings[1].Message)
+	assert.Equal(t, "Subnet inner (10.0.0.128/25) overlaps subnet misaligned (10.0.0.5/24) in 10.0.0.128/25", findings[3].Message)
+	assert.Equal(t, "Subnet reserved (10.0.200.0/24) overlaps reservation future (10.0.128.0/17)", findings[4].Message)
+
+	// Findings are machine-readable
+	output, err := json.Marshal(findings[2])
+	assert.Nil(t, err)
+	assert.Equal(t, `{"rule":"containment","block":"outside","cidr":"10.1.0.0/24","message":"10.1.0.0/24 is not within any of the allowed ranges [10.0.0.0/16]"}`, string(output))
+
+}
+
+// TestParsePlanInvalidInput parses content that is not a plan
+// Success Metric: An error is returned
+func TestParsePlanInvalidInput(t *testing.T) {
+
+	_, err := ParsePlan([]byte("subnets: [unterminated"))
+	assert.Error(t, err, "The content is not valid YAML. An error should be thrown.")
+
+	_, err = LoadPlan("testdata/missing.yaml")
+	assert.Error(t, err, "The file does not exist. An error should be thrown.")
+
+}
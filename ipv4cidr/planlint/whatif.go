@@ -0,0 +1,157 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package planlint
+
+import (
+	"github.com/microsoft/go-cidr-manager/ipv4cidr"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/utils"
+)
+
+// Change models a proposed change to the subnets of an address plan
+// @field Add []Block: The subnets to add
+// @field Remove []Block: The subnets to remove, matched by canonical CIDR range (e.g. 10.0.0.5 matches 10.0.0.5/32). A block without a name removes every subnet with that CIDR range
+type Change struct {
+	Add    []Block `json:"add,omitempty" yaml:"add,omitempty"`
+	Remove []Block `json:"remove,omitempty" yaml:"remove,omitempty"`
+}
+
+// Utilization is the fraction of a supernet covered by subnets, before and after a change
+// @field Supernet string: Name of the supernet, or its CIDR range if it has no name
+// @field Before float64: Fraction (0-1) of the supernet covered by the current subnets
+// @field After float64: Fraction (0-1) of the supernet covered by the proposed subnets
+type Utilization struct {
+	Supernet string  `json:"supernet" yaml:"supernet"`
+	Before   float64 `json:"before" yaml:"before"`
+	After    float64 `json:"after" yaml:"after"`
+}
+
+// Summarization is a group of disjoint subnets that together cover exactly one CIDR range, so they can be summarized, e.g. into a single route
+// @field CIDR string: The CIDR range covered by the subnets
+// @field Blocks []string: Names of the subnets, or their CIDR range if they have no name
+type Summarization struct {
+	CIDR   string   `json:"cidr" yaml:"cidr"`
+	Blocks []string `json:"blocks" yaml:"blocks"`
+}
+
+// Impact describes the effect of a change on an address plan, for review workflows
+// @field Plan *Plan: The proposed plan, i.e. the current plan with the change applied
+// @field Utilization []Utilization: The utilization of every valid supernet, in the order of the plan
+// @field NewFindings []Finding: The findings of the proposed plan that the current plan does not have
+// @field ResolvedFindings []Finding: The findings of the current plan that the proposed plan no longer has
+// @field Summarizations []Summarization: The groups of proposed subnets that can be summarized, in order of IP
+type Impact struct {
+	Plan             *Plan           `json:"plan" yaml:"plan"`
+	Utilization      []Utilization   `json:"utilization" yaml:"utilization"`
+	NewFindings      []Finding       `json:"newFindings" yaml:"newFindings"`
+	ResolvedFindings []Finding       `json:"resolvedFindings" yaml:"resolvedFindings"`
+	Summarizations   []Summarization `json:"summarizations" yaml:"summarizations"`
+}
+
+// WhatIf reports the effect of a proposed change on an address plan, without modifying the plan
+// @input plan *Plan: The current plan
+// @input change Change: The proposed change to its subnets
+// @returns *Impact: The proposed plan, its utilization, the findings it adds or resolves, and its summarization opportunities
+// @returns error: If a subnet to remove is not in the plan, an error is returned
+func WhatIf(plan *Plan, change Change) (*Impact, error) {
+
+	proposed := *plan
+	proposed.Subnets = make([]Block, 0, len(plan.Subnets)+len(change.Add))
+
+	// Apply the removals first, so that a change can replace a subnet with another of the same name
+	removed := make([]bool, len(plan.Subnets))
+	for _, removal := range change.Remove {
+
+		found := false
+		cidr := canonicalCIDR(removal.CIDR)
+		for n, subnet := range plan.Subnets {
+			if canonicalCIDR(subnet.CIDR) == cidr && (removal.Name == "" || subnet.Name == removal.Name) {
+				removed[n] = true
+				found = true
+			}
+		}
+
+		if !found {
+			return nil, utils.NewError(consts.PlanConflictCode, consts.SubnetNotInPlanError)
+		}
+
+	}
+
+	for n, subnet := range plan.Subnets {
+		if !removed[n] {
+			proposed.Subnets = append(proposed.Subnets, subnet)
+		}
+	}
+	proposed.Subnets = append(proposed.Subnets, change.Add...)
+
+	impact := &Impact{
+		Plan:           &proposed,
+		Utilization:    make([]Utilization, 0, len(plan.Supernets)),
+		Summarizations: make([]Summarization, 0),
+	}
+
+	// Utilization of every supernet, before and after. Invalid blocks are reported by Lint, so the parsing findings are not needed here
+	discarded := make([]Finding, 0)
+	currentSubnets := blockCIDRs(parseBlocks(plan.Subnets, &discarded))
+	proposedParsed := parseBlocks(proposed.Subnets, &discarded)
+	proposedSubnets := blockCIDRs(proposedParsed)
+
+	for _, supernet := range parseBlocks(plan.Supernets, &discarded) {
+		impact.Utilization = append(impact.Utilization, Utilization{
+			Supernet: blockName(supernet.block),
+			Before:   ipv4cidr.Coverage(supernet.cidr, currentSubnets),
+			After:    ipv4cidr.Coverage(supernet.cidr, proposedSubnets),
+		})
+	}
+
+	currentFindings, proposedFindings := Lint(plan), Lint(&proposed)
+	impact.NewFindings = subtractFindings(proposedFindings, currentFindings)
+	impact.ResolvedFindings = subtractFindings(currentFindings, proposedFindings)
+
+	// Groups of more than one subnet aggregating into a single CIDR range can be summarized.
+	// The subnets of a group cover the aggregate exactly, so they are disjoint if and only if their sizes add up to its size
+	for _, aggregate := range ipv4cidr.Aggregate(proposedSubnets) {
+
+		summarization := Summarization{CIDR: aggregate.ToString(), Blocks: make([]string, 0)}
+		size := uint64(0)
+		for _, subnet := range proposedParsed {
+			if aggregate.ContainsRange(subnet.cidr.Range()) {
+				summarization.Blocks = append(summarization.Blocks, blockName(subnet.block))
+				size += utils.GetCIDRRangeLength64(subnet.cidr.GetMask())
+			}
+		}
+
+		if len(summarization.Blocks) > 1 && size == utils.GetCIDRRangeLength64(aggregate.GetMask()) {
+			impact.Summarizations = append(impact.Summarizations, summarization)
+		}
+
+	}
+
+	return impact, nil
+
+}
+
+// subtractFindings returns the findings of a list that are not in another list, counting duplicates
+// @input findings []Finding: The findings to keep
+// @input existing []Finding: The findings to subtract
+// @returns []Finding: The findings of the first list not in the second, in order
+func subtractFindings(findings []Finding, existing []Finding) []Finding {
+
+	counts := make(map[Finding]int, len(existing))
+	for _, finding := range existing {
+		counts[finding]++
+	}
+
+	result := make([]Finding, 0)
+	for _, finding := range findings {
+		if counts[finding] > 0 {
+			counts[finding]--
+			continue
+		}
+		result = append(result, finding)
+	}
+
+	return result
+
+}
@@ -0,0 +1,118 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package planlint
+
+import (
+	"testing"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWhatIf analyzes the impact of adding and removing subnets of a plan
+// Success Metric: Utilization, new and resolved findings, and summarization opportunities are reported, and the plan is not modified
+func TestWhatIf(t *testing.T) {
+
+	plan, err := ParsePlan([]byte(validPlan))
+	assert.Nil(t, err, "The plan is valid YAML, it should be parsed.")
+
+	impact, err := WhatIf(plan, Change{
+		Add: []Block{
+			{Name: "apps", CIDR: "10.0.0.64/26"},
+			{Name: "data", CIDR: "10.0.0.128/25"},
+			{Name: "archive", CIDR: "10.0.200.0/24"},
+		},
+		Remove: []Block{{CIDR: "10.0.1.0/24"}},
+	})
+	assert.Nil(t, err)
+
+	assert.Len(t, plan.Subnets, 2, "The current plan should not be modified")
+	if assert.Len(t, impact.Plan.Subnets, 4) {
+		assert.Equal(t, "firewall", impact.Plan.Subnets[0].Name)
+		assert.Equal(t, "archive", impact.Plan.Subnets[3].Name)
+	}
+
+	if assert.Len(t, impact.Utilization, 1) {
+		assert.Equal(t, "hub", impact.Utilization[0].Supernet)
+		assert.InDelta(t, 320.0/65536, impact.Utilization[0].Before, 1e-9)
+		assert.InDelta(t, 512.0/65536, impact.Utilization[0].After, 1e-9)
+	}
+
+	if assert.Len(t, impact.NewFindings, 1) {
+		assert.Equal(t, ReservationRule, impact.NewFindings[0].Rule)
+		assert.Equal(t, "archive", impact.NewFindings[0].Block)
+	}
+	assert.Empty(t, impact.ResolvedFindings)
+
+	if assert.Len(t, impact.Summarizations, 1) {
+		assert.Equal(t, Summarization{CIDR: "10.0.0.0/24", Blocks: []string{"firewall", "apps", "data"}}, impact.Summarizations[0])
+	}
+
+	// Removing the offending subnet resolves its finding
+	resolved, err := WhatIf(impact.Plan, Change{Remove: []Block{{Name: "archive", CIDR: "10.0.200.0/24"}}})
+	assert.Nil(t, err)
+	assert.Empty(t, resolved.NewFindings)
+	assert.Equal(t, impact.NewFindings, resolved.ResolvedFindings)
+
+}
+
+// TestWhatIfRemoveMissingSubnet removes subnets that are not in the plan
+// Success Metric: Throw an error saying the subnet to remove is not in the plan
+func TestWhatIfRemoveMissingSubnet(t *testing.T) {
+
+	plan, _ := ParsePlan([]byte(validPlan))
+
+	for _, removal := range []Block{{CIDR: "10.0.2.0/24"}, {Name: "apps", CIDR: "10.0.1.0/24"}} {
+
+		_, err := WhatIf(plan, Change{Remove: []Block{removal}})
+		if assert.Error(t, err, "%s is not a subnet of the plan. An error should be thrown.", removal.CIDR) {
+
+			assert.Equal(t, consts.SubnetNotInPlanError, err.Error(), "Error thrown should be: \"%s\"", consts.SubnetNotInPlanError)
+			assert.Equal(t, consts.PlanConflictCode, ipv4cidr.GetErrorCode(err))
+
+		}
+
+	}
+
+}
+
+// TestWhatIfRemoveCanonicalCIDR removes a subnet written as a single IP address from a plan where it is written with a /32 mask
+// Success Metric: The subnet is removed, as both spellings are the same CIDR range
+func TestWhatIfRemoveCanonicalCIDR(t *testing.T) {
+
+	plan := &Plan{
+		Supernets: []Block{{Name: "hub", CIDR: "10.0.0.0/16"}},
+		Subnets: []Block{
+			{Name: "gateway", CIDR: "10.0.0.5/32"},
+			{Name: "apps", CIDR: "10.0.1.0/24"},
+		},
+	}
+
+	impact, err := WhatIf(plan, Change{Remove: []Block{{CIDR: "10.0.0.5"}}})
+	assert.Nil(t, err, "10.0.0.5 is the same CIDR range as 10.0.0.5/32, it should be removed.")
+	assert.Equal(t, []Block{{Name: "apps", CIDR: "10.0.1.0/24"}}, impact.Plan.Subnets)
+
+}
+
+// TestWhatIfOverlappingSummarization analyzes a plan where a subnet overlaps another one in the same aggregate
+// Success Metric: Groups of overlapping subnets are not reported as summarization opportunities, groups of disjoint subnets are
+func TestWhatIfOverlappingSummarization(t *testing.T) {
+
+	plan := &Plan{
+		Supernets: []Block{{Name: "hub", CIDR: "10.0.0.0/16"}},
+		Subnets: []Block{
+			{Name: "apps", CIDR: "10.0.0.0/24"},
+			{Name: "apps-half", CIDR: "10.0.0.0/25"},
+			{Name: "data-0", CIDR: "10.0.2.0/25"},
+			{Name: "data-1", CIDR: "10.0.2.128/25"},
+		},
+	}
+
+	impact, err := WhatIf(plan, Change{})
+	assert.Nil(t, err)
+	assert.Equal(t, []Summarization{{CIDR: "10.0.2.0/24", Blocks: []string{"data-0", "data-1"}}}, impact.Summarizations)
+
+}
@@ -0,0 +1,224 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package rdap
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/utils"
+)
+
+// Registration is the ownership data registered for a network in a regional internet registry
+// @field Networks []*ipv4cidr.IPv4CIDR: The CIDR ranges of the registered network, which may be larger than the CIDR range looked up
+// @field Handle string: The registry handle of the network, e.g. NET-104-16-0-0-1
+// @field Name string: The name of the network
+// @field Country string: The ISO 3166 country code of the network, if registered
+// @field Organization string: The name of the registrant of the network, if registered
+type Registration struct {
+	Networks     []*ipv4cidr.IPv4CIDR
+	Handle       string
+	Name         string
+	Country      string
+	Organization string
+}
+
+// Resolver looks up the ownership data of CIDR ranges
+// Implementations can query RDAP, whois, or a local database
+type Resolver interface {
+	Resolve(cidr *ipv4cidr.IPv4CIDR) (*Registration, error)
+}
+
+// Annotation is a CIDR range along with its ownership data
+// @field CIDR *ipv4cidr.IPv4CIDR: The CIDR range
+// @field Registration *Registration: The ownership data of the CIDR range, or nil for private and shared address space and failed lookups
+// @field Err error: The error of the lookup of the CIDR range, e.g. if it is not registered, or nil if it succeeded or was not needed
+type Annotation struct {
+	CIDR         *ipv4cidr.IPv4CIDR
+	Registration *Registration
+	Err          error
+}
+
+// Annotate looks up the ownership data of a list of CIDR ranges, e.g. to add ownership context for external ranges to audit reports
+// Private and shared address space is not registered, so it is not looked up. A failed lookup is recorded in its annotation, and does not stop the others
+// @input resolver Resolver: The resolver to look up the CIDR ranges with. A CachingResolver avoids repeated lookups of the same CIDR range
+// @input cidrs []*ipv4cidr.IPv4CIDR: The CIDR ranges to annotate
+// @returns []Annotation: The annotated CIDR ranges, in the order of the input
+func Annotate(resolver Resolver, cidrs []*ipv4cidr.IPv4CIDR) []Annotation {
+
+	annotations := make([]Annotation, 0, len(cidrs))
+	for _, cidr := range cidrs {
+
+		annotation := Annotation{CIDR: cidr}
+		if !cidr.IsPrivate() && !cidr.IsSharedAddressSpace() {
+			annotation.Registration, annotation.Err = resolver.Resolve(cidr)
+		}
+
+		annotations = append(annotations, annotation)
+
+	}
+
+	return annotations
+
+}
+
+// Client looks up the ownership data of CIDR ranges with the Registration Data Access Protocol (RFC 9082, RFC 9083)
+// @field BaseURL string: The URL of the RDAP service. https://rdap.org redirects to the authoritative registry
+// @field HTTPClient *http.Client: The HTTP client to send requests with. If nil, an HTTP client with a timeout of consts.RDAPTimeout is used
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// defaultHTTPClient is the HTTP client of RDAP clients that do not set one. Unlike http.DefaultClient, it has a timeout
+var defaultHTTPClient = &http.Client{Timeout: consts.RDAPTimeout}
+
+// NewClient creates an RDAP client for the bootstrap service at https://rdap.org, using an HTTP client with a timeout of consts.RDAPTimeout
+// @returns *Client: The RDAP client
+func NewClient() *Client {
+
+	return &Client{BaseURL: consts.RDAPBootstrapURL, HTTPClient: defaultHTTPClient}
+
+}
+
+// rdapIPNetwork models the fields of an RDAP IP network object used by the client
+type rdapIPNetwork struct {
+	Handle       string `json:"handle"`
+	StartAddress string `json:"startAddress"`
+	EndAddress   string `json:"endAddress"`
+	Name         string `json:"name"`
+	Country      string `json:"country"`
+	Entities     []struct {
+		Roles      []string      `json:"roles"`
+		VCardArray []interface{} `json:"vcardArray"`
+	} `json:"entities"`
+}
+
+// Resolve looks up the ownership data of a CIDR range
+// @input cidr *ipv4cidr.IPv4CIDR: The CIDR range to look up
+// @returns *Registration: The ownership data of the most specific registered network containing the CIDR range
+// @returns error: If the request fails, or the server does not return a valid registration within consts.RDAPMaxResponseSize bytes, an error is returned
+func (c *Client) Resolve(cidr *ipv4cidr.IPv4CIDR) (*Registration, error) {
+
+	request, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(c.BaseURL, "/")+"/ip/"+cidr.ToString(), nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Accept", "application/rdap+json")
+
+	client := c.HTTPClient
+	if client == nil {
+		client = defaultHTTPClient
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, utils.NewError(consts.LookupFailedCode, consts.RDAPLookupFailedError)
+	}
+
+	var network rdapIPNetwork
+	if err := json.NewDecoder(io.LimitReader(response.Body, consts.RDAPMaxResponseSize)).Decode(&network); err != nil {
+		return nil, utils.NewError(consts.LookupFailedCode, consts.RDAPLookupFailedError)
+	}
+
+	networks, err := ipv4cidr.SummarizeRange(network.StartAddress, network.EndAddress)
+	if err != nil {
+		return nil, utils.NewError(consts.LookupFailedCode, consts.RDAPLookupFailedError)
+	}
+
+	registration := &Registration{Networks: networks, Handle: network.Handle, Name: network.Name, Country: network.Country}
+	for _, entity := range network.Entities {
+		for _, role := range entity.Roles {
+			if role == "registrant" {
+				registration.Organization = vcardName(entity.VCardArray)
+			}
+		}
+	}
+
+	return registration, nil
+
+}
+
+// vcardName finds the formatted name (fn) of a jCard (RFC 7095), e.g. ["vcard", [["version", {}, "text", "4.0"], ["fn", {}, "text", "Example Inc."]]]
+// @input vcard []interface{}: The jCard
+// @returns string: The formatted name, or an empty string if there is none
+func vcardName(vcard []interface{}) string {
+
+	if len(vcard) < 2 {
+		return ""
+	}
+
+	properties, _ := vcard[1].([]interface{})
+	for _, property := range properties {
+
+		fields, _ := property.([]interface{})
+		if len(fields) == 4 && fields[0] == "fn" {
+			name, _ := fields[3].(string)
+			return name
+		}
+
+	}
+
+	return ""
+
+}
+
+// CachingResolver caches the registrations looked up by another resolver, keyed by the CIDR range looked up
+// Registrations are not reused for other CIDR ranges of the same network, since a more specific network may be registered within it
+// @field resolver Resolver: The resolver to look up uncached CIDR ranges with
+// @field registrations map[string]*Registration: The registrations looked up so far, keyed by CIDR range
+// @field lock sync.Mutex: Guards the registrations, so that the resolver can be shared between goroutines
+type CachingResolver struct {
+	resolver      Resolver
+	registrations map[string]*Registration
+	lock          sync.Mutex
+}
+
+// NewCachingResolver creates a resolver caching the registrations looked up by another resolver
+// @input resolver Resolver: The resolver to look up uncached CIDR ranges with
+// @returns *CachingResolver: The caching resolver
+func NewCachingResolver(resolver Resolver) *CachingResolver {
+
+	return &CachingResolver{resolver: resolver, registrations: make(map[string]*Registration)}
+
+}
+
+// Resolve looks up the ownership data of a CIDR range, from the cache if it was looked up before
+// @input cidr *ipv4cidr.IPv4CIDR: The CIDR range to look up
+// @returns *Registration: The ownership data of the network containing the CIDR range
+// @returns error: If the underlying lookup fails, an error is returned. Errors are not cached
+func (c *CachingResolver) Resolve(cidr *ipv4cidr.IPv4CIDR) (*Registration, error) {
+
+	key := cidr.ToString()
+
+	c.lock.Lock()
+	registration, ok := c.registrations[key]
+	c.lock.Unlock()
+
+	if ok {
+		return registration, nil
+	}
+
+	registration, err := c.resolver.Resolve(cidr)
+	if err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	c.registrations[key] = registration
+	c.lock.Unlock()
+
+	return registration, nil
+
+}
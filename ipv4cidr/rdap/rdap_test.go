@@ -0,0 +1,209 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package rdap
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rdapResponseSample is an excerpt of an RDAP IP network response
+const rdapResponseSample = `{
+  "objectClassName": "ip network",
+  "handle": "NET-104-16-0-0-1",
+  "startAddress": "104.16.0.0",
+  "endAddress": "104.31.255.255",
+  "ipVersion": "v4",
+  "name": "CLOUDFLARENET",
+  "country": "US",
+  "entities": [
+    {
+      "objectClassName": "entity",
+      "roles": ["abuse"],
+      "vcardArray": ["vcard", [["version", {}, "text", "4.0"], ["fn", {}, "text", "Abuse"]]]
+    },
+    {
+      "objectClassName": "entity",
+      "roles": ["registrant"],
+      "vcardArray": ["vcard", [["version", {}, "text", "4.0"], ["fn", {}, "text", "Cloudflare, Inc."]]]
+    }
+  ]
+}`
+
+// mustParse parses a CIDR range in tests
+func mustParse(t *testing.T, cidr string) *ipv4cidr.IPv4CIDR {
+
+	parsed, err := ipv4cidr.NewIPv4CIDR(cidr, false)
+	assert.Nil(t, err, "%s is a valid CIDR block", cidr)
+
+	return parsed
+
+}
+
+// newTestServer starts an RDAP server answering IP network queries with the sample (except 192.0.2.0/24, which is not found), and recording the queries
+func newTestServer(queries *[]string) *httptest.Server {
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		*queries = append(*queries, r.URL.Path)
+		if r.URL.Path == "/ip/192.0.2.0/24" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/rdap+json")
+		w.Write([]byte(rdapResponseSample))
+
+	}))
+
+}
+
+// TestClientResolve looks up the ownership data of CIDR ranges from an RDAP server
+// Success Metric: The registered network, handle, name, country and registrant are returned
+func TestClientResolve(t *testing.T) {
+
+	queries := make([]string, 0)
+	server := newTestServer(&queries)
+	defer server.Close()
+
+	client := &Client{BaseURL: server.URL + "/", HTTPClient: server.Client()}
+
+	registration, err := client.Resolve(mustParse(t, "104.16.132.0/24"))
+	assert.Nil(t, err, "The server returns a registration, it should be parsed.")
+	assert.Equal(t, []string{"/ip/104.16.132.0/24"}, queries)
+
+	if assert.NotNil(t, registration) {
+
+		assert.Equal(t, "104.16.0.0/12", registration.Networks[0].ToString())
+		assert.Equal(t, "NET-104-16-0-0-1", registration.Handle)
+		assert.Equal(t, "CLOUDFLARENET", registration.Name)
+		assert.Equal(t, "US", registration.Country)
+		assert.Equal(t, "Cloudflare, Inc.", registration.Organization)
+
+	}
+
+	_, err = client.Resolve(mustParse(t, "192.0.2.0/24"))
+	if assert.Error(t, err, "The server returns no registration. An error should be thrown.") {
+
+		assert.Equal(t, consts.RDAPLookupFailedError, err.Error(), "Error thrown should be: \"%s\"", consts.RDAPLookupFailedError)
+
+	}
+
+}
+
+// TestClientResolveDefaultHTTPClient looks up a CIDR range with a client that has no HTTP client set
+// Success Metric: An HTTP client with a timeout is used instead of panicking, as by NewClient
+func TestClientResolveDefaultHTTPClient(t *testing.T) {
+
+	assert.Equal(t, consts.RDAPTimeout, NewClient().HTTPClient.Timeout, "The default client should not wait forever for a stalled server")
+
+	queries := make([]string, 0)
+	server := newTestServer(&queries)
+	defer server.Close()
+
+	client := &Client{BaseURL: server.URL}
+
+	registration, err := client.Resolve(mustParse(t, "104.16.132.0/24"))
+	assert.Nil(t, err, "The server returns a registration, it should be parsed.")
+	if assert.NotNil(t, registration) {
+		assert.Equal(t, "CLOUDFLARENET", registration.Name)
+	}
+
+}
+
+// TestClientResolveInvalidResponse looks up CIDR ranges from a server returning invalid or oversized JSON
+// Success Metric: Throw an error with the lookup failed code instead of a raw decoding error
+func TestClientResolveInvalidResponse(t *testing.T) {
+
+	oversized := `{"name": "` + strings.Repeat("A", int(consts.RDAPMaxResponseSize)) + `"}`
+
+	for _, body := range []string{`{"name": `, `not json`, oversized} {
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(body))
+		}))
+
+		client := &Client{BaseURL: server.URL, HTTPClient: server.Client()}
+		_, err := client.Resolve(mustParse(t, "104.16.132.0/24"))
+		if assert.Error(t, err, "The server returns no valid registration. An error should be thrown.") {
+
+			assert.Equal(t, consts.RDAPLookupFailedError, err.Error(), "Error thrown should be: \"%s\"", consts.RDAPLookupFailedError)
+			assert.Equal(t, consts.LookupFailedCode, ipv4cidr.GetErrorCode(err))
+
+		}
+
+		server.Close()
+
+	}
+
+}
+
+// TestAnnotateWithCache annotates CIDR ranges through a caching resolver
+// Success Metric: Each public CIDR range is looked up once, and private and shared ranges are not looked up
+func TestAnnotateWithCache(t *testing.T) {
+
+	queries := make([]string, 0)
+	server := newTestServer(&queries)
+	defer server.Close()
+
+	resolver := NewCachingResolver(&Client{BaseURL: server.URL, HTTPClient: server.Client()})
+
+	cidrs := []*ipv4cidr.IPv4CIDR{
+		mustParse(t, "104.16.132.0/24"),
+		mustParse(t, "10.0.0.0/8"),
+		mustParse(t, "100.64.0.0/10"),
+		mustParse(t, "104.16.132.0/24"),
+	}
+
+	annotations := Annotate(resolver, cidrs)
+	assert.Equal(t, []string{"/ip/104.16.132.0/24"}, queries, "The repeated CIDR range should be answered from the cache.")
+
+	if assert.Len(t, annotations, 4) {
+
+		assert.Equal(t, "Cloudflare, Inc.", annotations[0].Registration.Organization)
+		assert.Nil(t, annotations[1].Registration, "Private address space is not registered.")
+		assert.Nil(t, annotations[2].Registration, "Shared address space is not registered.")
+		assert.Equal(t, annotations[0].Registration, annotations[3].Registration)
+
+		for _, annotation := range annotations {
+			assert.Nil(t, annotation.Err)
+		}
+
+	}
+
+}
+
+// TestAnnotateFailedLookup annotates CIDR ranges where one is not registered
+// Success Metric: The failed lookup is recorded in its annotation, and the other CIDR ranges are still annotated
+func TestAnnotateFailedLookup(t *testing.T) {
+
+	queries := make([]string, 0)
+	server := newTestServer(&queries)
+	defer server.Close()
+
+	client := &Client{BaseURL: server.URL, HTTPClient: server.Client()}
+	annotations := Annotate(client, []*ipv4cidr.IPv4CIDR{mustParse(t, "192.0.2.0/24"), mustParse(t, "104.16.132.0/24")})
+
+	if assert.Len(t, annotations, 2) {
+
+		assert.Nil(t, annotations[0].Registration)
+		if assert.Error(t, annotations[0].Err, "The server returns no registration. An error should be recorded.") {
+
+			assert.Equal(t, consts.RDAPLookupFailedError, annotations[0].Err.Error(), "Error recorded should be: \"%s\"", consts.RDAPLookupFailedError)
+
+		}
+
+		assert.Nil(t, annotations[1].Err)
+		assert.Equal(t, "CLOUDFLARENET", annotations[1].Registration.Name)
+
+	}
+
+}
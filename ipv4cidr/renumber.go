@@ -0,0 +1,172 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"sort"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/utils"
+)
+
+// Rebase maps a CIDR range at some offset within one supernet to the CIDR range at the same offset within another supernet of the same size
+// For example, rebasing 10.1.2.0/24 from 10.1.0.0/16 to 172.20.0.0/16 gives 172.20.2.0/24
+// @input cidr *IPv4CIDR: The CIDR range to rebase
+// @input oldParent *IPv4CIDR: The supernet currently containing the CIDR range
+// @input newParent *IPv4CIDR: The supernet to move the CIDR range to
+// @returns *IPv4CIDR: The CIDR range at the same offset within the new supernet
+// @returns error: If the CIDR range is not within the old supernet, or the supernets differ in size, an error is returned
+func Rebase(cidr *IPv4CIDR, oldParent *IPv4CIDR, newParent *IPv4CIDR) (*IPv4CIDR, error) {
+
+	if oldParent.mask != newParent.mask {
+		return nil, utils.NewError(consts.SizeMismatchCode, consts.ParentSizeMismatchError)
+	}
+
+	if !oldParent.contains(cidr) {
+		return nil, utils.NewError(consts.NotWithinParentCode, consts.NotWithinParentError)
+	}
+
+	offset := cidr.ip - oldParent.ip
+
+	return fromIPAndMask(newParent.ip+offset, cidr.mask), nil
+
+}
+
+// RenumberMapping pairs an existing CIDR range with the CIDR range it is renumbered to
+// @field Old *IPv4CIDR: The existing CIDR range
+// @field New *IPv4CIDR: The CIDR range in the new address space
+type RenumberMapping struct {
+	Old *IPv4CIDR
+	New *IPv4CIDR
+}
+
+// RenumberPlan is the result of planning the renumbering of a set of allocations into a new address space
+// @field Mappings []RenumberMapping: The old to new mapping of every allocation that fits, in the order of the input
+// @field Unmapped []*IPv4CIDR: The allocations that do not fit into the new address space, in the order of the input
+// @field Shortfall uint64: Number of addresses that would be needed in addition to the new address space to fit every allocation
+// @field Conflicts []Overlap: Overlapping allocations in the input. Allocations nested in another one are moved together with it
+// @field PreservesLayout bool: True if every allocation keeps its offset relative to the others, so adjacency and alignment are preserved
+type RenumberPlan struct {
+	Mappings        []RenumberMapping
+	Unmapped        []*IPv4CIDR
+	Shortfall       uint64
+	Conflicts       []Overlap
+	PreservesLayout bool
+}
+
+// PlanRenumbering plans how to move a set of existing allocations into a new address space, without changing the size of any allocation
+// If the new address space is at least as large as the smallest block covering the allocations, every allocation keeps its relative offset.
+// Otherwise the allocations are packed in address order, each at the next boundary aligned to its size, so adjacent allocations stay adjacent where alignment allows. If they still do not fit, they are packed largest first, which wastes no space, and the allocations that do not fit are reported.
+// @input allocations []*IPv4CIDR: The existing allocations
+// @input newParent *IPv4CIDR: The new address space
+// @returns *RenumberPlan: The old to new mapping, and the conflict and shortfall report
+func PlanRenumbering(allocations []*IPv4CIDR, newParent *IPv4CIDR) *RenumberPlan {
+
+	plan := RenumberPlan{
+		Mappings:  make([]RenumberMapping, 0, len(allocations)),
+		Unmapped:  make([]*IPv4CIDR, 0),
+		Conflicts: OverlapReport(allocations),
+	}
+
+	// Only the outermost allocations are placed, nested allocations follow their outermost allocation
+	outermost := normalize(allocations)
+	placements := make(map[*IPv4CIDR]*IPv4CIDR, len(outermost))
+
+	if len(outermost) > 0 {
+
+		first := outermost[0].ip
+		last := outermost[len(outermost)-1].lastIP()
+		oldSpace := fromIPAndMask(first, utils.GetCommonPrefixLength(first, last))
+
+		if oldSpace.mask >= newParent.mask {
+
+			// The new address space is large enough to keep the layout: rebase the covering block to the start of the new address space
+			newSpace := fromIPAndMask(newParent.ip, oldSpace.mask)
+			for _, allocation := range outermost {
+				placements[allocation], _ = Rebase(allocation, oldSpace, newSpace)
+			}
+			plan.PreservesLayout = true
+
+		} else if !packAllocations(outermost, newParent, placements) {
+
+			// Packing in address order did not fit everything, so pack the largest allocations first
+			for allocation := range placements {
+				delete(placements, allocation)
+			}
+
+			bySize := make([]*IPv4CIDR, len(outermost))
+			copy(bySize, outermost)
+			sort.SliceStable(bySize, func(a, b int) bool {
+				return bySize[a].mask < bySize[b].mask
+			})
+			packAllocations(bySize, newParent, placements)
+
+		}
+
+	}
+
+	for _, allocation := range allocations {
+
+		if allocation == nil {
+			continue
+		}
+
+		// Find the outermost allocation containing this one, and move it by the same offset
+		var container *IPv4CIDR
+		for _, candidate := range outermost {
+			if candidate.contains(allocation) {
+				container = candidate
+				break
+			}
+		}
+
+		placement, ok := placements[container]
+		if !ok {
+			plan.Unmapped = append(plan.Unmapped, allocation)
+			if container == allocation {
+				plan.Shortfall += allocation.size()
+			}
+			continue
+		}
+
+		rebased, _ := Rebase(allocation, container, placement)
+		plan.Mappings = append(plan.Mappings, RenumberMapping{Old: allocation, New: rebased})
+
+	}
+
+	return &plan
+
+}
+
+// packAllocations places allocations one after the other in an address space, each at the next boundary aligned to its size
+// Allocations that do not fit are skipped, and the following ones are still attempted.
+// @input allocations []*IPv4CIDR: The allocations to place, in the order to place them
+// @input space *IPv4CIDR: The address space to place them in
+// @input placements map[*IPv4CIDR]*IPv4CIDR: The map to record the placement of each allocation in
+// @returns bool: True if every allocation was placed
+func packAllocations(allocations []*IPv4CIDR, space *IPv4CIDR, placements map[*IPv4CIDR]*IPv4CIDR) bool {
+
+	next := uint64(space.ip)
+	end := uint64(space.lastIP())
+	complete := true
+
+	for _, allocation := range allocations {
+
+		// Round the next free address up to the alignment of the allocation
+		size := allocation.size()
+		start := (next + size - 1) / size * size
+
+		if allocation.mask < space.mask || start+size-1 > end {
+			complete = false
+			continue
+		}
+
+		placements[allocation] = fromIPAndMask(uint32(start), allocation.mask)
+		next = start + size
+
+	}
+
+	return complete
+
+}
@@ -0,0 +1,135 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"testing"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRebase moves CIDR ranges from one supernet to another of the same size
+// Success Metric: The CIDR range keeps its offset and size within the new supernet
+func TestRebase(t *testing.T) {
+
+	oldParent, _ := NewIPv4CIDR("10.1.0.0/16", false)
+	newParent, _ := NewIPv4CIDR("172.20.0.0/16", false)
+
+	testInputs := map[string]string{
+		"10.1.2.0/24":     "172.20.2.0/24",
+		"10.1.0.0/16":     "172.20.0.0/16",
+		"10.1.255.128/25": "172.20.255.128/25",
+	}
+
+	for input, expected := range testInputs {
+
+		CIDR, _ := NewIPv4CIDR(input, false)
+		rebased, err := Rebase(CIDR, oldParent, newParent)
+		assert.Nil(t, err, "%s is within the old parent, it should be rebased.", input)
+		assert.Equal(t, expected, rebased.ToString())
+
+	}
+
+}
+
+// TestRebaseInvalidInput rebases a CIDR range outside the old supernet, and between supernets of different sizes
+// Success Metric: Throw the appropriate error for each input
+func TestRebaseInvalidInput(t *testing.T) {
+
+	oldParent, _ := NewIPv4CIDR("10.1.0.0/16", false)
+	newParent, _ := NewIPv4CIDR("172.20.0.0/16", false)
+	largerParent, _ := NewIPv4CIDR("172.16.0.0/12", false)
+
+	CIDR, _ := NewIPv4CIDR("10.2.0.0/24", false)
+	_, err := Rebase(CIDR, oldParent, newParent)
+	if assert.Error(t, err, "10.2.0.0/24 is not within 10.1.0.0/16. An error should be thrown.") {
+
+		assert.Equal(t, consts.NotWithinParentError, err.Error(), "Error thrown should be: \"%s\"", consts.NotWithinParentError)
+
+	}
+
+	CIDR, _ = NewIPv4CIDR("10.1.0.0/24", false)
+	_, err = Rebase(CIDR, oldParent, largerParent)
+	if assert.Error(t, err, "The parents are of different sizes. An error should be thrown.") {
+
+		assert.Equal(t, consts.ParentSizeMismatchError, err.Error(), "Error thrown should be: \"%s\"", consts.ParentSizeMismatchError)
+
+	}
+
+}
+
+// mappingStrings is a test helper that converts renumbering mappings into "old -> new" strings
+func mappingStrings(mappings []RenumberMapping) []string {
+
+	strs := make([]string, 0, len(mappings))
+	for _, mapping := range mappings {
+		strs = append(strs, mapping.Old.ToString()+" -> "+mapping.New.ToString())
+	}
+
+	return strs
+
+}
+
+// TestPlanRenumberingPreservingLayout renumbers allocations into an address space large enough to keep their layout
+// Success Metric: Every allocation keeps its offset relative to the others, and nested allocations follow their container
+func TestPlanRenumberingPreservingLayout(t *testing.T) {
+
+	allocations := parseAll(t, "10.1.0.0/24", "10.1.1.0/24", "10.1.4.0/22", "10.1.4.128/25")
+	newParent, _ := NewIPv4CIDR("172.20.0.0/16", false)
+
+	plan := PlanRenumbering(allocations, newParent)
+
+	expected := []string{
+		"10.1.0.0/24 -> 172.20.0.0/24",
+		"10.1.1.0/24 -> 172.20.1.0/24",
+		"10.1.4.0/22 -> 172.20.4.0/22",
+		"10.1.4.128/25 -> 172.20.4.128/25",
+	}
+	assert.Equal(t, expected, mappingStrings(plan.Mappings))
+	assert.True(t, plan.PreservesLayout)
+	assert.Empty(t, plan.Unmapped)
+	assert.Len(t, plan.Conflicts, 1, "10.1.4.128/25 is nested in 10.1.4.0/22")
+
+}
+
+// TestPlanRenumberingPacking renumbers sparse allocations into a smaller address space
+// Success Metric: The allocations are packed in address order, aligned to their size
+func TestPlanRenumberingPacking(t *testing.T) {
+
+	allocations := parseAll(t, "10.1.0.0/24", "10.1.1.0/25", "10.200.0.0/23")
+	newParent, _ := NewIPv4CIDR("172.20.0.0/22", false)
+
+	plan := PlanRenumbering(allocations, newParent)
+
+	expected := []string{
+		"10.1.0.0/24 -> 172.20.0.0/24",
+		"10.1.1.0/25 -> 172.20.1.0/25",
+		"10.200.0.0/23 -> 172.20.2.0/23",
+	}
+	assert.Equal(t, expected, mappingStrings(plan.Mappings))
+	assert.False(t, plan.PreservesLayout)
+	assert.Empty(t, plan.Unmapped)
+
+}
+
+// TestPlanRenumberingShortfall renumbers allocations into an address space that is too small
+// Success Metric: The largest allocations are placed first, and the rest is reported as shortfall
+func TestPlanRenumberingShortfall(t *testing.T) {
+
+	allocations := parseAll(t, "10.1.0.0/25", "10.1.1.0/24", "10.2.0.0/24", "10.3.0.0/26")
+	newParent, _ := NewIPv4CIDR("172.20.0.0/23", false)
+
+	plan := PlanRenumbering(allocations, newParent)
+
+	expected := []string{
+		"10.1.1.0/24 -> 172.20.0.0/24",
+		"10.2.0.0/24 -> 172.20.1.0/24",
+	}
+	assert.Equal(t, expected, mappingStrings(plan.Mappings))
+	assert.Equal(t, []string{"10.1.0.0/25", "10.3.0.0/26"}, toStrings(plan.Unmapped))
+	assert.Equal(t, uint64(192), plan.Shortfall)
+
+}
@@ -0,0 +1,244 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"sort"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/utils"
+)
+
+// PrefixCount is the total count of the observations within a CIDR range
+// @field Prefix *IPv4CIDR: The CIDR range
+// @field Count uint64: Sum of the counts of the observations within the CIDR range
+type PrefixCount struct {
+	Prefix *IPv4CIDR
+	Count  uint64
+}
+
+// PrefixCounter accumulates (IP, count) observations, e.g. hits or bytes per source address, and reports their totals rolled up to any prefix length
+// @field counts map[uint32]uint64: The count of each observed IP address
+// @field total uint64: Sum of the counts of all observations
+type PrefixCounter struct {
+	counts map[uint32]uint64
+	total  uint64
+}
+
+// NewPrefixCounter instantiates a new, empty PrefixCounter object and returns it
+// @returns *PrefixCounter: Pointer to the new PrefixCounter object
+func NewPrefixCounter() *PrefixCounter {
+
+	return &PrefixCounter{
+		counts: make(map[uint32]uint64),
+	}
+
+}
+
+// Add records an observation for an IP address
+// @input ip uint32: The IP address in integer representation
+// @input count uint64: The count to add for the IP address
+func (c *PrefixCounter) Add(ip uint32, count uint64) {
+
+	c.counts[ip] += count
+	c.total += count
+
+}
+
+// AddString records an observation for an IP address in string representation
+// @input ip string: The IP address in format a.b.c.d
+// @input count uint64: The count to add for the IP address
+// @returns error: If the IP address is invalid, an error is returned
+func (c *PrefixCounter) AddString(ip string, count uint64) error {
+
+	value, err := utils.ParseIPUint32(ip)
+	if err != nil {
+		return err
+	}
+	c.Add(value, count)
+
+	return nil
+
+}
+
+// Total returns the sum of the counts of all observations
+// @returns uint64: Sum of the counts of all observations
+func (c *PrefixCounter) Total() uint64 {
+
+	return c.total
+
+}
+
+// Count returns the sum of the counts of the observations within a CIDR range
+// @input cidr *IPv4CIDR: The CIDR range
+// @returns uint64: Sum of the counts of the observations within the CIDR range
+func (c *PrefixCounter) Count(cidr *IPv4CIDR) uint64 {
+
+	total := uint64(0)
+	for ip, count := range c.counts {
+		if ip&cidr.netmask == cidr.ip {
+			total += count
+		}
+	}
+
+	return total
+
+}
+
+// RollUp totals the observations per CIDR range of a given mask, e.g. per /24
+// Only CIDR ranges with at least one observation are returned
+// @input mask uint8: The mask of the CIDR ranges to roll up to (0-32)
+// @returns []PrefixCount: The total of every observed CIDR range, highest count first, then in order of IP
+// @returns error: If the mask is larger than 32, an error is returned
+func (c *PrefixCounter) RollUp(mask uint8) ([]PrefixCount, error) {
+
+	if mask > consts.MaxBits {
+		return nil, utils.NewError(consts.InvalidMaskCode, consts.InvalidMaskError)
+	}
+
+	netmask := utils.GetNetmask(mask)
+	totals := make(map[uint32]uint64)
+	for ip, count := range c.counts {
+		totals[ip&netmask] += count
+	}
+
+	rollup := make([]PrefixCount, 0, len(totals))
+	for ip, count := range totals {
+		rollup = append(rollup, PrefixCount{
+			Prefix: fromIPAndMask(ip, mask),
+			Count:  count,
+		})
+	}
+
+	sort.Slice(rollup, func(i, j int) bool {
+		if rollup[i].Count != rollup[j].Count {
+			return rollup[i].Count > rollup[j].Count
+		}
+		return rollup[i].Prefix.ip < rollup[j].Prefix.ip
+	})
+
+	return rollup, nil
+
+}
+
+// Top returns the n CIDR ranges of a given mask with the highest totals, e.g. the top 10 /24s by hits
+// @input mask uint8: The mask of the CIDR ranges to roll up to (0-32)
+// @input n int: The maximum number of CIDR ranges to return
+// @returns []PrefixCount: The totals of the top CIDR ranges, highest count first
+// @returns error: If the mask is larger than 32, an error is returned
+func (c *PrefixCounter) Top(mask uint8, n int) ([]PrefixCount, error) {
+
+	rollup, err := c.RollUp(mask)
+	if err != nil {
+		return nil, err
+	}
+
+	if n < len(rollup) {
+		rollup = rollup[:n]
+	}
+
+	return rollup, nil
+
+}
+
+// HeavyHitters finds the smallest set of CIDR ranges whose traffic is at least a threshold, as used by DDoS mitigation and route optimization tooling
+// Observations are aggregated bottom-up: an IP address or CIDR range reaching the threshold is reported on its own, and the traffic of the others is rolled up into their parent CIDR range, until the whole address space is reached
+// The count of a reported CIDR range excludes the traffic of the more specific CIDR ranges already reported within it, and traffic that never reaches the threshold is not reported
+// @input threshold uint64: The minimum traffic of a reported CIDR range, must be at least 1
+// @returns []PrefixCount: The reported CIDR ranges with their traffic, in order of IP, then from largest to smallest
+func (c *PrefixCounter) HeavyHitters(threshold uint64) []PrefixCount {
+
+	if threshold == 0 {
+		threshold = 1
+	}
+
+	hitters := make([]PrefixCount, 0)
+	current := make(map[uint32]uint64, len(c.counts))
+	for ip, count := range c.counts {
+		current[ip] = count
+	}
+
+	for mask := int(consts.MaxBits); mask >= 0 && len(current) > 0; mask-- {
+
+		parents := make(map[uint32]uint64)
+		for ip, count := range current {
+
+			if count >= threshold {
+				hitters = append(hitters, PrefixCount{
+					Prefix: fromIPAndMask(ip, uint8(mask)),
+					Count:  count,
+				})
+				continue
+			}
+
+			// Cold traffic is aggregated upward into the parent CIDR range
+			if mask > 0 && count > 0 {
+				parents[ip&utils.GetNetmask(uint8(mask-1))] += count
+			}
+
+		}
+		current = parents
+
+	}
+
+	sort.Slice(hitters, func(i, j int) bool {
+		if hitters[i].Prefix.ip != hitters[j].Prefix.ip {
+			return hitters[i].Prefix.ip < hitters[j].Prefix.ip
+		}
+		return hitters[i].Prefix.mask < hitters[j].Prefix.mask
+	})
+
+	return hitters
+
+}
+
+// Histogram counts IP addresses per bucket CIDR range of a given mask, e.g. clients per /16
+// @input IPs []string: The IP addresses in format a.b.c.d. The same IP address may appear several times
+// @input mask uint8: The mask of the bucket CIDR ranges (0-32)
+// @input unique bool: If true, each distinct IP address is counted once per bucket (e.g. unique clients). If false, every occurrence is counted (e.g. requests)
+// @returns []PrefixCount: The count of every non-empty bucket, in order of IP
+// @returns error: If an IP address or the mask is invalid, an error is returned
+func Histogram(IPs []string, mask uint8, unique bool) ([]PrefixCount, error) {
+
+	if mask > consts.MaxBits {
+		return nil, utils.NewError(consts.InvalidMaskCode, consts.InvalidMaskError)
+	}
+
+	netmask := utils.GetNetmask(mask)
+	counts := make(map[uint32]uint64)
+	seen := make(map[uint32]struct{})
+
+	for _, IP := range IPs {
+
+		ip, err := utils.ParseIPUint32(IP)
+		if err != nil {
+			return nil, err
+		}
+
+		if unique {
+			if _, ok := seen[ip]; ok {
+				continue
+			}
+			seen[ip] = struct{}{}
+		}
+
+		counts[ip&netmask]++
+
+	}
+
+	histogram := make([]PrefixCount, 0, len(counts))
+	for bucket, count := range counts {
+		histogram = append(histogram, PrefixCount{
+			Prefix: fromIPAndMask(bucket, mask),
+			Count:  count,
+		})
+	}
+
+	sort.Slice(histogram, func(i, j int) bool {
+		return histogram[i].Prefix.ip < histogram[j].Prefix.ip
+	})
+
+	return histogram, nil
+
+}
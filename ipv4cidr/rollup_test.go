@@ -0,0 +1,176 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// prefixCountStrings converts a list of prefix counts to "prefix=count" strings for comparison in tests
+func prefixCountStrings(counts []PrefixCount) []string {
+
+	result := make([]string, 0, len(counts))
+	for _, count := range counts {
+		result = append(result, count.Prefix.ToString()+"="+strconv.FormatUint(count.Count, 10))
+	}
+
+	return result
+
+}
+
+// newTestCounter builds a PrefixCounter from a set of observations
+func newTestCounter(t *testing.T, observations map[string]uint64) *PrefixCounter {
+
+	counter := NewPrefixCounter()
+	for ip, count := range observations {
+		assert.Nil(t, counter.AddString(ip, count), "%s is a valid IP, it should be added.", ip)
+	}
+
+	return counter
+
+}
+
+// TestPrefixCounterRollUp rolls observations up to /24 and /16
+// Success Metric: Totals are summed per prefix and sorted by count, then IP
+func TestPrefixCounterRollUp(t *testing.T) {
+
+	counter := newTestCounter(t, map[string]uint64{
+		"10.0.0.1":    5,
+		"10.0.0.200":  10,
+		"10.0.1.1":    15,
+		"10.1.0.1":    1,
+		"192.168.1.1": 15,
+	})
+	counter.Add(167772161, 5) // 10.0.0.1 again
+
+	assert.Equal(t, uint64(51), counter.Total())
+
+	rollup, err := counter.RollUp(24)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"10.0.0.0/24=20", "10.0.1.0/24=15", "192.168.1.0/24=15", "10.1.0.0/24=1"}, prefixCountStrings(rollup))
+
+	rollup, err = counter.RollUp(16)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"10.0.0.0/16=35", "192.168.0.0/16=15", "10.1.0.0/16=1"}, prefixCountStrings(rollup))
+
+	rollup, err = counter.RollUp(0)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"0.0.0.0/0=51"}, prefixCountStrings(rollup))
+
+}
+
+// TestPrefixCounterTop returns the top prefixes and the count within a CIDR range
+// Success Metric: Only the requested number of prefixes is returned, and counts match the observations
+func TestPrefixCounterTop(t *testing.T) {
+
+	counter := newTestCounter(t, map[string]uint64{
+		"10.0.0.1":    5,
+		"10.0.1.1":    15,
+		"192.168.1.1": 7,
+	})
+
+	top, err := counter.Top(24, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"10.0.1.0/24=15", "192.168.1.0/24=7"}, prefixCountStrings(top))
+
+	top, err = counter.Top(24, 10)
+	assert.Nil(t, err)
+	assert.Len(t, top, 3)
+
+	assert.Equal(t, uint64(20), counter.Count(mustParse("10.0.0.0/16")))
+	assert.Equal(t, uint64(0), counter.Count(mustParse("172.16.0.0/12")))
+
+}
+
+// TestPrefixCounterErrors checks invalid IPs and masks
+// Success Metric: Errors are returned with the appropriate messages
+func TestPrefixCounterErrors(t *testing.T) {
+
+	counter := NewPrefixCounter()
+
+	err := counter.AddString("10.0.0", 1)
+	if assert.Error(t, err, "10.0.0 is an invalid IP. An error should be thrown.") {
+
+		assert.Equal(t, consts.InvalidIPv4Error, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidIPv4Error)
+
+	}
+
+	_, err = counter.RollUp(33)
+	if assert.Error(t, err, "33 is an invalid mask. An error should be thrown.") {
+
+		assert.Equal(t, consts.InvalidMaskError, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidMaskError)
+
+	}
+
+}
+
+// TestPrefixCounterHeavyHitters aggregates cold traffic upward until it reaches the threshold
+// Success Metric: Hot IPs are reported on their own, and cold traffic is reported at the smallest prefix reaching the threshold
+func TestPrefixCounterHeavyHitters(t *testing.T) {
+
+	counter := newTestCounter(t, map[string]uint64{
+		"10.0.0.1":    100,
+		"10.0.0.2":    30,
+		"10.0.0.3":    30,
+		"10.0.0.200":  40,
+		"10.0.1.1":    20,
+		"192.168.1.1": 5,
+	})
+
+	// 10.0.0.2 and 10.0.0.3 roll up into 10.0.0.2/31, and 10.0.0.200 and 10.0.1.1 roll up into 10.0.0.0/23
+	// 192.168.1.1 never reaches the threshold
+	hitters := counter.HeavyHitters(50)
+	assert.Equal(t, []string{"10.0.0.0/23=60", "10.0.0.1/32=100", "10.0.0.2/31=60"}, prefixCountStrings(hitters))
+
+	// With a threshold of 1, every observed IP is reported on its own
+	hitters = counter.HeavyHitters(0)
+	assert.Len(t, hitters, 6)
+
+	// A threshold above the total reports nothing
+	assert.Empty(t, counter.HeavyHitters(1000))
+
+	// Cold traffic reaching the threshold only for the whole address space is reported as /0
+	assert.Equal(t, []string{"0.0.0.0/0=225"}, prefixCountStrings(counter.HeavyHitters(225)))
+	assert.Equal(t, []string{"10.0.0.0/23=220"}, prefixCountStrings(counter.HeavyHitters(220)))
+
+}
+
+// TestHistogram counts IPs per /16 bucket, with and without duplicates
+// Success Metric: Buckets are returned in order of IP, with unique or total counts as requested
+func TestHistogram(t *testing.T) {
+
+	IPs := []string{"10.1.0.1", "10.1.200.7", "10.1.0.1", "192.168.1.1", "10.0.0.1"}
+
+	histogram, err := Histogram(IPs, 16, false)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"10.0.0.0/16=1", "10.1.0.0/16=3", "192.168.0.0/16=1"}, prefixCountStrings(histogram))
+
+	histogram, err = Histogram(IPs, 16, true)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"10.0.0.0/16=1", "10.1.0.0/16=2", "192.168.0.0/16=1"}, prefixCountStrings(histogram))
+
+	histogram, err = Histogram(nil, 16, true)
+	assert.Nil(t, err)
+	assert.Empty(t, histogram)
+
+	_, err = Histogram([]string{"10.1.0.1/32"}, 16, true)
+	if assert.Error(t, err, "10.1.0.1/32 is not an IP address. An error should be thrown.") {
+
+		assert.Equal(t, consts.InvalidIPv4Error, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidIPv4Error)
+
+	}
+
+	_, err = Histogram(IPs, 40, true)
+	if assert.Error(t, err, "40 is an invalid mask. An error should be thrown.") {
+
+		assert.Equal(t, consts.InvalidMaskError, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidMaskError)
+
+	}
+
+}
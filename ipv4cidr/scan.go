@@ -0,0 +1,36 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"fmt"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/utils"
+)
+
+// scanParser parses the tokens read by Scan. CIDR ranges must be standardized, as with NewIPv4CIDR(IP, false)
+var scanParser = NewParser(false)
+
+// Scan implements fmt.Scanner, so that CIDR ranges can be read from structured text with fmt.Sscanf and similar functions, e.g. fmt.Sscanf(line, "%v -> %v", &a, &b)
+// The CIDR range is read up to the first character that cannot be part of it, such as a space or a comma
+// @input state fmt.ScanState: The scanner state provided by the fmt package
+// @input verb rune: The formatting verb, either 'v' or 's'
+// @returns error: If the verb is not supported or the token is not a valid, standardized CIDR range, an error is returned
+func (i *IPv4CIDR) Scan(state fmt.ScanState, verb rune) error {
+
+	if verb != 'v' && verb != 's' {
+		return utils.NewError(consts.InvalidInputCode, consts.UnsupportedScanVerbError)
+	}
+
+	token, err := state.Token(true, func(r rune) bool {
+		return (r >= '0' && r <= '9') || r == '.' || r == '/'
+	})
+	if err != nil {
+		return err
+	}
+
+	return scanParser.ParseInto(i, string(token))
+
+}
@@ -0,0 +1,64 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestScan reads CIDR ranges from structured text with fmt.Sscanf
+// Success Metric: The CIDR ranges are parsed from the text
+func TestScan(t *testing.T) {
+
+	var from, to IPv4CIDR
+	n, err := fmt.Sscanf("10.0.0.0/24 -> 192.168.1.1", "%v -> %v", &from, &to)
+
+	assert.Nil(t, err, "Both CIDR ranges are valid")
+	assert.Equal(t, 2, n)
+	assert.Equal(t, "10.0.0.0/24", from.ToString())
+	assert.Equal(t, "192.168.1.1/32", to.ToString())
+
+	var route IPv4CIDR
+	var gateway string
+	n, err = fmt.Sscan("  172.16.0.0/12 via-gw", &route, &gateway)
+	assert.Nil(t, err, "Leading spaces should be skipped")
+	assert.Equal(t, 2, n)
+	assert.Equal(t, "172.16.0.0/12", route.ToString())
+	assert.Equal(t, "via-gw", gateway)
+
+}
+
+// TestScanInvalidInput reads invalid CIDR ranges and uses an unsupported verb
+// Success Metric: Errors are returned with the appropriate messages
+func TestScanInvalidInput(t *testing.T) {
+
+	var cidr IPv4CIDR
+
+	_, err := fmt.Sscanf("10.0.0.1/24", "%v", &cidr)
+	if assert.Error(t, err, "10.0.0.1/24 is not standardized. An error should be thrown.") {
+
+		assert.Equal(t, consts.NonStandardizedIPError, err.Error(), "Error thrown should be: \"%s\"", consts.NonStandardizedIPError)
+
+	}
+
+	_, err = fmt.Sscanf("10.0.0.256", "%v", &cidr)
+	if assert.Error(t, err, "10.0.0.256 is invalid. An error should be thrown.") {
+
+		assert.Equal(t, consts.InvalidIPv4CIDRError, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidIPv4CIDRError)
+
+	}
+
+	_, err = fmt.Sscanf("10.0.0.0/24", "%d", &cidr)
+	if assert.Error(t, err, "%%d is not supported. An error should be thrown.") {
+
+		assert.Equal(t, consts.UnsupportedScanVerbError, err.Error(), "Error thrown should be: \"%s\"", consts.UnsupportedScanVerbError)
+
+	}
+
+}
@@ -0,0 +1,228 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"sort"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/utils"
+)
+
+// Set is a list of CIDR ranges, e.g. the allocated subnets of an address space, which may overlap
+// The functions of this package taking a []*IPv4CIDR also accept a Set, since it has the same underlying type
+type Set []*IPv4CIDR
+
+// SubnetCoverage holds how much of a child subnet is covered by a list of CIDR ranges
+// @field Subnet *IPv4CIDR: The child subnet
+// @field Covered uint64: Number of IP addresses of the child subnet that are covered
+// @field Coverage float64: Fraction (0-1) of the child subnet that is covered
+type SubnetCoverage struct {
+	Subnet   *IPv4CIDR
+	Covered  uint64
+	Coverage float64
+}
+
+// normalize returns the blocks of a list of CIDR ranges that are not contained within any other block of the list, sorted by IP
+// Two CIDR ranges are either disjoint or one contains the other, so the result covers exactly the union of the input without any overlap
+// @input cidrs []*IPv4CIDR: The list of CIDR ranges
+// @returns []*IPv4CIDR: The sorted, non-overlapping list of CIDR ranges
+func normalize(cidrs []*IPv4CIDR) []*IPv4CIDR {
+
+	sorted := make([]*IPv4CIDR, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		if cidr != nil {
+			sorted = append(sorted, cidr)
+		}
+	}
+
+	// Sort by IP, and for the same IP put the larger block (smaller mask) first
+	sort.Slice(sorted, func(a, b int) bool {
+		return sorted[a].Compare(sorted[b]) < 0
+	})
+
+	result := make([]*IPv4CIDR, 0, len(sorted))
+	for _, cidr := range sorted {
+
+		// If the block starts within the last kept block, it is contained in it and can be dropped
+		if len(result) > 0 && cidr.ip <= result[len(result)-1].lastIP() {
+			continue
+		}
+		result = append(result, cidr)
+
+	}
+
+	return result
+
+}
+
+// TotalAddresses counts the number of distinct IP addresses covered by a list of CIDR ranges
+// Overlapping ranges are only counted once, and the count is 64-bit so that 0.0.0.0/0 is handled correctly
+// @input cidrs []*IPv4CIDR: The list of CIDR ranges
+// @returns uint64: Number of IP addresses in the union of the CIDR ranges
+func TotalAddresses(cidrs []*IPv4CIDR) uint64 {
+
+	total := uint64(0)
+	for _, cidr := range normalize(cidrs) {
+		total += cidr.size()
+	}
+
+	return total
+
+}
+
+// UsableAddresses counts the number of assignable host addresses covered by a list of CIDR ranges, using DefaultHostPolicy
+// Overlapping ranges are only counted once. Each outermost block is treated as a subnet, so its network and broadcast addresses are excluded (except for /31 and /32)
+// @input cidrs []*IPv4CIDR: The list of CIDR ranges
+// @returns uint64: Number of usable host addresses in the union of the CIDR ranges
+func UsableAddresses(cidrs []*IPv4CIDR) uint64 {
+
+	return DefaultHostPolicy.UsableAddresses(cidrs)
+
+}
+
+// TotalAddresses counts the number of distinct IP addresses covered by the set
+// @returns uint64: Number of IP addresses in the union of the CIDR ranges of the set
+func (s Set) TotalAddresses() uint64 {
+
+	return TotalAddresses(s)
+
+}
+
+// UsableAddresses counts the number of assignable host addresses covered by the set, using DefaultHostPolicy
+// @returns uint64: Number of usable host addresses in the union of the CIDR ranges of the set
+func (s Set) UsableAddresses() uint64 {
+
+	return UsableAddresses(s)
+
+}
+
+// intersect returns the overlap of two CIDR ranges
+// Two CIDR ranges are either disjoint or one contains the other, so the overlap is either nil or the smaller block
+// @input a *IPv4CIDR: The first CIDR range
+// @input b *IPv4CIDR: The second CIDR range
+// @returns *IPv4CIDR: The overlapping block, or nil if the ranges are disjoint
+func intersect(a *IPv4CIDR, b *IPv4CIDR) *IPv4CIDR {
+
+	if a.ip > b.lastIP() || b.ip > a.lastIP() {
+		return nil
+	}
+
+	if a.mask >= b.mask {
+		return a
+	}
+
+	return b
+
+}
+
+// coveredAddresses counts the number of distinct IP addresses of the parent that are covered by a list of CIDR ranges
+// @input parent *IPv4CIDR: The CIDR range to count within
+// @input cidrs []*IPv4CIDR: The list of CIDR ranges
+// @returns uint64: Number of IP addresses of the parent covered by the list
+func coveredAddresses(parent *IPv4CIDR, cidrs []*IPv4CIDR) uint64 {
+
+	overlaps := make([]*IPv4CIDR, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		if cidr == nil {
+			continue
+		}
+		if overlap := intersect(parent, cidr); overlap != nil {
+			overlaps = append(overlaps, overlap)
+		}
+	}
+
+	return TotalAddresses(overlaps)
+
+}
+
+// ClampTo returns the portions of a list of CIDR ranges that fall within a parent CIDR range, e.g. to scope a global feed down to an owned address space
+// Ranges containing the parent are clamped to the parent, ranges within it are kept, and ranges outside it are dropped
+// @input parent *IPv4CIDR: The CIDR range to clamp to
+// @input cidrs []*IPv4CIDR: The list of CIDR ranges
+// @returns []*IPv4CIDR: The clamped CIDR ranges, sorted by IP and without any range contained in another
+func ClampTo(parent *IPv4CIDR, cidrs []*IPv4CIDR) []*IPv4CIDR {
+
+	clamped := make([]*IPv4CIDR, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		if cidr == nil {
+			continue
+		}
+		if overlap := intersect(parent, cidr); overlap != nil {
+			clamped = append(clamped, overlap)
+		}
+	}
+
+	return normalize(clamped)
+
+}
+
+// Coverage calculates the fraction of a parent CIDR range that is covered by a list of CIDR ranges
+// Overlapping ranges are only counted once, and the parts of ranges outside the parent are ignored
+// @input parent *IPv4CIDR: The address space to measure
+// @input cidrs []*IPv4CIDR: The list of CIDR ranges, e.g. the allocated subnets
+// @returns float64: Fraction (0-1) of the parent covered by the list
+func Coverage(parent *IPv4CIDR, cidrs []*IPv4CIDR) float64 {
+
+	return float64(coveredAddresses(parent, cidrs)) / float64(parent.size())
+
+}
+
+// CoverageBreakdown calculates the coverage of each child subnet of a given mask within the parent CIDR range
+// @input parent *IPv4CIDR: The address space to measure
+// @input cidrs []*IPv4CIDR: The list of CIDR ranges, e.g. the allocated subnets
+// @input childMask uint8: The mask of the child subnets to report on, e.g. 24 for a breakdown per /24
+// @returns []SubnetCoverage: The coverage of every child subnet, in order of IP
+// @returns error: If the child mask is smaller than the parent mask, larger than 32, or more than consts.MaxBreakdownBits longer than the parent mask, an error is returned
+func CoverageBreakdown(parent *IPv4CIDR, cidrs []*IPv4CIDR, childMask uint8) ([]SubnetCoverage, error) {
+
+	if childMask < parent.mask || childMask > consts.MaxBits {
+		return nil, utils.NewError(consts.InvalidMaskCode, consts.InvalidChildMaskError)
+	}
+
+	// One entry is allocated per child subnet, so the number of children is bounded
+	if childMask-parent.mask > consts.MaxBreakdownBits {
+		return nil, utils.NewError(consts.InvalidMaskCode, consts.BreakdownTooLargeError)
+	}
+
+	childCount := uint64(1) << (childMask - parent.mask)
+	childSize := utils.GetCIDRRangeLength64(childMask)
+
+	breakdown := make([]SubnetCoverage, 0, childCount)
+	for n := uint64(0); n < childCount; n++ {
+
+		child := fromIPAndMask(parent.ip+uint32(n*childSize), childMask)
+		covered := coveredAddresses(child, cidrs)
+
+		breakdown = append(breakdown, SubnetCoverage{
+			Subnet:   child,
+			Covered:  covered,
+			Coverage: float64(covered) / float64(childSize),
+		})
+
+	}
+
+	return breakdown, nil
+
+}
+
+// Coverage calculates the fraction of a parent CIDR range that is covered by the set
+// @input parent *IPv4CIDR: The address space to measure
+// @returns float64: Fraction (0-1) of the parent covered by the set
+func (s Set) Coverage(parent *IPv4CIDR) float64 {
+
+	return Coverage(parent, s)
+
+}
+
+// CoverageBreakdown calculates the coverage by the set of each child subnet of a given mask within the parent CIDR range
+// @input parent *IPv4CIDR: The address space to measure
+// @input childMask uint8: The mask of the child subnets to report on, e.g. 24 for a breakdown per /24
+// @returns []SubnetCoverage: The coverage of every child subnet, in order of IP
+// @returns error: If the child mask is smaller than the parent mask, larger than 32, or more than consts.MaxBreakdownBits longer than the parent mask, an error is returned
+func (s Set) CoverageBreakdown(parent *IPv4CIDR, childMask uint8) ([]SubnetCoverage, error) {
+
+	return CoverageBreakdown(parent, s, childMask)
+
+}
@@ -0,0 +1,167 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"testing"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// parseAll is a test helper that converts a list of CIDR strings into IPv4CIDR objects
+func parseAll(t *testing.T, inputs ...string) []*IPv4CIDR {
+
+	cidrs := make([]*IPv4CIDR, 0, len(inputs))
+	for _, input := range inputs {
+		cidr, err := NewIPv4CIDR(input, false)
+		if err != nil {
+			t.Fatalf("Could not parse %s: %s", input, err)
+		}
+		cidrs = append(cidrs, cidr)
+	}
+
+	return cidrs
+
+}
+
+// TestTotalAddresses counts the addresses in a list of overlapping and disjoint CIDR ranges
+// Success Metric: Overlapping addresses are only counted once
+func TestTotalAddresses(t *testing.T) {
+
+	cidrs := parseAll(t, "10.10.0.0/24", "10.10.0.128/25", "10.10.0.5", "10.20.0.0/30")
+
+	assert.Equal(t, uint64(260), TotalAddresses(cidrs), "10.10.0.0/24 covers the /25 and /32, so total should be 256 + 4")
+	assert.Equal(t, uint64(0), TotalAddresses(nil), "An empty list contains no addresses")
+
+}
+
+// TestTotalAddressesEntireSpace counts the addresses in 0.0.0.0/0
+// Success Metric: The count is 2^32 and does not overflow
+func TestTotalAddressesEntireSpace(t *testing.T) {
+
+	cidrs := parseAll(t, "0.0.0.0/0", "10.0.0.0/8")
+
+	assert.Equal(t, uint64(1)<<32, TotalAddresses(cidrs), "0.0.0.0/0 contains 2^32 addresses")
+
+}
+
+// TestUsableAddresses counts the usable host addresses in a list of CIDR ranges
+// Success Metric: Network and broadcast addresses are excluded per outermost block, with /31 and /32 special-cased
+func TestUsableAddresses(t *testing.T) {
+
+	cidrs := parseAll(t, "10.10.0.0/24", "10.10.0.0/26", "10.20.0.0/31", "10.30.0.1")
+
+	assert.Equal(t, uint64(254+2+1), UsableAddresses(cidrs))
+
+}
+
+// TestSetAddresses counts the total and usable addresses of a Set
+// Success Metric: The counts are the same as those of the list of CIDR ranges, including for 0.0.0.0/0
+func TestSetAddresses(t *testing.T) {
+
+	set := Set(parseAll(t, "10.10.0.0/24", "10.10.0.0/26", "10.20.0.0/31", "10.30.0.1"))
+
+	assert.Equal(t, uint64(256+2+1), set.TotalAddresses())
+	assert.Equal(t, uint64(254+2+1), set.UsableAddresses())
+	assert.Equal(t, uint64(1)<<32, Set(parseAll(t, "0.0.0.0/0", "10.0.0.0/8")).TotalAddresses())
+	assert.Equal(t, uint64(0), Set(nil).TotalAddresses(), "An empty set contains no addresses")
+
+}
+
+// TestCoverage calculates how much of a parent range is covered by a list of CIDR ranges
+// Success Metric: Overlaps are counted once and parts outside the parent are ignored
+func TestCoverage(t *testing.T) {
+
+	parent, _ := NewIPv4CIDR("10.10.0.0/24", false)
+	cidrs := parseAll(t, "10.10.0.0/26", "10.10.0.0/27", "10.10.0.128/25", "10.20.0.0/16")
+
+	assert.Equal(t, 0.75, Coverage(parent, cidrs))
+
+	supernet := parseAll(t, "10.0.0.0/8")
+	assert.Equal(t, 1.0, Coverage(parent, supernet), "A block containing the parent covers it entirely")
+
+}
+
+// TestSetCoverage calculates the coverage of a parent range by a Set, in total and per child subnet
+// Success Metric: The results are the same as those for the list of CIDR ranges
+func TestSetCoverage(t *testing.T) {
+
+	parent, _ := NewIPv4CIDR("10.10.0.0/24", false)
+	set := Set(parseAll(t, "10.10.0.0/26", "10.10.0.0/27", "10.10.0.128/25", "10.20.0.0/16"))
+
+	assert.Equal(t, 0.75, set.Coverage(parent))
+
+	breakdown, err := set.CoverageBreakdown(parent, 25)
+	assert.Nil(t, err, "25 is a valid child mask for a /24")
+	if assert.Len(t, breakdown, 2) {
+		assert.Equal(t, 0.5, breakdown[0].Coverage)
+		assert.Equal(t, 1.0, breakdown[1].Coverage)
+	}
+
+	_, err = set.CoverageBreakdown(parent, 33)
+	assert.Error(t, err, "/33 is not a valid child mask. An error should be thrown.")
+
+}
+
+// TestCoverageBreakdown calculates the coverage of each child subnet of a parent range
+// Success Metric: One entry per child subnet with the correct coverage
+func TestCoverageBreakdown(t *testing.T) {
+
+	parent, _ := NewIPv4CIDR("10.10.0.0/24", false)
+	cidrs := parseAll(t, "10.10.0.0/26", "10.10.0.192/27")
+
+	breakdown, err := CoverageBreakdown(parent, cidrs, 26)
+	assert.Nil(t, err, "26 is a valid child mask for a /24")
+
+	if assert.Len(t, breakdown, 4) {
+
+		assert.Equal(t, "10.10.0.0/26", breakdown[0].Subnet.ToString())
+		assert.Equal(t, uint64(64), breakdown[0].Covered)
+		assert.Equal(t, 1.0, breakdown[0].Coverage)
+
+		assert.Equal(t, "10.10.0.64/26", breakdown[1].Subnet.ToString())
+		assert.Equal(t, 0.0, breakdown[1].Coverage)
+
+		assert.Equal(t, "10.10.0.192/26", breakdown[3].Subnet.ToString())
+		assert.Equal(t, uint64(32), breakdown[3].Covered)
+		assert.Equal(t, 0.5, breakdown[3].Coverage)
+
+	}
+
+	_, err = CoverageBreakdown(parent, cidrs, 20)
+	if assert.Error(t, err, "A /20 is larger than the parent. An error should be thrown.") {
+
+		assert.Equal(t, consts.InvalidChildMaskError, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidChildMaskError)
+
+	}
+
+	wide, _ := NewIPv4CIDR("10.0.0.0/8", false)
+	breakdown, err = CoverageBreakdown(wide, cidrs, 24)
+	assert.Nil(t, err, "A breakdown of a /8 per /24 has 65536 children")
+	assert.Len(t, breakdown, 65536)
+
+	_, err = CoverageBreakdown(wide, cidrs, 32)
+	if assert.Error(t, err, "A breakdown of a /8 per /32 has too many children. An error should be thrown.") {
+
+		assert.Equal(t, consts.BreakdownTooLargeError, err.Error(), "Error thrown should be: \"%s\"", consts.BreakdownTooLargeError)
+
+	}
+
+}
+
+// TestClampTo clamps a list of CIDR ranges to a parent range
+// Success Metric: Only the portions within the parent are returned, sorted and without duplicates
+func TestClampTo(t *testing.T) {
+
+	parent, _ := NewIPv4CIDR("10.10.0.0/16", false)
+	cidrs := parseAll(t, "10.10.128.0/17", "10.0.0.0/8", "192.168.0.0/16", "10.10.1.0/24", "10.11.0.0/16")
+
+	assert.Equal(t, []string{"10.10.0.0/16"}, toStrings(ClampTo(parent, cidrs)), "10.0.0.0/8 contains the parent, so the whole parent is covered")
+
+	cidrs = parseAll(t, "10.10.128.0/17", "192.168.0.0/16", "10.10.1.0/24", "10.11.0.0/16")
+	assert.Equal(t, []string{"10.10.1.0/24", "10.10.128.0/17"}, toStrings(ClampTo(parent, cidrs)))
+
+}
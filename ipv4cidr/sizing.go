@@ -0,0 +1,70 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"math"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/utils"
+)
+
+// SizingRequirement describes the expected use of a subnet over a planning horizon
+// @field Hosts uint64: The number of hosts in the subnet today
+// @field GrowthRate float64: The expected growth of the number of hosts per period, e.g. 0.25 for 25% per year
+// @field Periods uint: The planning horizon, in periods of the growth rate, e.g. 3 for 3 years
+// @field ReservedAddresses uint64: The number of addresses of the subnet reserved by the platform, e.g. consts.AzureReservedAddresses
+type SizingRequirement struct {
+	Hosts             uint64
+	GrowthRate        float64
+	Periods           uint
+	ReservedAddresses uint64
+}
+
+// SizingRecommendation is the smallest subnet size satisfying a sizing requirement
+// @field Mask uint8: The recommended prefix length
+// @field ProjectedHosts uint64: The number of hosts expected at the end of the planning horizon
+// @field Capacity uint64: The number of hosts a subnet of the recommended size can hold, excluding the reserved addresses
+type SizingRecommendation struct {
+	Mask           uint8
+	ProjectedHosts uint64
+	Capacity       uint64
+}
+
+// RecommendPrefixLength recommends the longest prefix length, i.e. the smallest subnet, that can hold the projected number of hosts of a requirement
+// The number of hosts is compounded by the growth rate over the planning horizon and rounded up, then the reserved addresses are added
+// @input requirement SizingRequirement: The expected use of the subnet
+// @returns *SizingRecommendation: The recommended prefix length and the capacity of a subnet of that size
+// @returns error: If the growth rate is not greater than -1 or the projected number of addresses exceeds the IPv4 address space, an error is returned
+func RecommendPrefixLength(requirement SizingRequirement) (*SizingRecommendation, error) {
+
+	if math.IsNaN(requirement.GrowthRate) || math.IsInf(requirement.GrowthRate, 0) || requirement.GrowthRate <= -1 {
+		return nil, utils.NewError(consts.InvalidInputCode, consts.InvalidGrowthRateError)
+	}
+
+	addressSpace := utils.GetCIDRRangeLength64(0)
+
+	// The projection is rounded to 6 decimals before rounding up, so that floating-point errors (e.g. 100 * 1.1 = 110.00000000000001) do not add a host
+	projected := float64(requirement.Hosts) * math.Pow(1+requirement.GrowthRate, float64(requirement.Periods))
+	projected = math.Ceil(math.Round(projected*1e6) / 1e6)
+
+	// Projections beyond the address space are rejected before converting back to an integer, so that they cannot overflow
+	if projected > float64(addressSpace) || requirement.ReservedAddresses > addressSpace-uint64(projected) {
+		return nil, utils.NewError(consts.OutOfRangeCode, consts.SizeExceedsAddressSpaceError)
+	}
+
+	required := uint64(projected) + requirement.ReservedAddresses
+
+	mask := consts.MaxBits
+	for mask > 0 && utils.GetCIDRRangeLength64(mask) < required {
+		mask--
+	}
+
+	return &SizingRecommendation{
+		Mask:           mask,
+		ProjectedHosts: uint64(projected),
+		Capacity:       utils.GetCIDRRangeLength64(mask) - requirement.ReservedAddresses,
+	}, nil
+
+}
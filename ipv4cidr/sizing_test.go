@@ -0,0 +1,87 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"math"
+	"testing"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRecommendPrefixLength recommends subnet sizes for various growth scenarios
+// Success Metric: The smallest subnet holding the projected hosts and the reserved addresses is recommended
+func TestRecommendPrefixLength(t *testing.T) {
+
+	for _, test := range []struct {
+		requirement SizingRequirement
+		expected    SizingRecommendation
+	}{
+		{
+			SizingRequirement{Hosts: 100, GrowthRate: 0.25, Periods: 2, ReservedAddresses: consts.AzureReservedAddresses},
+			SizingRecommendation{Mask: 24, ProjectedHosts: 157, Capacity: 251},
+		},
+		{
+			SizingRequirement{Hosts: 100, GrowthRate: 0.1, Periods: 1},
+			SizingRecommendation{Mask: 25, ProjectedHosts: 110, Capacity: 128},
+		},
+		{
+			SizingRequirement{Hosts: 123, ReservedAddresses: consts.AzureReservedAddresses},
+			SizingRecommendation{Mask: 25, ProjectedHosts: 123, Capacity: 123},
+		},
+		{
+			SizingRequirement{Hosts: 1000, GrowthRate: -0.5, Periods: 1, ReservedAddresses: consts.NetworkAndBroadcastReservedAddresses},
+			SizingRecommendation{Mask: 23, ProjectedHosts: 500, Capacity: 510},
+		},
+		{
+			SizingRequirement{},
+			SizingRecommendation{Mask: 32, ProjectedHosts: 0, Capacity: 1},
+		},
+		{
+			SizingRequirement{Hosts: uint64(1) << 32},
+			SizingRecommendation{Mask: 0, ProjectedHosts: uint64(1) << 32, Capacity: uint64(1) << 32},
+		},
+	} {
+
+		recommendation, err := RecommendPrefixLength(test.requirement)
+		if assert.Nil(t, err) {
+			assert.Equal(t, test.expected, *recommendation, "Recommendation for %+v", test.requirement)
+		}
+
+	}
+
+}
+
+// TestRecommendPrefixLengthInvalidInput recommends subnet sizes for invalid growth rates and projections beyond the address space
+// Success Metric: Errors are returned with the appropriate messages
+func TestRecommendPrefixLengthInvalidInput(t *testing.T) {
+
+	for _, rate := range []float64{-1, -2, math.NaN(), math.Inf(1)} {
+
+		_, err := RecommendPrefixLength(SizingRequirement{Hosts: 10, GrowthRate: rate, Periods: 1})
+		if assert.Error(t, err, "%f is not a valid growth rate. An error should be thrown.", rate) {
+
+			assert.Equal(t, consts.InvalidGrowthRateError, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidGrowthRateError)
+
+		}
+
+	}
+
+	for _, requirement := range []SizingRequirement{
+		{Hosts: uint64(1) << 32, ReservedAddresses: 1},
+		{Hosts: 1 << 20, GrowthRate: 1, Periods: 20},
+	} {
+
+		_, err := RecommendPrefixLength(requirement)
+		if assert.Error(t, err, "%+v exceeds the address space. An error should be thrown.", requirement) {
+
+			assert.Equal(t, consts.SizeExceedsAddressSpaceError, err.Error(), "Error thrown should be: \"%s\"", consts.SizeExceedsAddressSpaceError)
+
+		}
+
+	}
+
+}
@@ -0,0 +1,332 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"math/bits"
+	"sort"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/utils"
+)
+
+// arrayContainerMaxSize is the number of addresses above which a container switches from a sorted array (2 bytes per address) to a bitmap (8 KiB)
+const arrayContainerMaxSize = 4096
+
+// bitmapContainerWords is the number of 64-bit words needed for a bitmap of the 65536 addresses of a container
+const bitmapContainerWords = 1024
+
+// ipContainer holds the low 16 bits of the addresses of a SparseIPSet that share the same high 16 bits
+// Sparse containers are stored as a sorted array, dense containers as a bitmap, as in roaring bitmaps
+// @field array []uint16: The sorted low bits of the addresses, if the container is sparse
+// @field bitmap []uint64: The bitmap of the low bits of the addresses, if the container is dense
+// @field cardinality int: Number of addresses in the container
+type ipContainer struct {
+	array       []uint16
+	bitmap      []uint64
+	cardinality int
+}
+
+// SparseIPSet is a set of individual IP addresses, optimized for addresses scattered across the whole IPv4 space
+// Addresses are grouped by their high 16 bits, and each group is stored as a sorted array or a bitmap depending on its density, so memory usage stays proportional to the number of addresses
+// @field containers map[uint16]*ipContainer: The containers, keyed by the high 16 bits of their addresses
+type SparseIPSet struct {
+	containers map[uint16]*ipContainer
+}
+
+// NewSparseIPSet instantiates a new, empty SparseIPSet object and returns it
+// @returns *SparseIPSet: Pointer to the new SparseIPSet object
+func NewSparseIPSet() *SparseIPSet {
+
+	return &SparseIPSet{
+		containers: make(map[uint16]*ipContainer),
+	}
+
+}
+
+// Add adds an IP address to the set
+// @input ip uint32: The IP address in integer representation
+func (s *SparseIPSet) Add(ip uint32) {
+
+	high, low := uint16(ip>>16), uint16(ip)
+
+	c, ok := s.containers[high]
+	if !ok {
+		c = &ipContainer{}
+		s.containers[high] = c
+	}
+	c.add(low)
+
+}
+
+// AddString adds an IP address in string representation to the set
+// @input ip string: The IP address in format a.b.c.d
+// @returns error: If the IP address is invalid, an error is returned
+func (s *SparseIPSet) AddString(ip string) error {
+
+	value, err := utils.ParseIPUint32(ip)
+	if err != nil {
+		return err
+	}
+	s.Add(value)
+
+	return nil
+
+}
+
+// Remove removes an IP address from the set, if present
+// @input ip uint32: The IP address in integer representation
+func (s *SparseIPSet) Remove(ip uint32) {
+
+	high, low := uint16(ip>>16), uint16(ip)
+
+	c, ok := s.containers[high]
+	if !ok {
+		return
+	}
+	c.remove(low)
+
+	if c.cardinality == 0 {
+		delete(s.containers, high)
+	}
+
+}
+
+// Contains checks if an IP address is in the set
+// @input ip uint32: The IP address in integer representation
+// @returns bool: True if the IP address is in the set
+func (s *SparseIPSet) Contains(ip uint32) bool {
+
+	c, ok := s.containers[uint16(ip>>16)]
+
+	return ok && c.contains(uint16(ip))
+
+}
+
+// Cardinality returns the number of IP addresses in the set
+// @returns uint64: Number of IP addresses in the set
+func (s *SparseIPSet) Cardinality() uint64 {
+
+	total := uint64(0)
+	for _, c := range s.containers {
+		total += uint64(c.cardinality)
+	}
+
+	return total
+
+}
+
+// Union returns a new set with the IP addresses of both sets
+// @input other *SparseIPSet: The other set
+// @returns *SparseIPSet: Pointer to the new set
+func (s *SparseIPSet) Union(other *SparseIPSet) *SparseIPSet {
+
+	result := NewSparseIPSet()
+	for high, c := range s.containers {
+		result.containers[high] = c.clone()
+	}
+
+	for high, c := range other.containers {
+		if existing, ok := result.containers[high]; ok {
+			result.containers[high] = existing.union(c)
+		} else {
+			result.containers[high] = c.clone()
+		}
+	}
+
+	return result
+
+}
+
+// CIDRs returns the minimal list of CIDR ranges covering exactly the IP addresses of the set
+// @returns []*IPv4CIDR: The CIDR ranges, in order of IP
+func (s *SparseIPSet) CIDRs() []*IPv4CIDR {
+
+	highs := make([]int, 0, len(s.containers))
+	for high := range s.containers {
+		highs = append(highs, int(high))
+	}
+	sort.Ints(highs)
+
+	// Merge consecutive addresses into ranges
+	ranges := make([]ipRange, 0)
+	for _, high := range highs {
+		s.containers[uint16(high)].each(func(low uint16) {
+
+			ip := uint64(high)<<16 | uint64(low)
+			if len(ranges) > 0 && ranges[len(ranges)-1].end+1 == ip {
+				ranges[len(ranges)-1].end = ip
+				return
+			}
+			ranges = append(ranges, ipRange{start: ip, end: ip})
+
+		})
+	}
+
+	return rangesToCIDRs(ranges)
+
+}
+
+// add adds the low bits of an address to the container, switching to a bitmap once the array is full
+// @input low uint16: The low 16 bits of the address
+func (c *ipContainer) add(low uint16) {
+
+	if c.bitmap != nil {
+		if c.bitmap[low/64]&(1<<(low%64)) == 0 {
+			c.bitmap[low/64] |= 1 << (low % 64)
+			c.cardinality++
+		}
+		return
+	}
+
+	position := sort.Search(len(c.array), func(n int) bool { return c.array[n] >= low })
+	if position < len(c.array) && c.array[position] == low {
+		return
+	}
+
+	c.array = append(c.array, 0)
+	copy(c.array[position+1:], c.array[position:])
+	c.array[position] = low
+	c.cardinality++
+
+	if c.cardinality > arrayContainerMaxSize {
+		c.toBitmap()
+	}
+
+}
+
+// remove removes the low bits of an address from the container, switching back to an array once sparse enough
+// @input low uint16: The low 16 bits of the address
+func (c *ipContainer) remove(low uint16) {
+
+	if c.bitmap != nil {
+		if c.bitmap[low/64]&(1<<(low%64)) != 0 {
+			c.bitmap[low/64] &^= 1 << (low % 64)
+			c.cardinality--
+		}
+		if c.cardinality <= arrayContainerMaxSize {
+			c.toArray()
+		}
+		return
+	}
+
+	position := sort.Search(len(c.array), func(n int) bool { return c.array[n] >= low })
+	if position < len(c.array) && c.array[position] == low {
+		c.array = append(c.array[:position], c.array[position+1:]...)
+		c.cardinality--
+	}
+
+}
+
+// contains checks if the low bits of an address are in the container
+// @input low uint16: The low 16 bits of the address
+// @returns bool: True if the address is in the container
+func (c *ipContainer) contains(low uint16) bool {
+
+	if c.bitmap != nil {
+		return c.bitmap[low/64]&(1<<(low%64)) != 0
+	}
+
+	position := sort.Search(len(c.array), func(n int) bool { return c.array[n] >= low })
+
+	return position < len(c.array) && c.array[position] == low
+
+}
+
+// each calls a function for every address in the container, in ascending order
+// @input f func(uint16): The function to call with the low 16 bits of each address
+func (c *ipContainer) each(f func(uint16)) {
+
+	if c.bitmap == nil {
+		for _, low := range c.array {
+			f(low)
+		}
+		return
+	}
+
+	for word, value := range c.bitmap {
+		for value != 0 {
+			bit := bits.TrailingZeros64(value)
+			f(uint16(word*64 + bit))
+			value &= value - 1
+		}
+	}
+
+}
+
+// clone returns a deep copy of the container
+// @returns *ipContainer: The copy
+func (c *ipContainer) clone() *ipContainer {
+
+	copied := &ipContainer{cardinality: c.cardinality}
+	if c.bitmap != nil {
+		copied.bitmap = append([]uint64(nil), c.bitmap...)
+	} else {
+		copied.array = append([]uint16(nil), c.array...)
+	}
+
+	return copied
+
+}
+
+// union returns a new container with the addresses of both containers, switching back to an array if the result is sparse enough
+// @input other *ipContainer: The other container
+// @returns *ipContainer: The new container
+func (c *ipContainer) union(other *ipContainer) *ipContainer {
+
+	result := c.clone()
+	if result.bitmap == nil && other.bitmap == nil && result.cardinality+other.cardinality <= arrayContainerMaxSize {
+		other.each(result.add)
+		return result
+	}
+
+	// At least one side is dense, so compute the union as a bitmap
+	if result.bitmap == nil {
+		result.toBitmap()
+	}
+	if other.bitmap != nil {
+		for word := range result.bitmap {
+			result.bitmap[word] |= other.bitmap[word]
+		}
+	} else {
+		for _, low := range other.array {
+			result.bitmap[low/64] |= 1 << (low % 64)
+		}
+	}
+
+	result.cardinality = 0
+	for _, value := range result.bitmap {
+		result.cardinality += bits.OnesCount64(value)
+	}
+
+	// Overlapping arrays can exceed the threshold together but not once merged
+	if result.cardinality <= arrayContainerMaxSize {
+		result.toArray()
+	}
+
+	return result
+
+}
+
+// toBitmap converts the container from a sorted array to a bitmap
+func (c *ipContainer) toBitmap() {
+
+	c.bitmap = make([]uint64, bitmapContainerWords)
+	for _, low := range c.array {
+		c.bitmap[low/64] |= 1 << (low % 64)
+	}
+	c.array = nil
+
+}
+
+// toArray converts the container from a bitmap to a sorted array
+func (c *ipContainer) toArray() {
+
+	array := make([]uint16, 0, c.cardinality)
+	c.each(func(low uint16) {
+		array = append(array, low)
+	})
+	c.array = array
+	c.bitmap = nil
+
+}
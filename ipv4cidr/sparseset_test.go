@@ -0,0 +1,128 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"testing"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSparseIPSet adds, checks and removes scattered IPs
+// Success Metric: Membership and cardinality are tracked correctly
+func TestSparseIPSet(t *testing.T) {
+
+	set := NewSparseIPSet()
+	set.Add(168427520)  // 10.10.0.0
+	set.Add(168427520)  // 10.10.0.0 again
+	set.Add(3232235786) // 192.168.1.10
+	assert.Nil(t, set.AddString("8.8.8.8"), "8.8.8.8 is a valid IP, it should be added.")
+
+	assert.Equal(t, uint64(3), set.Cardinality())
+	assert.True(t, set.Contains(168427520))
+	assert.True(t, set.Contains(134744072))
+	assert.False(t, set.Contains(168427521))
+
+	set.Remove(168427520)
+	set.Remove(168427521)
+	assert.Equal(t, uint64(2), set.Cardinality())
+	assert.False(t, set.Contains(168427520))
+
+	err := set.AddString("8.8.8.256")
+	if assert.Error(t, err, "8.8.8.256 is an invalid IP. An error should be thrown.") {
+
+		assert.Equal(t, consts.InvalidIPv4Error, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidIPv4Error)
+
+	}
+
+}
+
+// TestSparseIPSetDenseContainer adds enough IPs sharing the same high bits to switch to a bitmap, then removes them
+// Success Metric: Membership and cardinality stay correct across the switch in both directions
+func TestSparseIPSetDenseContainer(t *testing.T) {
+
+	set := NewSparseIPSet()
+	base := uint32(168427520) // 10.10.0.0
+	for n := uint32(0); n < 10000; n += 2 {
+		set.Add(base + n)
+	}
+
+	assert.Equal(t, uint64(5000), set.Cardinality())
+	assert.True(t, set.Contains(base+9998))
+	assert.False(t, set.Contains(base+9999))
+
+	for n := uint32(0); n < 2000; n += 2 {
+		set.Remove(base + n)
+	}
+
+	assert.Equal(t, uint64(4000), set.Cardinality())
+	assert.False(t, set.Contains(base))
+	assert.True(t, set.Contains(base+2000))
+
+}
+
+// TestSparseIPSetUnion merges a sparse and a dense set
+// Success Metric: The union holds the addresses of both sets, and the inputs are unchanged
+func TestSparseIPSetUnion(t *testing.T) {
+
+	sparse := NewSparseIPSet()
+	dense := NewSparseIPSet()
+	base := uint32(168427520) // 10.10.0.0
+
+	for n := uint32(0); n < 5000; n++ {
+		dense.Add(base + n)
+	}
+	sparse.Add(base + 1)
+	sparse.Add(base + 6000)
+	sparse.Add(3232235786)
+
+	union := sparse.Union(dense)
+	assert.Equal(t, uint64(5002), union.Cardinality())
+	assert.True(t, union.Contains(base+6000))
+	assert.True(t, union.Contains(base+4999))
+	assert.Equal(t, uint64(3), sparse.Cardinality())
+	assert.Equal(t, uint64(5000), dense.Cardinality())
+
+}
+
+// TestSparseIPSetUnionOverlapping merges two sparse sets holding mostly the same addresses
+// Success Metric: The union stays a sorted array, as its cardinality is below the threshold even though the inputs add up to more
+func TestSparseIPSetUnionOverlapping(t *testing.T) {
+
+	first := NewSparseIPSet()
+	second := NewSparseIPSet()
+	base := uint32(168427520) // 10.10.0.0
+
+	for n := uint32(0); n < 3000; n++ {
+		first.Add(base + n)
+		second.Add(base + n + 1)
+	}
+
+	union := first.Union(second)
+	assert.Equal(t, uint64(3001), union.Cardinality())
+	assert.True(t, union.Contains(base))
+	assert.True(t, union.Contains(base+3000))
+
+	container := union.containers[uint16(base>>16)]
+	assert.Nil(t, container.bitmap, "A container with at most 4096 addresses should be an array")
+	assert.Len(t, container.array, 3001)
+
+}
+
+// TestSparseIPSetCIDRs summarizes the addresses of a set into CIDR ranges
+// Success Metric: The minimal list of CIDR ranges covering exactly the addresses is returned
+func TestSparseIPSetCIDRs(t *testing.T) {
+
+	set := NewSparseIPSet()
+	base := uint32(168427520) // 10.10.0.0
+	for n := uint32(0); n < 65536+256; n++ {
+		set.Add(base + n)
+	}
+	set.Add(3232235786) // 192.168.1.10
+
+	assert.Equal(t, []string{"10.10.0.0/16", "10.11.0.0/24", "192.168.1.10/32"}, toStrings(set.CIDRs()))
+
+}
@@ -0,0 +1,155 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"math/bits"
+	"sort"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/utils"
+)
+
+// NthSubnet returns the nth child CIDR range of a given mask directly, without iterating, e.g. the 300th /28 of a /16
+// @input targetMask uint8: The mask of the child CIDR ranges, between the mask of the CIDR range and 32
+// @input n uint64: The index of the child, starting at 0 for the lowest one
+// @returns *IPv4CIDR: The nth child CIDR range
+// @returns error: If the mask is invalid or there are not enough children of that size, the appropriate error is returned
+func (i *IPv4CIDR) NthSubnet(targetMask uint8, n uint64) (*IPv4CIDR, error) {
+
+	if targetMask < i.mask || targetMask > consts.MaxBits {
+		return nil, utils.NewDetailedError(consts.InvalidMaskCode, consts.InvalidChildMaskError, targetMask, i.mask, consts.MaxBits)
+	}
+
+	if count := uint64(1) << (targetMask - i.mask); n >= count {
+		return nil, utils.NewDetailedError(consts.OutOfRangeCode, consts.SubnetIndexOutOfRangeError, n, 0, count-1)
+	}
+
+	offset := n * utils.GetCIDRRangeLength64(targetMask)
+	checkOffset(i, offset, "NthSubnet")
+
+	return fromIPAndMask(i.ip+uint32(offset), targetMask), nil
+
+}
+
+// Subnet returns the subnet numbered index after extending the prefix by newBits, with the same results as the cidrsubnet function of Terraform,
+// e.g. Subnet(8, 2) of 10.0.0.0/8 is 10.2.0.0/16
+// @input newBits uint8: The number of bits to add to the mask
+// @input index uint32: The number of the subnet, starting at 0 for the lowest one
+// @returns *IPv4CIDR: The subnet
+// @returns error: If the resulting mask would be larger than 32 or there are not enough subnets of that size, an error is returned
+func (i *IPv4CIDR) Subnet(newBits uint8, index uint32) (*IPv4CIDR, error) {
+
+	if newBits > consts.MaxBits-i.mask {
+		return nil, utils.NewDetailedError(consts.InvalidMaskCode, consts.InvalidMaskError, int(i.mask)+int(newBits), 0, consts.MaxBits)
+	}
+
+	return i.NthSubnet(i.mask+newBits, uint64(index))
+
+}
+
+// SubnetIndex returns the position of the CIDR range among the equally sized children of a parent CIDR range, the inverse of NthSubnet
+// @input parent *IPv4CIDR: The parent CIDR range
+// @returns uint64: The index of the CIDR range, starting at 0 for the lowest child
+// @returns error: If the CIDR range is not within the parent, an error is returned
+func (i *IPv4CIDR) SubnetIndex(parent *IPv4CIDR) (uint64, error) {
+
+	if !parent.contains(i) {
+		return 0, utils.NewDetailedError(consts.NotWithinParentCode, consts.NotWithinParentError, i.ToString(), parent.ToString())
+	}
+
+	return uint64(i.ip-parent.ip) / utils.GetCIDRRangeLength64(i.mask), nil
+
+}
+
+// SplitInto divides the CIDR range into n equally sized children, e.g. 16 /28s for a /24, instead of calling Split recursively
+// @input n uint32: The number of children, a power of two
+// @returns []*IPv4CIDR: The children, in order of IP
+// @returns error: If n is not a power of two or the CIDR range has fewer than n IP addresses, an error is returned
+func (i *IPv4CIDR) SplitInto(n uint32) ([]*IPv4CIDR, error) {
+
+	if n == 0 || n&(n-1) != 0 || uint8(bits.TrailingZeros32(n)) > consts.MaxBits-i.mask {
+		return nil, utils.NewError(consts.SplitNotPossibleCode, consts.InvalidSubnetCountError)
+	}
+
+	return i.SplitToMask(i.mask + uint8(bits.TrailingZeros32(n)))
+
+}
+
+// SplitToMask enumerates every child CIDR range of a given mask, e.g. all /28s of a /24, instead of calling Split recursively
+// @input newMask uint8: The mask of the children, between the mask of the CIDR range and 32
+// @returns []*IPv4CIDR: The children, in order of IP
+// @returns error: If the mask is smaller than the mask of the CIDR range or larger than 32, an error is returned
+func (i *IPv4CIDR) SplitToMask(newMask uint8) ([]*IPv4CIDR, error) {
+
+	if newMask < i.mask || newMask > consts.MaxBits {
+		return nil, utils.NewDetailedError(consts.InvalidMaskCode, consts.InvalidChildMaskError, newMask, i.mask, consts.MaxBits)
+	}
+
+	count := uint64(1) << (newMask - i.mask)
+	step := utils.GetCIDRRangeLength64(newMask)
+
+	children := make([]*IPv4CIDR, 0, count)
+	for k := uint64(0); k < count; k++ {
+		checkOffset(i, k*step, "SplitToMask")
+		children = append(children, fromIPAndMask(i.ip+uint32(k*step), newMask))
+	}
+
+	return children, nil
+
+}
+
+// SplitByHostCounts carves the CIDR range into variably sized subnets (VLSM), each the smallest one with enough usable hosts for its requirement under a host policy
+// The policy decides the size of small subnets: under DefaultHostPolicy 2 hosts fit in a /31 (RFC 3021 point-to-point link), while LAN subnets reserving
+// their network and broadcast addresses (HostPolicy{ReserveNetworkAndBroadcast: true}) need a /30.
+// Subnets are packed from the start of the CIDR range, largest first, so that every subnet is aligned and the free space is left in one piece at the end
+// @input requirements []uint32: The number of hosts each subnet must hold
+// @input policy HostPolicy: The rules deciding how many hosts a subnet can hold
+// @returns []*IPv4CIDR: The subnets, in the order of the requirements
+// @returns error: If the subnets do not all fit in the CIDR range, an error is returned
+func (i *IPv4CIDR) SplitByHostCounts(requirements []uint32, policy HostPolicy) ([]*IPv4CIDR, error) {
+
+	masks := make([]uint8, len(requirements))
+	for n, hosts := range requirements {
+
+		// The smallest subnet with enough usable hosts, i.e. the longest mask
+		mask := consts.MaxBits
+		for mask > i.mask && policy.UsableHostCount(fromIPAndMask(i.ip, mask)) < uint64(hosts) {
+			mask--
+		}
+		if policy.UsableHostCount(fromIPAndMask(i.ip, mask)) < uint64(hosts) {
+			return nil, utils.NewError(consts.SplitNotPossibleCode, consts.InsufficientSpaceError)
+		}
+
+		masks[n] = mask
+
+	}
+
+	order := make([]int, len(requirements))
+	for n := range order {
+		order[n] = n
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return masks[order[a]] < masks[order[b]]
+	})
+
+	// Subnets are allocated largest first, so every offset is a multiple of the size of the next subnet
+	subnets := make([]*IPv4CIDR, len(requirements))
+	offset := uint64(0)
+	for _, n := range order {
+
+		size := utils.GetCIDRRangeLength64(masks[n])
+		if offset+size > utils.GetCIDRRangeLength64(i.mask) {
+			return nil, utils.NewError(consts.SplitNotPossibleCode, consts.InsufficientSpaceError)
+		}
+
+		checkOffset(i, offset, "SplitByHostCounts")
+		subnets[n] = fromIPAndMask(i.ip+uint32(offset), masks[n])
+		offset += size
+
+	}
+
+	return subnets, nil
+
+}
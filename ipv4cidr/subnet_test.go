@@ -0,0 +1,319 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"testing"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNthSubnet gets children of various sizes by index
+// Success Metric: The correct child is returned, including the first and last ones
+func TestNthSubnet(t *testing.T) {
+
+	CIDR, _ := NewIPv4CIDR("10.0.0.0/16", false)
+
+	subnet, err := CIDR.NthSubnet(28, 300)
+	assert.Nil(t, err)
+	assert.Equal(t, "10.0.18.192/28", subnet.ToString())
+
+	subnet, err = CIDR.NthSubnet(16, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "10.0.0.0/16", subnet.ToString(), "The only /16 of a /16 is itself")
+
+	subnet, err = CIDR.NthSubnet(32, 65535)
+	assert.Nil(t, err)
+	assert.Equal(t, "10.0.255.255/32", subnet.ToString())
+
+	CIDR, _ = NewIPv4CIDR("0.0.0.0/0", false)
+	subnet, err = CIDR.NthSubnet(32, 4294967295)
+	assert.Nil(t, err)
+	assert.Equal(t, "255.255.255.255/32", subnet.ToString())
+
+}
+
+// TestNthSubnetInvalidInput gets children with an invalid mask or index
+// Success Metric: Errors are returned with the appropriate messages
+func TestNthSubnetInvalidInput(t *testing.T) {
+
+	CIDR, _ := NewIPv4CIDR("10.0.0.0/16", false)
+
+	_, err := CIDR.NthSubnet(15, 0)
+	if assert.Error(t, err, "A /15 is not a child of a /16. An error should be thrown.") {
+
+		assert.Equal(t, consts.InvalidChildMaskError, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidChildMaskError)
+
+	}
+
+	_, err = CIDR.NthSubnet(24, 256)
+	if assert.Error(t, err, "A /16 only has 256 /24s. An error should be thrown.") {
+
+		assert.Equal(t, consts.SubnetIndexOutOfRangeError, err.Error(), "Error thrown should be: \"%s\"", consts.SubnetIndexOutOfRangeError)
+
+	}
+
+}
+
+// TestSubnet gets subnets by number of new bits and index, as cidrsubnet does in Terraform
+// Success Metric: The results match the ones of cidrsubnet
+func TestSubnet(t *testing.T) {
+
+	for _, test := range []struct {
+		cidr     string
+		newBits  uint8
+		index    uint32
+		expected string
+	}{
+		{"172.16.0.0/12", 4, 2, "172.18.0.0/16"},
+		{"10.1.2.0/24", 4, 15, "10.1.2.240/28"},
+		{"10.0.0.0/8", 8, 2, "10.2.0.0/16"},
+		{"10.0.0.0/16", 0, 0, "10.0.0.0/16"},
+		{"0.0.0.0/0", 32, 4294967295, "255.255.255.255/32"},
+	} {
+
+		CIDR, _ := NewIPv4CIDR(test.cidr, false)
+		subnet, err := CIDR.Subnet(test.newBits, test.index)
+		if assert.Nil(t, err) {
+			assert.Equal(t, test.expected, subnet.ToString(), "cidrsubnet(\"%s\", %d, %d)", test.cidr, test.newBits, test.index)
+		}
+
+	}
+
+}
+
+// TestSubnetInvalidInput gets subnets with too many new bits or an index out of range
+// Success Metric: Errors are returned with the appropriate messages
+func TestSubnetInvalidInput(t *testing.T) {
+
+	CIDR, _ := NewIPv4CIDR("10.0.0.0/24", false)
+
+	for _, newBits := range []uint8{9, 255} {
+
+		_, err := CIDR.Subnet(newBits, 0)
+		if assert.Error(t, err, "A /24 cannot be extended by %d bits. An error should be thrown.", newBits) {
+
+			assert.Equal(t, consts.InvalidMaskError, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidMaskError)
+
+		}
+
+	}
+
+	_, err := CIDR.Subnet(2, 4)
+	if assert.Error(t, err, "A /24 only has 4 /26s. An error should be thrown.") {
+
+		assert.Equal(t, consts.SubnetIndexOutOfRangeError, err.Error(), "Error thrown should be: \"%s\"", consts.SubnetIndexOutOfRangeError)
+
+	}
+
+}
+
+// TestSubnetIndex gets the index of children within their parent
+// Success Metric: The index is the inverse of NthSubnet
+func TestSubnetIndex(t *testing.T) {
+
+	parent, _ := NewIPv4CIDR("10.0.0.0/16", false)
+
+	subnet, _ := NewIPv4CIDR("10.0.18.192/28", false)
+	index, err := subnet.SubnetIndex(parent)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(300), index)
+
+	index, err = parent.SubnetIndex(parent)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0), index, "A CIDR range is the first child of itself")
+
+	for _, n := range []uint64{0, 1, 255, 4095} {
+		child, _ := parent.NthSubnet(28, n)
+		index, err = child.SubnetIndex(parent)
+		assert.Nil(t, err)
+		assert.Equal(t, n, index)
+	}
+
+	all, _ := NewIPv4CIDR("0.0.0.0/0", false)
+	last, _ := NewIPv4CIDR("255.255.255.255", false)
+	index, err = last.SubnetIndex(all)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(4294967295), index)
+
+}
+
+// TestSubnetIndexNotWithinParent gets the index of a CIDR range outside the parent
+// Success Metric: Throw an error saying the CIDR range is not within the parent
+func TestSubnetIndexNotWithinParent(t *testing.T) {
+
+	parent, _ := NewIPv4CIDR("10.0.0.0/16", false)
+
+	for _, input := range []string{"10.1.0.0/24", "10.0.0.0/8"} {
+
+		CIDR, _ := NewIPv4CIDR(input, false)
+		_, err := CIDR.SubnetIndex(parent)
+		if assert.Error(t, err, "%s is not within 10.0.0.0/16. An error should be thrown.", input) {
+
+			assert.Equal(t, consts.NotWithinParentError, err.Error(), "Error thrown should be: \"%s\"", consts.NotWithinParentError)
+
+		}
+
+	}
+
+}
+
+// TestSplitInto divides CIDR ranges into equally sized children
+// Success Metric: The children are contiguous, of the expected size, and cover the CIDR range
+func TestSplitInto(t *testing.T) {
+
+	CIDR, _ := NewIPv4CIDR("10.0.0.0/24", false)
+
+	children, err := CIDR.SplitInto(16)
+	assert.Nil(t, err)
+	if assert.Len(t, children, 16) {
+		assert.Equal(t, "10.0.0.0/28", children[0].ToString())
+		assert.Equal(t, "10.0.0.16/28", children[1].ToString())
+		assert.Equal(t, "10.0.0.240/28", children[15].ToString())
+	}
+
+	children, err = CIDR.SplitInto(1)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"10.0.0.0/24"}, toStrings(children), "Splitting into one child returns the CIDR range itself")
+
+	children, err = CIDR.SplitInto(256)
+	assert.Nil(t, err)
+	if assert.Len(t, children, 256) {
+		assert.Equal(t, "10.0.0.255/32", children[255].ToString())
+	}
+
+	CIDR, _ = NewIPv4CIDR("0.0.0.0/0", false)
+	children, err = CIDR.SplitInto(4)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"0.0.0.0/2", "64.0.0.0/2", "128.0.0.0/2", "192.0.0.0/2"}, toStrings(children))
+
+}
+
+// TestSplitIntoInvalidCount divides a CIDR range into a number of children that is not a power of two or too large
+// Success Metric: Throw an error saying the number of subnets is invalid
+func TestSplitIntoInvalidCount(t *testing.T) {
+
+	CIDR, _ := NewIPv4CIDR("10.0.0.0/24", false)
+
+	for _, n := range []uint32{0, 3, 12, 512} {
+
+		_, err := CIDR.SplitInto(n)
+		if assert.Error(t, err, "A /24 cannot be split into %d children. An error should be thrown.", n) {
+
+			assert.Equal(t, consts.InvalidSubnetCountError, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidSubnetCountError)
+
+		}
+
+	}
+
+}
+
+// TestSplitToMask enumerates the children of CIDR ranges at various masks
+// Success Metric: Every child of the mask is returned, in order of IP
+func TestSplitToMask(t *testing.T) {
+
+	CIDR, _ := NewIPv4CIDR("10.0.0.0/24", false)
+
+	children, err := CIDR.SplitToMask(26)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"10.0.0.0/26", "10.0.0.64/26", "10.0.0.128/26", "10.0.0.192/26"}, toStrings(children))
+
+	children, err = CIDR.SplitToMask(24)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"10.0.0.0/24"}, toStrings(children), "The only /24 of a /24 is itself")
+
+	children, err = CIDR.SplitToMask(32)
+	assert.Nil(t, err)
+	if assert.Len(t, children, 256) {
+		assert.Equal(t, "10.0.0.0/32", children[0].ToString())
+		assert.Equal(t, "10.0.0.255/32", children[255].ToString())
+	}
+
+	CIDR, _ = NewIPv4CIDR("255.255.255.0/24", false)
+	children, err = CIDR.SplitToMask(25)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"255.255.255.0/25", "255.255.255.128/25"}, toStrings(children), "Children at the end of the address space should not overflow")
+
+}
+
+// TestSplitToMaskInvalidMask enumerates children with a mask smaller than the CIDR range or larger than 32
+// Success Metric: Throw an error saying the child mask is invalid
+func TestSplitToMaskInvalidMask(t *testing.T) {
+
+	CIDR, _ := NewIPv4CIDR("10.0.0.0/24", false)
+
+	for _, mask := range []uint8{23, 33} {
+
+		_, err := CIDR.SplitToMask(mask)
+		if assert.Error(t, err, "/%d is not a valid child mask. An error should be thrown.", mask) {
+
+			assert.Equal(t, consts.InvalidChildMaskError, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidChildMaskError)
+
+		}
+
+	}
+
+}
+
+// TestSplitByHostCounts carves CIDR ranges into subnets for lists of host counts
+// Success Metric: Each subnet is the smallest one holding its hosts, subnets are aligned and do not overlap, and are returned in the order of the requirements
+func TestSplitByHostCounts(t *testing.T) {
+
+	CIDR, _ := NewIPv4CIDR("192.168.1.0/24", false)
+
+	subnets, err := CIDR.SplitByHostCounts([]uint32{20, 100, 2, 50, 1}, DefaultHostPolicy)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"192.168.1.192/27", "192.168.1.0/25", "192.168.1.224/31", "192.168.1.128/26", "192.168.1.226/32"}, toStrings(subnets))
+
+	subnets, err = CIDR.SplitByHostCounts([]uint32{254}, DefaultHostPolicy)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"192.168.1.0/24"}, toStrings(subnets), "254 hosts need the whole /24")
+
+	subnets, err = CIDR.SplitByHostCounts([]uint32{126, 126}, DefaultHostPolicy)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"192.168.1.0/25", "192.168.1.128/25"}, toStrings(subnets))
+
+	subnets, err = CIDR.SplitByHostCounts(nil, DefaultHostPolicy)
+	assert.Nil(t, err)
+	assert.Empty(t, subnets)
+
+}
+
+// TestSplitByHostCountsPolicy carves a CIDR range into subnets of 2 hosts under different host policies
+// Success Metric: 2 hosts get a /31 under DefaultHostPolicy (RFC 3021), and a /30 when the network and broadcast addresses of every subnet are reserved
+func TestSplitByHostCountsPolicy(t *testing.T) {
+
+	CIDR, _ := NewIPv4CIDR("192.168.1.0/24", false)
+
+	subnets, err := CIDR.SplitByHostCounts([]uint32{2, 2}, DefaultHostPolicy)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"192.168.1.0/31", "192.168.1.2/31"}, toStrings(subnets))
+
+	lan := HostPolicy{ReserveNetworkAndBroadcast: true}
+	subnets, err = CIDR.SplitByHostCounts([]uint32{2, 2, 6}, lan)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"192.168.1.8/30", "192.168.1.12/30", "192.168.1.0/29"}, toStrings(subnets))
+
+}
+
+// TestSplitByHostCountsInsufficientSpace carves CIDR ranges into subnets that do not fit
+// Success Metric: Throw an error saying the CIDR range is too small
+func TestSplitByHostCountsInsufficientSpace(t *testing.T) {
+
+	CIDR, _ := NewIPv4CIDR("192.168.1.0/24", false)
+
+	for _, requirements := range [][]uint32{{255}, {100, 100, 100}, {126, 126, 1}} {
+
+		_, err := CIDR.SplitByHostCounts(requirements, DefaultHostPolicy)
+		if assert.Error(t, err, "%v does not fit in a /24. An error should be thrown.", requirements) {
+
+			assert.Equal(t, consts.InsufficientSpaceError, err.Error(), "Error thrown should be: \"%s\"", consts.InsufficientSpaceError)
+
+		}
+
+	}
+
+}
@@ -0,0 +1,74 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"sort"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/utils"
+)
+
+// BlockSuggestion is a free block of a parent CIDR range that could be allocated, along with the fragmentation it would leave behind
+// @field CIDR *IPv4CIDR: The suggested block
+// @field FreeBlocks int: The number of CIDR ranges making up the free space of the parent after allocating the block
+// @field LargestFreeBlock *IPv4CIDR: The largest free CIDR range left after allocating the block, nil if the parent would be full
+type BlockSuggestion struct {
+	CIDR             *IPv4CIDR
+	FreeBlocks       int
+	LargestFreeBlock *IPv4CIDR
+}
+
+// SuggestBlocks suggests free blocks of a requested size within a parent CIDR range, best fit first, without allocating anything, e.g. for interactive planning tools
+// One block is suggested per free CIDR range large enough, carved from its start. Suggestions that keep the largest free block are ranked first,
+// then those leaving the fewest free blocks, so blocks are carved from the smallest free ranges that fit and large ranges are preserved
+// @input parent *IPv4CIDR: The CIDR range to allocate from
+// @input allocations []*IPv4CIDR: The existing allocations, which may overlap each other or extend beyond the parent
+// @input mask uint8: The mask of the requested block
+// @input limit int: The maximum number of suggestions to return, 0 or less for all of them
+// @returns []BlockSuggestion: The suggestions, best first. Empty if no free block of the requested size is left
+// @returns error: If the mask is smaller than the mask of the parent or larger than 32, an error is returned
+func SuggestBlocks(parent *IPv4CIDR, allocations []*IPv4CIDR, mask uint8, limit int) ([]BlockSuggestion, error) {
+
+	if mask < parent.mask || mask > consts.MaxBits {
+		return nil, utils.NewError(consts.InvalidMaskCode, consts.InvalidChildMaskError)
+	}
+
+	suggestions := make([]BlockSuggestion, 0)
+	for _, free := range ExcludeAll(parent, allocations) {
+
+		if free.mask > mask {
+			continue
+		}
+
+		// Free ranges are aligned blocks, so their first child of the requested size is always a valid block
+		candidate := fromIPAndMask(free.ip, mask)
+		remaining := ExcludeAll(parent, append(append(make([]*IPv4CIDR, 0, len(allocations)+1), allocations...), candidate))
+
+		suggestion := BlockSuggestion{CIDR: candidate, FreeBlocks: len(remaining)}
+		for _, block := range remaining {
+			if suggestion.LargestFreeBlock == nil || block.mask < suggestion.LargestFreeBlock.mask {
+				suggestion.LargestFreeBlock = block
+			}
+		}
+
+		suggestions = append(suggestions, suggestion)
+
+	}
+
+	sort.SliceStable(suggestions, func(a, b int) bool {
+		largestA, largestB := suggestions[a].LargestFreeBlock, suggestions[b].LargestFreeBlock
+		if largestA != nil && largestB != nil && largestA.mask != largestB.mask {
+			return largestA.mask < largestB.mask
+		}
+		return suggestions[a].FreeBlocks < suggestions[b].FreeBlocks
+	})
+
+	if limit > 0 && len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+
+	return suggestions, nil
+
+}
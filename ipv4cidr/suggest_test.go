@@ -0,0 +1,78 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"testing"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSuggestBlocks suggests free blocks in a partially allocated parent
+// Success Metric: Blocks are carved from the smallest free range that fits, preserving the largest free range
+func TestSuggestBlocks(t *testing.T) {
+
+	parent := mustParse("10.0.0.0/22")
+
+	// Free space: 10.0.0.128/25, 10.0.1.0/24 and 10.0.2.0/23
+	allocations := parseAll(t, "10.0.0.0/25")
+
+	suggestions, err := SuggestBlocks(parent, allocations, 25, 0)
+	assert.Nil(t, err)
+
+	if assert.Len(t, suggestions, 3) {
+
+		assert.Equal(t, "10.0.0.128/25", suggestions[0].CIDR.ToString(), "The /25 gap is the best fit")
+		assert.Equal(t, 2, suggestions[0].FreeBlocks)
+		assert.Equal(t, "10.0.2.0/23", suggestions[0].LargestFreeBlock.ToString())
+
+		assert.Equal(t, "10.0.1.0/25", suggestions[1].CIDR.ToString())
+		assert.Equal(t, 3, suggestions[1].FreeBlocks)
+		assert.Equal(t, "10.0.2.0/23", suggestions[1].LargestFreeBlock.ToString())
+
+		assert.Equal(t, "10.0.2.0/25", suggestions[2].CIDR.ToString(), "Carving the /23 is the worst fit")
+		assert.Equal(t, "10.0.1.0/24", suggestions[2].LargestFreeBlock.ToString())
+
+	}
+
+	suggestions, err = SuggestBlocks(parent, allocations, 24, 1)
+	assert.Nil(t, err)
+	if assert.Len(t, suggestions, 1, "The suggestions should be limited") {
+		assert.Equal(t, "10.0.1.0/24", suggestions[0].CIDR.ToString())
+	}
+
+	suggestions, err = SuggestBlocks(parent, parseAll(t, "10.0.0.0/23", "10.0.2.0/23"), 32, 0)
+	assert.Nil(t, err)
+	assert.Empty(t, suggestions, "The parent is full, nothing can be suggested")
+
+	suggestions, err = SuggestBlocks(parent, nil, 22, 0)
+	assert.Nil(t, err)
+	if assert.Len(t, suggestions, 1) {
+		assert.Equal(t, "10.0.0.0/22", suggestions[0].CIDR.ToString())
+		assert.Equal(t, 0, suggestions[0].FreeBlocks)
+		assert.Nil(t, suggestions[0].LargestFreeBlock, "Nothing is left after allocating the whole parent")
+	}
+
+}
+
+// TestSuggestBlocksInvalidMask requests blocks larger than the parent or with an invalid mask
+// Success Metric: Errors are thrown for masks outside of the parent mask to 32
+func TestSuggestBlocksInvalidMask(t *testing.T) {
+
+	parent := mustParse("10.0.0.0/22")
+
+	for _, mask := range []uint8{21, 33} {
+
+		_, err := SuggestBlocks(parent, nil, mask, 0)
+		if assert.Error(t, err, "/%d is not a valid child mask. An error should be thrown.", mask) {
+
+			assert.Equal(t, consts.InvalidChildMaskError, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidChildMaskError)
+
+		}
+
+	}
+
+}
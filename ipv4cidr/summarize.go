@@ -0,0 +1,321 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"math/bits"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/utils"
+)
+
+// ipRange models an inclusive range of IP addresses
+// The bounds are stored as 64-bit values so that the address after the end of the IPv4 space can be represented
+// @field start uint64: The first IP address in the range
+// @field end uint64: The last IP address in the range
+type ipRange struct {
+	start uint64
+	end   uint64
+}
+
+// toRanges converts a list of CIDR ranges into a sorted list of disjoint, non-adjacent IP ranges covering their union
+// @input cidrs []*IPv4CIDR: The list of CIDR ranges
+// @returns []ipRange: The merged IP ranges
+func toRanges(cidrs []*IPv4CIDR) []ipRange {
+
+	ranges := make([]ipRange, 0, len(cidrs))
+	for _, cidr := range normalize(cidrs) {
+
+		start := uint64(cidr.ip)
+		end := uint64(cidr.lastIP())
+
+		// If this block starts right after the previous range, extend the previous range instead
+		if len(ranges) > 0 && ranges[len(ranges)-1].end+1 == start {
+			ranges[len(ranges)-1].end = end
+			continue
+		}
+		ranges = append(ranges, ipRange{start: start, end: end})
+
+	}
+
+	return ranges
+
+}
+
+// subtractRanges removes the addresses in one list of IP ranges from another
+// @input ranges []ipRange: Sorted, disjoint IP ranges to remove addresses from
+// @input exclusions []ipRange: Sorted, disjoint IP ranges to remove
+// @returns []ipRange: The remaining sorted, disjoint IP ranges
+func subtractRanges(ranges []ipRange, exclusions []ipRange) []ipRange {
+
+	result := make([]ipRange, 0, len(ranges))
+	for _, r := range ranges {
+
+		for _, exclusion := range exclusions {
+
+			// Exclusions entirely before the range are irrelevant, and once an exclusion starts after the range, so do all following ones
+			if exclusion.end < r.start {
+				continue
+			}
+			if exclusion.start > r.end {
+				break
+			}
+
+			// Keep the part of the range before the exclusion, and continue with the part after it
+			if exclusion.start > r.start {
+				result = append(result, ipRange{start: r.start, end: exclusion.start - 1})
+			}
+			r.start = exclusion.end + 1
+
+			if r.start > r.end {
+				break
+			}
+
+		}
+
+		if r.start <= r.end {
+			result = append(result, r)
+		}
+
+	}
+
+	return result
+
+}
+
+// rangeToCIDRs converts an IP range into the minimal list of CIDR ranges covering exactly that range
+// @input r ipRange: The IP range to convert
+// @returns []*IPv4CIDR: The CIDR ranges, in order of IP
+func rangeToCIDRs(r ipRange) []*IPv4CIDR {
+
+	cidrs := make([]*IPv4CIDR, 0)
+	for start := r.start; start <= r.end; {
+
+		// The largest block starting here is limited by the alignment of the start address
+		hostBits := uint8(consts.MaxBits)
+		if start != 0 {
+			hostBits = uint8(bits.TrailingZeros32(uint32(start)))
+		}
+
+		// Shrink the block until it no longer extends past the end of the range
+		for start+(uint64(1)<<hostBits)-1 > r.end {
+			hostBits--
+		}
+
+		cidrs = append(cidrs, fromIPAndMask(uint32(start), consts.MaxBits-hostBits))
+		start += uint64(1) << hostBits
+
+	}
+
+	return cidrs
+
+}
+
+// rangesToCIDRs converts a list of IP ranges into the minimal list of CIDR ranges covering exactly those ranges
+// @input ranges []ipRange: Sorted, disjoint IP ranges
+// @returns []*IPv4CIDR: The CIDR ranges, in order of IP
+func rangesToCIDRs(ranges []ipRange) []*IPv4CIDR {
+
+	cidrs := make([]*IPv4CIDR, 0, len(ranges))
+	for _, r := range ranges {
+		cidrs = append(cidrs, rangeToCIDRs(r)...)
+	}
+
+	return cidrs
+
+}
+
+// Difference returns the minimal list of CIDR ranges covering exactly the addresses that are included but not excluded
+// For example, including 10.0.0.0/8 and excluding three /24s returns the CIDR ranges making up the rest of 10.0.0.0/8
+// @input include []*IPv4CIDR: The CIDR ranges to include
+// @input exclude []*IPv4CIDR: The CIDR ranges to exclude
+// @returns []*IPv4CIDR: The minimal list of CIDR ranges, in order of IP
+func Difference(include []*IPv4CIDR, exclude []*IPv4CIDR) []*IPv4CIDR {
+
+	return rangesToCIDRs(subtractRanges(toRanges(include), toRanges(exclude)))
+
+}
+
+// ExcludeAll removes many CIDR ranges from a parent CIDR range at once, and returns the minimal list of CIDR ranges covering the remainder
+// The exclusions may overlap each other or extend beyond the parent
+// @input parent *IPv4CIDR: The CIDR range to remove addresses from
+// @input exclusions []*IPv4CIDR: The CIDR ranges to remove
+// @returns []*IPv4CIDR: The minimal list of CIDR ranges, in order of IP
+func ExcludeAll(parent *IPv4CIDR, exclusions []*IPv4CIDR) []*IPv4CIDR {
+
+	return Difference([]*IPv4CIDR{parent}, exclusions)
+
+}
+
+// Aggregate returns the minimal list of CIDR ranges covering exactly the union of the input, merging overlapping and adjacent ranges
+// @input cidrs []*IPv4CIDR: The list of CIDR ranges
+// @returns []*IPv4CIDR: The minimal list of CIDR ranges, in order of IP
+func Aggregate(cidrs []*IPv4CIDR) []*IPv4CIDR {
+
+	return rangesToCIDRs(toRanges(cidrs))
+
+}
+
+// CIDRsBetween returns the minimal list of CIDR ranges covering the addresses strictly between two CIDR ranges, e.g. to fill the gap between two allocations of a plan
+// The order of the two CIDR ranges does not matter
+// @input a *IPv4CIDR: The first CIDR range
+// @input b *IPv4CIDR: The second CIDR range
+// @returns []*IPv4CIDR: The minimal list of CIDR ranges, in order of IP. Empty if the CIDR ranges overlap or are adjacent
+func CIDRsBetween(a *IPv4CIDR, b *IPv4CIDR) []*IPv4CIDR {
+
+	if a.ip > b.ip {
+		a, b = b, a
+	}
+
+	start := uint64(a.lastIP()) + 1
+	end := uint64(b.ip)
+	if start >= end {
+		return make([]*IPv4CIDR, 0)
+	}
+
+	return rangeToCIDRs(ipRange{start: start, end: end - 1})
+
+}
+
+// DiffLists compares two versions of a list of CIDR ranges, e.g. two releases of a published IP range feed
+// Both lists are normalized first, so a change in how the same addresses are split into CIDR ranges is not reported
+// @input oldList []*IPv4CIDR: The previous version of the list
+// @input newList []*IPv4CIDR: The current version of the list
+// @returns []*IPv4CIDR: The minimal list of CIDR ranges covering the addresses added in the current version
+// @returns []*IPv4CIDR: The minimal list of CIDR ranges covering the addresses removed in the current version
+func DiffLists(oldList []*IPv4CIDR, newList []*IPv4CIDR) ([]*IPv4CIDR, []*IPv4CIDR) {
+
+	oldRanges := toRanges(oldList)
+	newRanges := toRanges(newList)
+
+	added := rangesToCIDRs(subtractRanges(newRanges, oldRanges))
+	removed := rangesToCIDRs(subtractRanges(oldRanges, newRanges))
+
+	return added, removed
+
+}
+
+// CoverIPs returns the smallest single CIDR range that contains all the input IP addresses
+// @input IPs []string: The IP addresses in format a.b.c.d. CIDR ranges in format a.b.c.d/e are also accepted, in which case the whole range is covered
+// @returns *IPv4CIDR: The smallest CIDR range containing every input
+// @returns error: If the list is empty or any input is invalid, an error is returned
+func CoverIPs(IPs []string) (*IPv4CIDR, error) {
+
+	if len(IPs) == 0 {
+		return nil, utils.NewError(consts.EmptyInputCode, consts.EmptyInputError)
+	}
+
+	// Track the lowest and highest addresses seen
+	lowest := consts.MaxUInt32
+	highest := uint32(0)
+
+	for _, IP := range IPs {
+
+		cidr, err := NewIPv4CIDR(IP, false)
+		if err != nil {
+			return nil, err
+		}
+
+		if cidr.ip < lowest {
+			lowest = cidr.ip
+		}
+		if cidr.lastIP() > highest {
+			highest = cidr.lastIP()
+		}
+
+	}
+
+	// The covering block is defined by the bits the lowest and highest addresses have in common
+	mask := utils.GetCommonPrefixLength(lowest, highest)
+
+	return fromIPAndMask(lowest, mask), nil
+
+}
+
+// CommonPrefixLen calculates the number of leading bits that two IP addresses have in common, e.g. to cluster hosts
+// @input a string: The first IP address in format a.b.c.d
+// @input b string: The second IP address in format a.b.c.d
+// @returns uint8: The length of the common prefix (0-32)
+// @returns error: If either IP address is invalid, an error is returned
+func CommonPrefixLen(a string, b string) (uint8, error) {
+
+	ipA, err := utils.ParseIPUint32(a)
+	if err != nil {
+		return 0, err
+	}
+
+	ipB, err := utils.ParseIPUint32(b)
+	if err != nil {
+		return 0, err
+	}
+
+	return utils.GetCommonPrefixLength(ipA, ipB), nil
+
+}
+
+// CommonPrefix returns the smallest CIDR range containing both IP addresses, i.e. the CIDR range of their common prefix
+// @input a string: The first IP address in format a.b.c.d
+// @input b string: The second IP address in format a.b.c.d
+// @returns *IPv4CIDR: The CIDR range of the common prefix
+// @returns error: If either IP address is invalid, an error is returned
+func CommonPrefix(a string, b string) (*IPv4CIDR, error) {
+
+	mask, err := CommonPrefixLen(a, b)
+	if err != nil {
+		return nil, err
+	}
+
+	// Both addresses share the prefix, so either one can be standardized to get the CIDR range
+	ip, _ := utils.ParseIPUint32(a)
+
+	return fromIPAndMask(ip, mask), nil
+
+}
+
+// SummarizeIPs collapses a list of IP addresses into the minimal list of CIDR ranges covering exactly those addresses
+// @input IPs []string: The IP addresses in format a.b.c.d. CIDR ranges in format a.b.c.d/e are also accepted
+// @returns []*IPv4CIDR: The minimal list of CIDR ranges, in order of IP
+// @returns error: If any input is invalid, an error is returned
+func SummarizeIPs(IPs []string) ([]*IPv4CIDR, error) {
+
+	cidrs := make([]*IPv4CIDR, 0, len(IPs))
+	for _, IP := range IPs {
+
+		cidr, err := NewIPv4CIDR(IP, false)
+		if err != nil {
+			return nil, err
+		}
+		cidrs = append(cidrs, cidr)
+
+	}
+
+	return Aggregate(cidrs), nil
+
+}
+
+// SummarizeRange converts an inclusive range of IP addresses into the minimal list of CIDR ranges covering exactly that range
+// For example, 10.0.0.0 to 10.0.2.255 returns 10.0.0.0/23 and 10.0.2.0/24
+// @input first string: The first IP address of the range in format a.b.c.d
+// @input last string: The last IP address of the range in format a.b.c.d
+// @returns []*IPv4CIDR: The minimal list of CIDR ranges, in order of IP
+// @returns error: If either IP address is invalid, or the last IP address is before the first, an error is returned
+func SummarizeRange(first string, last string) ([]*IPv4CIDR, error) {
+
+	start, err := utils.ParseIPUint32(first)
+	if err != nil {
+		return nil, err
+	}
+
+	end, err := utils.ParseIPUint32(last)
+	if err != nil {
+		return nil, err
+	}
+
+	if end < start {
+		return nil, utils.NewError(consts.InvalidInputCode, consts.InvalidIPRangeError)
+	}
+
+	return rangeToCIDRs(ipRange{start: uint64(start), end: uint64(end)}), nil
+
+}
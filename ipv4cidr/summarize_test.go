@@ -0,0 +1,266 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"testing"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// toStrings is a test helper that converts a list of IPv4CIDR objects into their string representations
+func toStrings(cidrs []*IPv4CIDR) []string {
+
+	strs := make([]string, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		strs = append(strs, cidr.ToString())
+	}
+
+	return strs
+
+}
+
+// TestDifference removes excluded ranges from included ranges
+// Success Metric: The minimal list of CIDR ranges covering exactly the difference is returned
+func TestDifference(t *testing.T) {
+
+	include := parseAll(t, "10.0.0.0/22")
+	exclude := parseAll(t, "10.0.1.0/24", "10.0.3.128/25", "192.168.0.0/16")
+
+	expected := []string{"10.0.0.0/24", "10.0.2.0/24", "10.0.3.0/25"}
+	assert.Equal(t, expected, toStrings(Difference(include, exclude)))
+
+}
+
+// TestDifferenceMergesIncludes includes adjacent and overlapping ranges without any exclusion
+// Success Metric: Adjacent ranges are merged into their common parent
+func TestDifferenceMergesIncludes(t *testing.T) {
+
+	include := parseAll(t, "10.0.0.0/25", "10.0.0.128/25", "10.0.1.0/24", "10.0.1.7")
+
+	assert.Equal(t, []string{"10.0.0.0/23"}, toStrings(Difference(include, nil)))
+
+}
+
+// TestDifferenceEntireSpace excludes a single IP from the entire IPv4 space
+// Success Metric: 32 CIDR ranges are returned, one for each mask from /1 to /32
+func TestDifferenceEntireSpace(t *testing.T) {
+
+	include := parseAll(t, "0.0.0.0/0")
+	exclude := parseAll(t, "0.0.0.0")
+
+	result := Difference(include, exclude)
+	if assert.Len(t, result, 32) {
+
+		assert.Equal(t, "0.0.0.1/32", result[0].ToString())
+		assert.Equal(t, "128.0.0.0/1", result[31].ToString())
+
+	}
+
+	assert.Empty(t, Difference(include, include), "Excluding everything should leave nothing")
+
+}
+
+// TestExcludeAll removes several overlapping CIDR ranges from a parent at once
+// Success Metric: The minimal list of CIDR ranges covering the remainder is returned
+func TestExcludeAll(t *testing.T) {
+
+	parent := parseAll(t, "10.0.0.0/22")[0]
+	exclusions := parseAll(t, "10.0.1.0/24", "10.0.1.128/25", "10.0.3.0/25", "9.0.0.0/8")
+
+	assert.Equal(t, []string{"10.0.0.0/24", "10.0.2.0/24", "10.0.3.128/25"}, toStrings(ExcludeAll(parent, exclusions)))
+	assert.Equal(t, []string{"10.0.0.0/22"}, toStrings(ExcludeAll(parent, nil)), "Excluding nothing should leave the parent")
+	assert.Empty(t, ExcludeAll(parent, parseAll(t, "10.0.0.0/8")), "Excluding a supernet should leave nothing")
+
+}
+
+// TestAggregate merges overlapping and adjacent CIDR ranges
+// Success Metric: The minimal list of CIDR ranges covering the union is returned
+func TestAggregate(t *testing.T) {
+
+	cidrs := parseAll(t, "10.0.1.0/24", "10.0.0.0/24", "10.0.0.64/26", "10.0.2.0/25", "192.168.0.0/16")
+
+	assert.Equal(t, []string{"10.0.0.0/23", "10.0.2.0/25", "192.168.0.0/16"}, toStrings(Aggregate(cidrs)))
+
+}
+
+// TestCIDRsBetween fills the gap between two CIDR ranges
+// Success Metric: The minimal list of CIDR ranges strictly between them is returned, in either order, and nothing for overlapping or adjacent ranges
+func TestCIDRsBetween(t *testing.T) {
+
+	cidrs := parseAll(t, "10.0.0.0/24", "10.0.4.0/24", "10.0.1.0/24", "10.0.0.0/16")
+
+	assert.Equal(t, []string{"10.0.1.0/24", "10.0.2.0/23"}, toStrings(CIDRsBetween(cidrs[0], cidrs[1])))
+	assert.Equal(t, []string{"10.0.1.0/24", "10.0.2.0/23"}, toStrings(CIDRsBetween(cidrs[1], cidrs[0])))
+	assert.Empty(t, CIDRsBetween(cidrs[0], cidrs[2]), "Adjacent ranges have no gap")
+	assert.Empty(t, CIDRsBetween(cidrs[0], cidrs[3]), "Overlapping ranges have no gap")
+
+}
+
+// TestDiffLists compares two versions of a list of CIDR ranges
+// Success Metric: Added and removed addresses are reported as minimal CIDR lists
+func TestDiffLists(t *testing.T) {
+
+	oldList := parseAll(t, "10.0.0.0/24", "10.0.1.0/24", "192.168.0.0/24")
+	newList := parseAll(t, "10.0.0.0/23", "10.0.2.0/24", "192.168.0.0/25")
+
+	added, removed := DiffLists(oldList, newList)
+	assert.Equal(t, []string{"10.0.2.0/24"}, toStrings(added))
+	assert.Equal(t, []string{"192.168.0.128/25"}, toStrings(removed))
+
+}
+
+// TestDiffListsRepresentationChange compares two lists covering the same addresses with different CIDR ranges
+// Success Metric: No changes are reported
+func TestDiffListsRepresentationChange(t *testing.T) {
+
+	oldList := parseAll(t, "10.0.0.0/25", "10.0.0.128/25")
+	newList := parseAll(t, "10.0.0.0/24", "10.0.0.64/26")
+
+	added, removed := DiffLists(oldList, newList)
+	assert.Empty(t, added)
+	assert.Empty(t, removed)
+
+}
+
+// TestCoverIPs finds the smallest CIDR range containing a list of IPs
+// Success Metric: The minimal covering CIDR range is returned
+func TestCoverIPs(t *testing.T) {
+
+	CIDR, err := CoverIPs([]string{"10.10.0.5", "10.10.0.100", "10.10.0.77"})
+	assert.Nil(t, err, "All inputs are valid IPs, a covering range should be created.")
+	assert.Equal(t, "10.10.0.0/25", CIDR.ToString())
+
+	CIDR, err = CoverIPs([]string{"10.10.0.5"})
+	assert.Nil(t, err, "A single IP is a valid input.")
+	assert.Equal(t, "10.10.0.5/32", CIDR.ToString())
+
+	CIDR, err = CoverIPs([]string{"10.10.0.5", "10.10.1.0/24"})
+	assert.Nil(t, err, "CIDR ranges are valid inputs.")
+	assert.Equal(t, "10.10.0.0/23", CIDR.ToString())
+
+	CIDR, err = CoverIPs([]string{"1.2.3.4", "200.1.1.1"})
+	assert.Nil(t, err, "IPs differing in the first bit are valid inputs.")
+	assert.Equal(t, "0.0.0.0/0", CIDR.ToString())
+
+}
+
+// TestCoverIPsInvalidInput tries to cover an empty list and a list with an invalid IP
+// Success Metric: Throw the appropriate error for each input
+func TestCoverIPsInvalidInput(t *testing.T) {
+
+	_, err := CoverIPs(nil)
+	if assert.Error(t, err, "An empty list cannot be covered. An error should be thrown.") {
+
+		assert.Equal(t, consts.EmptyInputError, err.Error(), "Error thrown should be: \"%s\"", consts.EmptyInputError)
+
+	}
+
+	_, err = CoverIPs([]string{"10.10.0.5", "10.10.0.256"})
+	if assert.Error(t, err, "10.10.0.256 is an invalid IP. An error should be thrown.") {
+
+		assert.Equal(t, consts.InvalidIPv4CIDRError, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidIPv4CIDRError)
+
+	}
+
+}
+
+// TestCommonPrefix computes the common prefix of pairs of IPs
+// Success Metric: The correct prefix length and covering CIDR range are returned
+func TestCommonPrefix(t *testing.T) {
+
+	length, err := CommonPrefixLen("10.0.0.1", "10.0.0.6")
+	assert.Nil(t, err)
+	assert.Equal(t, uint8(29), length)
+
+	length, err = CommonPrefixLen("10.0.0.1", "10.0.0.1")
+	assert.Nil(t, err)
+	assert.Equal(t, uint8(32), length, "Identical IPs share all 32 bits")
+
+	length, err = CommonPrefixLen("10.0.0.1", "192.168.0.1")
+	assert.Nil(t, err)
+	assert.Equal(t, uint8(0), length, "IPs differing in the first bit share nothing")
+
+	prefix, err := CommonPrefix("10.0.3.1", "10.0.1.200")
+	assert.Nil(t, err)
+	assert.Equal(t, "10.0.0.0/22", prefix.ToString())
+
+}
+
+// TestCommonPrefixInvalidInput computes the common prefix of invalid IPs
+// Success Metric: Throw an error saying the IP is invalid
+func TestCommonPrefixInvalidInput(t *testing.T) {
+
+	_, err := CommonPrefixLen("10.0.0.1", "10.0.0")
+	if assert.Error(t, err, "10.0.0 is an invalid IP. An error should be thrown.") {
+
+		assert.Equal(t, consts.InvalidIPv4Error, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidIPv4Error)
+
+	}
+
+	_, err = CommonPrefix("10.0.0.0/24", "10.0.0.1")
+	if assert.Error(t, err, "10.0.0.0/24 is not an IP. An error should be thrown.") {
+
+		assert.Equal(t, consts.InvalidIPv4Error, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidIPv4Error)
+
+	}
+
+}
+
+// TestSummarizeIPs collapses a list of host IPs into CIDR ranges
+// Success Metric: The minimal list of CIDR ranges covering exactly the hosts is returned
+func TestSummarizeIPs(t *testing.T) {
+
+	IPs := []string{"10.0.0.3", "10.0.0.0", "10.0.0.1", "10.0.0.2", "10.0.0.4", "10.0.0.9", "10.0.0.1"}
+
+	summary, err := SummarizeIPs(IPs)
+	assert.Nil(t, err, "All inputs are valid IPs, they should be summarized.")
+	assert.Equal(t, []string{"10.0.0.0/30", "10.0.0.4/32", "10.0.0.9/32"}, toStrings(summary))
+
+	summary, err = SummarizeIPs(nil)
+	assert.Nil(t, err, "An empty list is a valid input.")
+	assert.Empty(t, summary)
+
+	_, err = SummarizeIPs([]string{"10.0.0.1", "10.0.0"})
+	if assert.Error(t, err, "10.0.0 is an invalid IP. An error should be thrown.") {
+
+		assert.Equal(t, consts.InvalidIPv4CIDRError, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidIPv4CIDRError)
+
+	}
+
+}
+
+// TestSummarizeRange converts IP ranges into CIDR ranges
+// Success Metric: The minimal list of CIDR ranges covering exactly the range is returned
+func TestSummarizeRange(t *testing.T) {
+
+	summary, err := SummarizeRange("10.0.0.0", "10.0.2.255")
+	assert.Nil(t, err, "Both inputs are valid IPs, the range should be summarized.")
+	assert.Equal(t, []string{"10.0.0.0/23", "10.0.2.0/24"}, toStrings(summary))
+
+	summary, err = SummarizeRange("10.0.0.255", "10.0.1.1")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"10.0.0.255/32", "10.0.1.0/31"}, toStrings(summary))
+
+	summary, err = SummarizeRange("0.0.0.0", "255.255.255.255")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"0.0.0.0/0"}, toStrings(summary))
+
+	_, err = SummarizeRange("10.0.1.0", "10.0.0.0")
+	if assert.Error(t, err, "The last IP is before the first IP. An error should be thrown.") {
+
+		assert.Equal(t, consts.InvalidIPRangeError, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidIPRangeError)
+
+	}
+
+	_, err = SummarizeRange("10.0.0.0", "10.0.0")
+	if assert.Error(t, err, "10.0.0 is an invalid IP. An error should be thrown.") {
+
+		assert.Equal(t, consts.InvalidIPv4Error, err.Error(), "Error thrown should be: \"%s\"", consts.InvalidIPv4Error)
+
+	}
+
+}
@@ -0,0 +1,104 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package utils
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+)
+
+// Error is the error type returned by this package, carrying a stable machine-readable code alongside the message
+// @field Code string: The error code, one of the codes defined in consts (e.g. CIDR_INVALID_INPUT)
+// @field Message string: The human-readable error message, stable for a given error
+// @field Detail string: The human-readable error message embedding the offending input, from the error templates
+type Error struct {
+	Code    string
+	Message string
+	Detail  string
+}
+
+// templates is the catalog of detailed error messages in use, keyed by error code, initialized from consts.ErrorTemplates
+// templatesLock guards templates, so that they can be replaced while errors are being created
+var (
+	templates     = copyTemplates(consts.ErrorTemplates)
+	templatesLock sync.RWMutex
+)
+
+// copyTemplates returns a copy of a catalog of error templates
+// @input catalog map[string]string: The error templates, keyed by error code
+// @returns map[string]string: The copy
+func copyTemplates(catalog map[string]string) map[string]string {
+
+	copied := make(map[string]string, len(catalog))
+	for code, template := range catalog {
+		copied[code] = template
+	}
+
+	return copied
+
+}
+
+// SetErrorTemplate replaces the template of the detailed error messages of an error code. It is safe to call while errors are being created
+// @input code string: The error code
+// @input template string: The template, taking the same parameters as the one in consts.ErrorTemplates. If empty, errors of the code use their message as detail
+func SetErrorTemplate(code string, template string) {
+
+	templatesLock.Lock()
+	defer templatesLock.Unlock()
+
+	if template == "" {
+		delete(templates, code)
+		return
+	}
+	templates[code] = template
+
+}
+
+// NewError creates a new error with a code and a message
+// @input code string: The error code
+// @input message string: The error message
+// @returns error: The new error
+func NewError(code string, message string) error {
+
+	return &Error{
+		Code:    code,
+		Message: message,
+		Detail:  message,
+	}
+
+}
+
+// NewDetailedError creates a new error with a code, a message, and a detail filled in from the template of the code
+// @input code string: The error code
+// @input message string: The error message
+// @input args ...interface{}: The parameters of the template, e.g. the offending input
+// @returns error: The new error. If the code has no template, its detail is the message
+func NewDetailedError(code string, message string, args ...interface{}) error {
+
+	templatesLock.RLock()
+	template, ok := templates[code]
+	templatesLock.RUnlock()
+
+	detail := message
+	if ok {
+		detail = fmt.Sprintf(template, args...)
+	}
+
+	return &Error{
+		Code:    code,
+		Message: message,
+		Detail:  detail,
+	}
+
+}
+
+// Error returns the message of the error, so that it satisfies the error interface
+// @returns string: The error message
+func (e *Error) Error() string {
+
+	return e.Message
+
+}
@@ -0,0 +1,53 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package utils
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewError creates an error with a code and a message
+// Success Metric: The message is returned by Error() and the code can be recovered with errors.As
+func TestNewError(t *testing.T) {
+
+	err := NewError(consts.InvalidInputCode, consts.InvalidIPv4CIDRError)
+	assert.Equal(t, consts.InvalidIPv4CIDRError, err.Error())
+
+	var codedErr *Error
+	if assert.True(t, errors.As(err, &codedErr), "The error should be of type *Error") {
+
+		assert.Equal(t, consts.InvalidInputCode, codedErr.Code)
+
+	}
+
+}
+
+// TestNewDetailedError creates errors with a detail from the template of their code
+// Success Metric: The detail embeds the parameters, the message is unchanged, and codes without a template use the message as detail
+func TestNewDetailedError(t *testing.T) {
+
+	err := NewDetailedError(consts.InvalidMaskCode, consts.InvalidMaskError, 36, 0, 32)
+	assert.Equal(t, consts.InvalidMaskError, err.Error(), "The message should not depend on the input")
+
+	var codedErr *Error
+	if assert.True(t, errors.As(err, &codedErr), "The error should be of type *Error") {
+
+		assert.Equal(t, consts.InvalidMaskCode, codedErr.Code)
+		assert.Equal(t, "mask 36 out of range [0,32]", codedErr.Detail)
+
+	}
+
+	err = NewDetailedError(consts.SplitNotPossibleCode, consts.NoMoreSplittingPossibleError, "10.0.0.0/32")
+	if assert.True(t, errors.As(err, &codedErr), "The error should be of type *Error") {
+
+		assert.Equal(t, consts.NoMoreSplittingPossibleError, codedErr.Detail, "Codes without a template should use the message")
+
+	}
+
+}
@@ -4,34 +4,87 @@
 package utils
 
 import (
-	"errors"
 	"math"
-	"strconv"
-	"strings"
+	"math/bits"
 
 	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
 )
 
-// GetNetmask takes the mask number as input and creates the netmask from it
+// netmasks and rangeLengths hold the netmask and range length for each of the 33 possible masks (/0 to /32), indexed by mask
+var (
+	netmasks     [consts.MaxBits + 1]uint32
+	rangeLengths [consts.MaxBits + 1]uint32
+)
+
+// init precomputes the netmask and range length tables, so that hot paths (parsing, splitting) only need table lookups
+func init() {
+
+	for mask := uint8(0); mask <= consts.MaxBits; mask++ {
+		netmasks[mask] = computeNetmask(mask)
+		rangeLengths[mask] = computeCIDRRangeLength(mask)
+	}
+
+}
+
+// computeNetmask takes the mask number as input and creates the netmask from it
 // @input mask uint8: The mask for the CIDR range
 // @returns uint32: The integer representation of the netmask
-func GetNetmask(mask uint8) uint32 {
+func computeNetmask(mask uint8) uint32 {
 
 	// Netmask = 32-bit number with all bits set, shifted left by (32-mask)
 	return consts.MaxUInt32 << (consts.MaxBits - mask)
 
 }
 
-// GetCIDRRangeLength calculates the number of IP addresses in that CIDR range
+// computeCIDRRangeLength calculates the number of IP addresses in that CIDR range
 // @input mask uint8: The mask for the CIDR range
 // @returns uint32: The length of the CIDR range
-func GetCIDRRangeLength(mask uint8) uint32 {
+func computeCIDRRangeLength(mask uint8) uint32 {
 
 	// Length of CIDR range = 2^(32-mask)
 	return uint32(math.Pow(float64(2), float64((consts.MaxBits - mask))))
 
 }
 
+// GetNetmask takes the mask number as input and returns the netmask for it
+// @input mask uint8: The mask for the CIDR range (0-32)
+// @returns uint32: The integer representation of the netmask
+func GetNetmask(mask uint8) uint32 {
+
+	return netmasks[mask]
+
+}
+
+// GetCIDRRangeLength returns the number of IP addresses in that CIDR range
+// @input mask uint8: The mask for the CIDR range (0-32)
+// @returns uint32: The length of the CIDR range
+func GetCIDRRangeLength(mask uint8) uint32 {
+
+	return rangeLengths[mask]
+
+}
+
+// GetCIDRRangeLength64 calculates the number of IP addresses in that CIDR range as a 64-bit value, so that /0 (2^32 addresses) can be represented
+// @input mask uint8: The mask for the CIDR range
+// @returns uint64: The length of the CIDR range
+func GetCIDRRangeLength64(mask uint8) uint64 {
+
+	// Length of CIDR range = 2^(32-mask), computed in 64 bits so that it does not overflow for /0
+	return uint64(1) << (consts.MaxBits - mask)
+
+}
+
+// GetLastIP calculates the last IP address of the CIDR range
+// @input ip uint32: The first IP address of the CIDR range in integer representation
+// @input netmask uint32: The netmask of the CIDR range
+// @returns uint32: Last IP in CIDR range
+func GetLastIP(ip uint32, netmask uint32) uint32 {
+
+	// A bitwise OR of the first IP with the inverted netmask sets all the host bits
+	return (ip | ^netmask)
+
+}
+
 // Standardize converts the IP to the first IP address of the CIDR range
 // @input ip uint32: The IP address in integer representation
 // @input netmask uint32: The netmask of the CIDR range
@@ -55,7 +108,18 @@ func CheckStandardized(ip uint32, netmask uint32) error {
 	}
 
 	// If above check fails, return an error
-	return errors.New(consts.NonStandardizedIPError)
+	return NewError(consts.NotStandardizedCode, consts.NonStandardizedIPError)
+
+}
+
+// GetCommonPrefixLength calculates the number of leading bits that two IP addresses have in common
+// @input ip1 uint32: The first IP address in integer representation
+// @input ip2 uint32: The second IP address in integer representation
+// @returns uint8: The length of the common prefix (0-32)
+func GetCommonPrefixLength(ip1 uint32, ip2 uint32) uint8 {
+
+	// The bits that differ are set in the XOR of the two IPs, so the common prefix ends at the first set bit
+	return uint8(bits.LeadingZeros32(ip1 ^ ip2))
 
 }
 
@@ -64,21 +128,136 @@ func CheckStandardized(ip uint32, netmask uint32) error {
 // @returns string: IP address in string representation
 func ConvertIPToString(ip uint32) string {
 
-	// IP addresses consist of 4 sections (a.b.c.d)
-	ipSections := make([]string, 4)
+	// The longest IP address string is 255.255.255.255 (15 characters), so a stack buffer of that size avoids any intermediate allocation
+	var buffer [15]byte
+
+	return string(AppendIPString(buffer[:0], ip))
+
+}
+
+// AppendIPString appends the string representation of an integer IP address to a byte slice, without allocating if the slice has enough capacity
+// @param dst []byte: The byte slice to append to
+// @param ip uint32: IP address in integer representation
+// @returns []byte: The extended byte slice
+func AppendIPString(dst []byte, ip uint32) []byte {
 
 	for i := 3; i >= 0; i-- {
 
-		// To generate each section, we go in reverse order (right to left)
-		// 1. Pull the least significant 8 bits into another var
-		// 2. Convert to int and save in the corresponding section
-		// 3. Shift the IP by 8 bits to the right
-		sectionInt := int(ip & consts.EightBits)
-		ipSections[i] = strconv.Itoa(sectionInt)
-		ip = ip >> consts.GroupSize
+		// To generate each section, we go from left to right
+		// 1. Shift the IP so that the section is in the least significant 8 bits, and pull those bits
+		// 2. Append the decimal digits of the section, without leading zeros
+		section := (ip >> (uint8(i) * consts.GroupSize)) & consts.EightBits
+
+		if section >= 100 {
+			dst = append(dst, byte('0'+section/100))
+		}
+		if section >= 10 {
+			dst = append(dst, byte('0'+(section/10)%10))
+		}
+		dst = append(dst, byte('0'+section%10))
+
+		if i > 0 {
+			dst = append(dst, '.')
+		}
+
+	}
+
+	return dst
+
+}
+
+// ParseIPUint32 converts the string representation of an IP address to its integer representation
+// @param ip string: IP address in format a.b.c.d
+// @returns uint32: IP address in integer representation
+// @returns error: If the string is not a valid IP address, an error is returned
+func ParseIPUint32(ip string) (uint32, error) {
+
+	value, length, ok := parseIP(ip)
+	if !ok || length != len(ip) {
+		return 0, NewError(consts.InvalidInputCode, consts.InvalidIPv4Error)
+	}
+
+	return value, nil
+
+}
+
+// ParseCIDRUint32 converts the string representation of a CIDR range to the integer representation of its IP and its mask, without allocating
+// The accepted format is the same as consts.IPv4CIDRRegex. The IP is returned as written, it is not standardized
+// @param cidr string: CIDR range in format a.b.c.d/e or a.b.c.d (in which case the mask is 32)
+// @returns uint32: IP address in integer representation
+// @returns uint8: The mask of the CIDR range
+// @returns bool: True if the string is a valid CIDR range
+func ParseCIDRUint32(cidr string) (uint32, uint8, bool) {
+
+	ip, position, ok := parseIP(cidr)
+	if !ok {
+		return 0, 0, false
+	}
+
+	if position == len(cidr) {
+		return ip, consts.MaxBits, true
+	}
+
+	// The mask is 1 or 2 digits without a leading zero, at most 32
+	maskString := cidr[position:]
+	if maskString[0] != '/' || len(maskString) < 2 || len(maskString) > 3 {
+		return 0, 0, false
+	}
+
+	mask := uint8(0)
+	for n := 1; n < len(maskString); n++ {
+		if maskString[n] < '0' || maskString[n] > '9' {
+			return 0, 0, false
+		}
+		mask = mask*10 + (maskString[n] - '0')
+	}
+
+	if (len(maskString) == 3 && maskString[1] == '0') || mask > consts.MaxBits {
+		return 0, 0, false
+	}
+
+	return ip, mask, true
+
+}
+
+// parseIP parses an IP address in format a.b.c.d at the start of a string, without allocating
+// Each section is 1 to 3 decimal digits with a value of at most 255, matching the sections accepted by consts.IPv4CIDRRegex
+// @param s string: The string starting with the IP address
+// @returns uint32: IP address in integer representation
+// @returns int: Number of characters of the string that make up the IP address
+// @returns bool: True if the string starts with a valid IP address
+func parseIP(s string) (uint32, int, bool) {
+
+	ip := uint32(0)
+	position := 0
+
+	for section := 0; section < 4; section++ {
+
+		// Every section except the first is preceded by a dot
+		if section > 0 {
+			if position >= len(s) || s[position] != '.' {
+				return 0, 0, false
+			}
+			position++
+		}
+
+		// Read up to 3 digits
+		value := uint32(0)
+		digits := 0
+		for position < len(s) && digits < 3 && s[position] >= '0' && s[position] <= '9' {
+			value = value*10 + uint32(s[position]-'0')
+			position++
+			digits++
+		}
+
+		if digits == 0 || value > consts.EightBits {
+			return 0, 0, false
+		}
+
+		ip = ip<<consts.GroupSize | value
 
 	}
 
-	return strings.Join(ipSections, ".")
+	return ip, position, true
 
 }
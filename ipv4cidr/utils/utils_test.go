@@ -45,6 +45,35 @@ func TestGetCIDRRangeLength(t *testing.T) {
 
 }
 
+// TestGetCIDRRangeLength64 calculates the 64-bit range size for /32 to /0 block sizes
+// Success Metric: The correct range size is calculated for each value, including 2^32 for /0
+func TestGetCIDRRangeLength64(t *testing.T) {
+
+	rangeLength := uint64(1)
+	var mask uint8
+
+	for mask = 32; mask <= 32; mask-- {
+
+		assert.Equal(t, rangeLength, GetCIDRRangeLength64(mask), "Range length for %d should be %d", mask, rangeLength)
+		rangeLength *= 2
+
+	}
+
+}
+
+// TestGetLastIP uses the first IP address and netmask to calculate the last IP address in the CIDR block
+// Success Metric: The last IP address of the CIDR block is returned
+func TestGetLastIP(t *testing.T) {
+
+	firstIP := uint32(168427520) // 10.10.0.0
+	lastIP := uint32(168431615)  // 10.10.15.255
+
+	assert.Equal(t, lastIP, GetLastIP(firstIP, GetNetmask(20)), "The last IP of 10.10.0.0/20 is 10.10.15.255")
+	assert.Equal(t, firstIP, GetLastIP(firstIP, GetNetmask(32)), "The last IP of 10.10.0.0/32 is 10.10.0.0")
+	assert.Equal(t, consts.MaxUInt32, GetLastIP(0, GetNetmask(0)), "The last IP of 0.0.0.0/0 is 255.255.255.255")
+
+}
+
 // TestStandardize uses the IP address and CIDR block number to calculate the first IP address in the CIDR block
 // Success Metric: The first IP address of the CIDR block is returned
 func TestStandardize(t *testing.T) {
@@ -78,6 +107,20 @@ func TestCheckStandarized(t *testing.T) {
 
 }
 
+// TestGetCommonPrefixLength calculates the number of leading bits two IP addresses share
+// Success Metric: The correct prefix length is returned, including 32 for equal IPs and 0 for IPs differing in the first bit
+func TestGetCommonPrefixLength(t *testing.T) {
+
+	IP1 := uint32(168427520)  // 10.10.0.0
+	IP2 := uint32(168427620)  // 10.10.0.100
+	IP3 := uint32(3232235520) // 192.168.0.0
+
+	assert.Equal(t, uint8(25), GetCommonPrefixLength(IP1, IP2), "10.10.0.0 and 10.10.0.100 share 25 bits")
+	assert.Equal(t, uint8(32), GetCommonPrefixLength(IP1, IP1), "Equal IPs share all 32 bits")
+	assert.Equal(t, uint8(0), GetCommonPrefixLength(IP1, IP3), "10.10.0.0 and 192.168.0.0 differ in the first bit")
+
+}
+
 // TestConvertIPToString converts an IP in integer format to string format
 // Success Metric: IP is successfully converted to its string representation
 func TestConvertIPToString(t *testing.T) {
@@ -86,5 +129,142 @@ func TestConvertIPToString(t *testing.T) {
 	IP2 := uint32(168427620) // 10.10.0.100
 	assert.Equal(t, "10.10.0.0", ConvertIPToString(IP1))
 	assert.Equal(t, "10.10.0.100", ConvertIPToString(IP2))
+	assert.Equal(t, "0.0.0.0", ConvertIPToString(0))
+	assert.Equal(t, "255.255.255.255", ConvertIPToString(consts.MaxUInt32))
+
+}
+
+// TestAppendIPString appends the string representation of an IP to a byte slice
+// Success Metric: The IP is appended after the existing content
+func TestAppendIPString(t *testing.T) {
+
+	IP := uint32(3232235786) // 192.168.1.10
+	assert.Equal(t, "ip=192.168.1.10", string(AppendIPString([]byte("ip="), IP)))
+
+}
+
+// TestParseIPUint32 converts IPs in string format to integer format
+// Success Metric: Valid IPs are converted, invalid IPs throw an error
+func TestParseIPUint32(t *testing.T) {
+
+	IP, err := ParseIPUint32("10.10.0.100")
+	assert.Nil(t, err, "10.10.0.100 is a valid IP, it should be converted.")
+	assert.Equal(t, uint32(168427620), IP)
+
+	IP, err = ParseIPUint32("255.255.255.255")
+	assert.Nil(t, err, "255.255.255.255 is a valid IP, it should be converted.")
+	assert.Equal(t, consts.MaxUInt32, IP)
+
+	testInputs := []string{"", "10.10.0", "10.10.0.256", "10.10.0.1/32", "10.10.0.1.", "10..0.1", "10.10.0.1000", "a.b.c.d", " 10.10.0.1"}
+	for _, input := range testInputs {
+
+		_, err := ParseIPUint32(input)
+		if assert.Error(t, err, "%s is an invalid IP. An error should be thrown.", input) {
+
+			assert.Equal(t, consts.InvalidIPv4Error, err.Error(), "For input %s, Error thrown should be: \"%s\"", input, consts.InvalidIPv4Error)
+
+		}
+
+	}
+
+}
+
+// TestParseCIDRUint32 converts CIDR ranges in string format to an integer IP and a mask
+// Success Metric: Valid CIDR ranges are converted, and the same inputs as consts.IPv4CIDRRegex are rejected
+func TestParseCIDRUint32(t *testing.T) {
+
+	IP, mask, ok := ParseCIDRUint32("10.10.0.100/20")
+	assert.True(t, ok, "10.10.0.100/20 is a valid CIDR range, it should be converted.")
+	assert.Equal(t, uint32(168427620), IP)
+	assert.Equal(t, uint8(20), mask)
+
+	IP, mask, ok = ParseCIDRUint32("10.10.0.100")
+	assert.True(t, ok, "10.10.0.100 is a valid CIDR range, it should be converted.")
+	assert.Equal(t, uint32(168427620), IP)
+	assert.Equal(t, uint8(32), mask)
+
+	_, mask, ok = ParseCIDRUint32("0.0.0.0/0")
+	assert.True(t, ok, "0.0.0.0/0 is a valid CIDR range, it should be converted.")
+	assert.Equal(t, uint8(0), mask)
+
+	testInputs := []string{"200.200.200.256/23", "10.10.0.0/33", "10.10.0.0/123", "10.10.0.0/", "10.20.3.", "10.20.3", "10./3", "10.10.0.0/05", "10.10.0.0/3a", "10.10.0.0-24"}
+	for _, input := range testInputs {
+
+		_, _, ok := ParseCIDRUint32(input)
+		assert.False(t, ok, "%s is an invalid CIDR range, it should not be converted.", input)
+
+	}
+
+}
+
+// BenchmarkConvertIPToString measures converting an IP in integer format to string format
+func BenchmarkConvertIPToString(b *testing.B) {
+
+	for n := 0; n < b.N; n++ {
+		ConvertIPToString(uint32(n))
+	}
+
+}
+
+// BenchmarkAppendIPString measures appending IPs to a reused byte slice
+func BenchmarkAppendIPString(b *testing.B) {
+
+	buffer := make([]byte, 0, 15)
+	for n := 0; n < b.N; n++ {
+		buffer = AppendIPString(buffer[:0], uint32(n))
+	}
+
+}
+
+// BenchmarkParseIPUint32 measures converting an IP in string format to integer format
+func BenchmarkParseIPUint32(b *testing.B) {
+
+	for n := 0; n < b.N; n++ {
+		ParseIPUint32("192.168.100.200")
+	}
+
+}
+
+// BenchmarkGetNetmask measures the table lookup of the netmask for every mask
+func BenchmarkGetNetmask(b *testing.B) {
+
+	for n := 0; n < b.N; n++ {
+		for mask := uint8(0); mask <= consts.MaxBits; mask++ {
+			GetNetmask(mask)
+		}
+	}
+
+}
+
+// BenchmarkComputeNetmask measures the computation of the netmask for every mask, as a baseline for BenchmarkGetNetmask
+func BenchmarkComputeNetmask(b *testing.B) {
+
+	for n := 0; n < b.N; n++ {
+		for mask := uint8(0); mask <= consts.MaxBits; mask++ {
+			computeNetmask(mask)
+		}
+	}
+
+}
+
+// BenchmarkGetCIDRRangeLength measures the table lookup of the range length for every mask
+func BenchmarkGetCIDRRangeLength(b *testing.B) {
+
+	for n := 0; n < b.N; n++ {
+		for mask := uint8(0); mask <= consts.MaxBits; mask++ {
+			GetCIDRRangeLength(mask)
+		}
+	}
+
+}
+
+// BenchmarkComputeCIDRRangeLength measures the computation of the range length for every mask, as a baseline for BenchmarkGetCIDRRangeLength
+func BenchmarkComputeCIDRRangeLength(b *testing.B) {
+
+	for n := 0; n < b.N; n++ {
+		for mask := uint8(0); mask <= consts.MaxBits; mask++ {
+			computeCIDRRangeLength(mask)
+		}
+	}
 
 }
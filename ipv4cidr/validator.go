@@ -0,0 +1,254 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+)
+
+// Violation describes a validation rule that a CIDR range does not satisfy
+// @field Rule string: Name of the rule that was violated
+// @field Message string: Human-readable description of the violation
+type Violation struct {
+	Rule    string
+	Message string
+}
+
+// Rule is a single policy check that can be evaluated against a CIDR range
+type Rule interface {
+
+	// Check evaluates the rule against a CIDR range
+	// @input cidr *IPv4CIDR: The CIDR range to check
+	// @returns *Violation: If the rule is violated, the violation is returned. Else, return value is nil
+	Check(cidr *IPv4CIDR) *Violation
+}
+
+// Validator evaluates CIDR ranges against a set of policy rules
+// @field rules []Rule: The rules to evaluate, in order
+type Validator struct {
+	rules []Rule
+}
+
+// NewValidator instantiates a new Validator object with the given rules and returns it
+// @param rules ...Rule: The rules to evaluate
+// @returns *Validator: Pointer to the new Validator object
+func NewValidator(rules ...Rule) *Validator {
+
+	return &Validator{
+		rules: rules,
+	}
+
+}
+
+// Validate evaluates every rule against a CIDR range
+// @input cidr *IPv4CIDR: The CIDR range to validate
+// @returns []Violation: All the violated rules, in the order the rules were configured. Empty if the CIDR range is valid
+func (v *Validator) Validate(cidr *IPv4CIDR) []Violation {
+
+	violations := make([]Violation, 0)
+	for _, rule := range v.rules {
+		if violation := rule.Check(cidr); violation != nil {
+			violations = append(violations, *violation)
+		}
+	}
+
+	return violations
+
+}
+
+// minPrefixLength is the rule returned by MinPrefixLength
+type minPrefixLength struct {
+	mask uint8
+}
+
+// MinPrefixLength creates a rule requiring the mask to be at least the given value, i.e. the CIDR range to be no larger than that size
+// @param mask uint8: The smallest allowed mask
+// @returns Rule: The new rule
+func MinPrefixLength(mask uint8) Rule {
+
+	return &minPrefixLength{mask: mask}
+
+}
+
+// Check evaluates the rule against a CIDR range
+// @input cidr *IPv4CIDR: The CIDR range to check
+// @returns *Violation: If the mask is too short, the violation is returned. Else, return value is nil
+func (r *minPrefixLength) Check(cidr *IPv4CIDR) *Violation {
+
+	if cidr.mask >= r.mask {
+		return nil
+	}
+
+	return &Violation{
+		Rule:    consts.MinPrefixLengthRule,
+		Message: fmt.Sprintf(consts.MinPrefixLengthViolation, cidr.mask, r.mask),
+	}
+
+}
+
+// maxPrefixLength is the rule returned by MaxPrefixLength
+type maxPrefixLength struct {
+	mask uint8
+}
+
+// MaxPrefixLength creates a rule requiring the mask to be at most the given value, i.e. the CIDR range to be no smaller than that size
+// @param mask uint8: The largest allowed mask
+// @returns Rule: The new rule
+func MaxPrefixLength(mask uint8) Rule {
+
+	return &maxPrefixLength{mask: mask}
+
+}
+
+// Check evaluates the rule against a CIDR range
+// @input cidr *IPv4CIDR: The CIDR range to check
+// @returns *Violation: If the mask is too long, the violation is returned. Else, return value is nil
+func (r *maxPrefixLength) Check(cidr *IPv4CIDR) *Violation {
+
+	if cidr.mask <= r.mask {
+		return nil
+	}
+
+	return &Violation{
+		Rule:    consts.MaxPrefixLengthRule,
+		Message: fmt.Sprintf(consts.MaxPrefixLengthViolation, cidr.mask, r.mask),
+	}
+
+}
+
+// within is the rule returned by Within
+type within struct {
+	supernets []*IPv4CIDR
+}
+
+// Within creates a rule requiring the CIDR range to be entirely contained in at least one of the given supernets
+// @param supernets ...*IPv4CIDR: The allowed address space
+// @returns Rule: The new rule
+func Within(supernets ...*IPv4CIDR) Rule {
+
+	return &within{supernets: supernets}
+
+}
+
+// Check evaluates the rule against a CIDR range
+// @input cidr *IPv4CIDR: The CIDR range to check
+// @returns *Violation: If the CIDR range is not within any supernet, the violation is returned. Else, return value is nil
+func (r *within) Check(cidr *IPv4CIDR) *Violation {
+
+	for _, supernet := range r.supernets {
+		if supernet.contains(cidr) {
+			return nil
+		}
+	}
+
+	return &Violation{
+		Rule:    consts.WithinRule,
+		Message: fmt.Sprintf(consts.WithinViolation, cidr.ToString(), joinCIDRs(r.supernets)),
+	}
+
+}
+
+// notOverlapping is the rule returned by NotOverlapping
+type notOverlapping struct {
+	reserved []*IPv4CIDR
+}
+
+// NotOverlapping creates a rule requiring the CIDR range not to share any address with the given reserved ranges
+// @param reserved ...*IPv4CIDR: The reserved address space
+// @returns Rule: The new rule
+func NotOverlapping(reserved ...*IPv4CIDR) Rule {
+
+	return &notOverlapping{reserved: reserved}
+
+}
+
+// Check evaluates the rule against a CIDR range
+// @input cidr *IPv4CIDR: The CIDR range to check
+// @returns *Violation: If the CIDR range overlaps a reserved range, the violation for the first such range is returned. Else, return value is nil
+func (r *notOverlapping) Check(cidr *IPv4CIDR) *Violation {
+
+	for _, reserved := range r.reserved {
+		if intersect(reserved, cidr) != nil {
+			return &Violation{
+				Rule:    consts.NotOverlappingRule,
+				Message: fmt.Sprintf(consts.NotOverlappingViolation, cidr.ToString(), reserved.ToString()),
+			}
+		}
+	}
+
+	return nil
+
+}
+
+// private is the rule returned by Private
+type private struct{}
+
+// Private creates a rule requiring the CIDR range to be within the private (RFC 1918) address space
+// @returns Rule: The new rule
+func Private() Rule {
+
+	return &private{}
+
+}
+
+// Check evaluates the rule against a CIDR range
+// @input cidr *IPv4CIDR: The CIDR range to check
+// @returns *Violation: If the CIDR range is not private, the violation is returned. Else, return value is nil
+func (r *private) Check(cidr *IPv4CIDR) *Violation {
+
+	if cidr.IsPrivate() {
+		return nil
+	}
+
+	return &Violation{
+		Rule:    consts.PrivateRule,
+		Message: fmt.Sprintf(consts.PrivateViolation, cidr.ToString()),
+	}
+
+}
+
+// privateOrShared is the rule returned by PrivateOrShared
+type privateOrShared struct{}
+
+// PrivateOrShared creates a rule requiring the CIDR range to be within the private (RFC 1918) or the shared (RFC 6598) address space
+// Use it instead of Private when the carrier-grade NAT range 100.64.0.0/10 is an allowed internal pool
+// @returns Rule: The new rule
+func PrivateOrShared() Rule {
+
+	return &privateOrShared{}
+
+}
+
+// Check evaluates the rule against a CIDR range
+// @input cidr *IPv4CIDR: The CIDR range to check
+// @returns *Violation: If the CIDR range is neither private nor shared, the violation is returned. Else, return value is nil
+func (r *privateOrShared) Check(cidr *IPv4CIDR) *Violation {
+
+	if cidr.IsPrivate() || cidr.IsSharedAddressSpace() {
+		return nil
+	}
+
+	return &Violation{
+		Rule:    consts.PrivateOrSharedRule,
+		Message: fmt.Sprintf(consts.PrivateOrSharedViolation, cidr.ToString()),
+	}
+
+}
+
+// joinCIDRs converts a list of CIDR ranges into a single comma-separated string
+// @input cidrs []*IPv4CIDR: The list of CIDR ranges
+// @returns string: The CIDR ranges in format [a.b.c.d/e, ...]
+func joinCIDRs(cidrs []*IPv4CIDR) string {
+
+	strs := make([]string, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		strs = append(strs, cidr.ToString())
+	}
+
+	return "[" + strings.Join(strs, ", ") + "]"
+
+}
@@ -0,0 +1,79 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package ipv4cidr
+
+import (
+	"testing"
+
+	"github.com/microsoft/go-cidr-manager/ipv4cidr/consts"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestValidatorValidCIDR validates a CIDR range that satisfies every rule
+// Success Metric: No violations are returned
+func TestValidatorValidCIDR(t *testing.T) {
+
+	supernet, _ := NewIPv4CIDR("10.0.0.0/16", false)
+	reserved, _ := NewIPv4CIDR("10.0.255.0/24", false)
+	validator := NewValidator(MinPrefixLength(20), MaxPrefixLength(28), Within(supernet), NotOverlapping(reserved), Private())
+
+	CIDR, _ := NewIPv4CIDR("10.0.1.0/24", false)
+	assert.Empty(t, validator.Validate(CIDR), "10.0.1.0/24 satisfies every rule.")
+
+}
+
+// TestValidatorInvalidCIDR validates CIDR ranges that violate some of the rules
+// Success Metric: All violations are returned, in the order the rules were configured
+func TestValidatorInvalidCIDR(t *testing.T) {
+
+	supernet, _ := NewIPv4CIDR("10.0.0.0/16", false)
+	reserved, _ := NewIPv4CIDR("10.0.255.0/24", false)
+	validator := NewValidator(MinPrefixLength(20), MaxPrefixLength(28), Within(supernet), NotOverlapping(reserved), Private())
+
+	CIDR, _ := NewIPv4CIDR("10.0.255.16/29", false)
+	violations := validator.Validate(CIDR)
+	if assert.Len(t, violations, 2) {
+
+		assert.Equal(t, consts.MaxPrefixLengthRule, violations[0].Rule)
+		assert.Equal(t, "Mask /29 is longer than the maximum allowed mask /28", violations[0].Message)
+		assert.Equal(t, consts.NotOverlappingRule, violations[1].Rule)
+		assert.Equal(t, "10.0.255.16/29 overlaps the reserved range 10.0.255.0/24", violations[1].Message)
+
+	}
+
+	CIDR, _ = NewIPv4CIDR("8.0.0.0/8", false)
+	violations = validator.Validate(CIDR)
+	if assert.Len(t, violations, 3) {
+
+		assert.Equal(t, consts.MinPrefixLengthRule, violations[0].Rule)
+		assert.Equal(t, consts.WithinRule, violations[1].Rule)
+		assert.Equal(t, "8.0.0.0/8 is not within any of the allowed ranges [10.0.0.0/16]", violations[1].Message)
+		assert.Equal(t, consts.PrivateRule, violations[2].Rule)
+
+	}
+
+}
+
+// TestValidatorPrivateOrShared validates CIDR ranges in the private, shared and public address space
+// Success Metric: Private and shared CIDR ranges pass, and public ones are reported
+func TestValidatorPrivateOrShared(t *testing.T) {
+
+	validator := NewValidator(PrivateOrShared())
+
+	for _, input := range []string{"10.0.0.0/16", "100.64.0.0/10", "100.127.255.0/24"} {
+		CIDR, _ := NewIPv4CIDR(input, false)
+		assert.Empty(t, validator.Validate(CIDR), "%s is private or shared", input)
+	}
+
+	CIDR, _ := NewIPv4CIDR("100.128.0.0/24", false)
+	violations := validator.Validate(CIDR)
+	if assert.Len(t, violations, 1) {
+
+		assert.Equal(t, consts.PrivateOrSharedRule, violations[0].Rule)
+		assert.Equal(t, "100.128.0.0/24 is not within the private (RFC 1918) or shared (RFC 6598) address space", violations[0].Message)
+
+	}
+
+}